@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"slices"
 	"uPIMulator/src/assembler/prim"
 	"uPIMulator/src/misc"
 )
@@ -35,6 +36,17 @@ func (this *Assembler) Init(command_line_parser *misc.CommandLineParser) {
 
 	this.num_tasklets = int(command_line_parser.IntParameter("num_tasklets"))
 
+	this.InitAssemblables()
+
+	if assemblable, found := this.assemblables[this.benchmark]; found {
+		assemblable.Init(command_line_parser)
+	} else {
+		err := errors.New("assemblable is not found")
+		panic(err)
+	}
+}
+
+func (this *Assembler) InitAssemblables() {
 	this.assemblables = make(map[string]Assemblable, 0)
 
 	this.assemblables["BS"] = new(prim.Bs)
@@ -50,13 +62,17 @@ func (this *Assembler) Init(command_line_parser *misc.CommandLineParser) {
 	this.assemblables["TS"] = new(prim.Ts)
 	this.assemblables["UNI"] = new(prim.Uni)
 	this.assemblables["VA"] = new(prim.Va)
+}
 
-	if assemblable, found := this.assemblables[this.benchmark]; found {
-		assemblable.Init(command_line_parser)
-	} else {
-		err := errors.New("assemblable is not found")
-		panic(err)
+// Benchmarks returns the names of the benchmarks that the assembler can assemble.
+func (this *Assembler) Benchmarks() []string {
+	benchmarks := make([]string, 0)
+	for benchmark := range this.assemblables {
+		benchmarks = append(benchmarks, benchmark)
 	}
+
+	slices.Sort(benchmarks)
+	return benchmarks
 }
 
 func (this *Assembler) Assemble() {
@@ -5,6 +5,8 @@ import (
 	"sync"
 )
 
+// A ThreadPool runs each of its jobs on a goroutine of its own. Like the WorkerPool, it keeps what
+// the jobs raise, and Start raises the first of it again on the calling goroutine.
 type ThreadPool struct {
 	num_threads  int
 	channel_size int
@@ -12,6 +14,9 @@ type ThreadPool struct {
 	jobs []Job
 
 	wg sync.WaitGroup
+
+	recovered interface{}
+	mutex     sync.Mutex
 }
 
 func (this *ThreadPool) Init(num_threads int) {
@@ -23,6 +28,8 @@ func (this *ThreadPool) Init(num_threads int) {
 	this.num_threads = num_threads
 
 	this.jobs = make([]Job, 0)
+
+	this.recovered = nil
 }
 
 func (this *ThreadPool) Enque(job Job) {
@@ -35,9 +42,26 @@ func (this *ThreadPool) Start() {
 		go this.Dispatch(job)
 	}
 	this.wg.Wait()
+
+	if this.recovered != nil {
+		recovered := this.recovered
+		this.recovered = nil
+		panic(recovered)
+	}
 }
 
 func (this *ThreadPool) Dispatch(job Job) {
 	defer this.wg.Done()
+	defer this.Recover()
 	job.Execute()
 }
+
+func (this *ThreadPool) Recover() {
+	if recovered := recover(); recovered != nil {
+		this.mutex.Lock()
+		if this.recovered == nil {
+			this.recovered = recovered
+		}
+		this.mutex.Unlock()
+	}
+}
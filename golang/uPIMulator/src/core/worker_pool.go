@@ -0,0 +1,135 @@
+package core
+
+import (
+	"errors"
+	"sync"
+)
+
+// WorkerPool keeps its jobs and workers across Starts. Each job is pinned to a worker when it is
+// enqueued, and every Start runs all jobs once and returns only when every worker has finished. A
+// panic in a worker would bring the process down before anyone could recover it, so the worker
+// keeps what its jobs raise, and Start raises the first of it again on the calling goroutine.
+type WorkerPool struct {
+	num_workers int
+
+	jobs [][]Job
+
+	num_enqueued_jobs int
+	is_started        bool
+
+	channels []chan bool
+	wg       sync.WaitGroup
+
+	recovered interface{}
+	mutex     sync.Mutex
+}
+
+func (this *WorkerPool) Init(num_workers int) {
+	if num_workers <= 0 {
+		err := errors.New("num workers <= 0")
+		panic(err)
+	}
+
+	this.num_workers = num_workers
+
+	this.jobs = make([][]Job, num_workers)
+	for i := 0; i < num_workers; i++ {
+		this.jobs[i] = make([]Job, 0)
+	}
+
+	this.num_enqueued_jobs = 0
+	this.is_started = false
+
+	this.channels = make([]chan bool, 0)
+
+	this.recovered = nil
+}
+
+func (this *WorkerPool) Fini() {
+	for _, channel := range this.channels {
+		close(channel)
+	}
+
+	this.channels = make([]chan bool, 0)
+	this.is_started = false
+}
+
+func (this *WorkerPool) Enque(job Job) {
+	if this.is_started {
+		err := errors.New("worker pool is already started")
+		panic(err)
+	}
+
+	worker_id := this.num_enqueued_jobs % this.num_workers
+	this.jobs[worker_id] = append(this.jobs[worker_id], job)
+
+	this.num_enqueued_jobs++
+}
+
+func (this *WorkerPool) Start() {
+	if !this.is_started {
+		this.Spawn()
+	}
+
+	if len(this.channels) == 0 {
+		for _, job := range this.jobs[0] {
+			job.Execute()
+		}
+		return
+	}
+
+	this.wg.Add(len(this.channels))
+	for _, channel := range this.channels {
+		channel <- true
+	}
+	this.wg.Wait()
+
+	if this.recovered != nil {
+		recovered := this.recovered
+		this.recovered = nil
+		panic(recovered)
+	}
+}
+
+func (this *WorkerPool) Spawn() {
+	this.is_started = true
+
+	if this.num_enqueued_jobs <= 1 || this.num_workers == 1 {
+		return
+	}
+
+	for worker_id := 0; worker_id < this.num_workers; worker_id++ {
+		if len(this.jobs[worker_id]) > 0 {
+			channel := make(chan bool)
+			this.channels = append(this.channels, channel)
+
+			go this.Work(this.jobs[worker_id], channel)
+		}
+	}
+}
+
+func (this *WorkerPool) Work(jobs []Job, channel chan bool) {
+	for range channel {
+		this.Execute(jobs)
+
+		this.wg.Done()
+	}
+}
+
+func (this *WorkerPool) Execute(jobs []Job) {
+	defer this.Recover()
+
+	for _, job := range jobs {
+		job.Execute()
+	}
+}
+
+func (this *WorkerPool) Recover() {
+	if recovered := recover(); recovered != nil {
+		this.mutex.Lock()
+		if this.recovered == nil {
+			this.recovered = recovered
+		}
+		this.mutex.Unlock()
+	}
+}
@@ -23,6 +23,9 @@ type Executable struct {
 
 	sections    map[*Section]bool
 	cur_section *Section
+
+	functions map[string]bool
+	locs      map[int64]*SourceLine
 }
 
 func (this *Executable) Init(name string) {
@@ -34,6 +37,9 @@ func (this *Executable) Init(name string) {
 	this.liveness.Init()
 
 	this.sections = make(map[*Section]bool, 0)
+
+	this.functions = make(map[string]bool, 0)
+	this.locs = make(map[int64]*SourceLine, 0)
 }
 
 func (this *Executable) Name() string {
@@ -106,6 +112,34 @@ func (this *Executable) DumpAddresses(path string) {
 	file_dumper.WriteLines(lines)
 }
 
+func (this *Executable) DumpLines(path string) {
+	source_lines := this.SourceLines()
+
+	addresses := make([]int64, 0)
+	for address, _ := range source_lines {
+		addresses = append(addresses, address)
+	}
+	sort.Slice(addresses, func(i int, j int) bool { return addresses[i] < addresses[j] })
+
+	lines := make([]string, 0)
+	for _, address := range addresses {
+		source_line := source_lines[address]
+
+		line := fmt.Sprintf(
+			"%d: %s %s:%d",
+			address,
+			source_line.Function(),
+			source_line.File(),
+			source_line.LineNumber(),
+		)
+		lines = append(lines, line)
+	}
+
+	file_dumper := new(misc.FileDumper)
+	file_dumper.Init(path)
+	file_dumper.WriteLines(lines)
+}
+
 func (this *Executable) DumpAtomic(path string) {
 	atomic_byte_stream := this.AtomicByteStream()
 
@@ -238,6 +272,85 @@ func (this *Executable) Addresses() map[string]int64 {
 	return addresses
 }
 
+func (this *Executable) AddFunction(function string) {
+	this.functions[function] = true
+}
+
+// AddLoc records that the instructions from the address on are compiled from the source line,
+// until the next .loc directive or the end of the function.
+func (this *Executable) AddLoc(address int64, file string, line_number int) {
+	source_line := new(SourceLine)
+	source_line.Init(file, line_number, "")
+
+	this.locs[address] = source_line
+}
+
+// SourceLines maps the address of every instruction in the IRAM to its source line and to the
+// function whose label precedes it.
+func (this *Executable) SourceLines() map[int64]*SourceLine {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	iram_data_size := int64(config_loader.IramDataWidth() / 8)
+
+	iram_sections := this.Sort(
+		config_loader.IramOffset(),
+		config_loader.IramOffset()+config_loader.IramSize(),
+	)
+
+	function_labels := make([]*Label, 0)
+	for _, iram_section := range iram_sections {
+		for _, label := range iram_section.Labels() {
+			if _, found := this.functions[label.Name()]; found {
+				function_labels = append(function_labels, label)
+			}
+		}
+	}
+	sort.SliceStable(function_labels, func(i int, j int) bool {
+		return function_labels[i].Address() < function_labels[j].Address()
+	})
+
+	loc_addresses := make([]int64, 0)
+	for address, _ := range this.locs {
+		loc_addresses = append(loc_addresses, address)
+	}
+	sort.Slice(loc_addresses, func(i int, j int) bool { return loc_addresses[i] < loc_addresses[j] })
+
+	source_lines := make(map[int64]*SourceLine, 0)
+	for _, iram_section := range iram_sections {
+		begin_address := iram_section.Address()
+		end_address := iram_section.Address() + iram_section.Size()
+
+		for address := begin_address; address < end_address; address += iram_data_size {
+			function := "??"
+			function_address := begin_address
+
+			i := sort.Search(len(function_labels), func(i int) bool {
+				return function_labels[i].Address() > address
+			})
+			if i > 0 && function_labels[i-1].Address() >= begin_address {
+				function = function_labels[i-1].Name()
+				function_address = function_labels[i-1].Address()
+			}
+
+			file := "??"
+			line_number := 0
+
+			j := sort.Search(len(loc_addresses), func(j int) bool { return loc_addresses[j] > address })
+			if j > 0 && loc_addresses[j-1] >= function_address {
+				file = this.locs[loc_addresses[j-1]].File()
+				line_number = this.locs[loc_addresses[j-1]].LineNumber()
+			}
+
+			source_line := new(SourceLine)
+			source_line.Init(file, line_number, function)
+
+			source_lines[address] = source_line
+		}
+	}
+	return source_lines
+}
+
 func (this *Executable) AtomicByteStream() *encoding.ByteStream {
 	config_loader := new(misc.ConfigLoader)
 	config_loader.Init()
@@ -510,7 +510,7 @@ func (this *Instruction) InitZrif(
 	this.imm = new(word.Immediate)
 	this.imm.Init(word.SIGNED, 27, imm)
 
-	false_cc := new(cc.LogSetCc)
+	false_cc := new(cc.FalseCc)
 	false_cc.Init(condition)
 
 	this.condition = new(cc.Condition)
@@ -2784,7 +2784,7 @@ func (this *Instruction) EncodeSRrr(word_ *word.Word) {
 		panic(err)
 	}
 
-	if this.suffix != RRR {
+	if this.suffix != S_RRR && this.suffix != U_RRR {
 		err := errors.New("suffix is not S_RRR nor U_RRR")
 		panic(err)
 	}
@@ -4855,7 +4855,7 @@ func (this *Instruction) DecodeSRrr(word_ *word.Word) {
 		panic(err)
 	}
 
-	if this.suffix != S_RRR {
+	if this.suffix != S_RRR && this.suffix != U_RRR {
 		err := errors.New("suffix is not S_RRR nor U_RRR")
 		panic(err)
 	}
@@ -4884,11 +4884,11 @@ func (this *Instruction) DecodeSRrrc(word_ *word.Word) {
 		panic(err)
 	}
 
-	rc_begin := this.SuffixEnd()
-	rc_end := rc_begin + this.RegisterWidth()
-	this.rc = this.DecodeGpRegDescriptor(word_, rc_begin, rc_end)
+	dc_begin := this.SuffixEnd()
+	dc_end := dc_begin + this.RegisterWidth()
+	this.dc = this.DecodePairRegDescriptor(word_, dc_begin, dc_end)
 
-	ra_begin := rc_end
+	ra_begin := dc_end
 	ra_end := ra_begin + this.RegisterWidth()
 	this.ra = this.DecodeSrcRegDescriptor(word_, ra_begin, ra_end)
 
@@ -5561,11 +5561,11 @@ func (this *Instruction) DecodeZirc(word_ *word.Word) {
 	}
 
 	imm_begin := this.SuffixEnd()
-	imm_end := imm_begin + 24
+	imm_end := imm_begin + 27
 	imm := this.DecodeImm(word_, imm_begin, imm_end, word.SIGNED)
 
 	this.imm = new(word.Immediate)
-	this.imm.Init(word.SIGNED, 24, imm)
+	this.imm.Init(word.SIGNED, 27, imm)
 
 	ra_begin := imm_end
 	ra_end := ra_begin + this.RegisterWidth()
@@ -5590,11 +5590,11 @@ func (this *Instruction) DecodeZirci(word_ *word.Word) {
 	}
 
 	imm_begin := this.SuffixEnd()
-	imm_end := imm_begin + 8
+	imm_end := imm_begin + 11
 	imm := this.DecodeImm(word_, imm_begin, imm_end, word.SIGNED)
 
 	this.imm = new(word.Immediate)
-	this.imm.Init(word.SIGNED, 8, imm)
+	this.imm.Init(word.SIGNED, 11, imm)
 
 	ra_begin := imm_end
 	ra_end := ra_begin + this.RegisterWidth()
@@ -7663,13 +7663,13 @@ func (this *Instruction) StringifySuffix() string {
 	} else if this.suffix == ZIRCI {
 		return "zirci"
 	} else if this.suffix == S_RIRC {
-		return "s_zirc"
+		return "s_rirc"
 	} else if this.suffix == S_RIRCI {
-		return "s_zirci"
+		return "s_rirci"
 	} else if this.suffix == U_RIRC {
-		return "u_zirc"
+		return "u_rirc"
 	} else if this.suffix == U_RIRCI {
-		return "u_zirci"
+		return "u_rirci"
 	} else if this.suffix == R {
 		return "r"
 	} else if this.suffix == RCI {
@@ -7886,3 +7886,11 @@ func (this *Instruction) StringifyOff(off *word.Immediate) string {
 func (this *Instruction) StringifyPc(pc *word.Immediate) string {
 	return strconv.FormatInt(pc.Value(), 10)
 }
+
+func (this *Instruction) Checkpoint(checkpoint_writer *misc.CheckpointWriter) {
+	checkpoint_writer.WriteByteStream(this.Encode())
+}
+
+func (this *Instruction) Restore(checkpoint_reader *misc.CheckpointReader) {
+	this.Decode(checkpoint_reader.ReadByteStream())
+}
@@ -42,6 +42,11 @@ func (this *Label) EndAddress() int64 {
 	return this.Address() + this.Size()
 }
 
+// CurAddress is the address that the next encodable appended to the label is placed at.
+func (this *Label) CurAddress() int64 {
+	return this.Address() + this.byte_stream.Size()
+}
+
 func (this *Label) SetAddress(address int64) {
 	if this.address != nil {
 		err := errors.New("address is already set")
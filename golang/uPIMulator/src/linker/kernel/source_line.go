@@ -0,0 +1,27 @@
+package kernel
+
+// A SourceLine is the C source line and function that an instruction has been compiled from. The
+// file is "??" and the line number is 0 if no .loc directive describes the instruction.
+type SourceLine struct {
+	file        string
+	line_number int
+	function    string
+}
+
+func (this *SourceLine) Init(file string, line_number int, function string) {
+	this.file = file
+	this.line_number = line_number
+	this.function = function
+}
+
+func (this *SourceLine) File() string {
+	return this.file
+}
+
+func (this *SourceLine) LineNumber() int {
+	return this.line_number
+}
+
+func (this *SourceLine) Function() string {
+	return this.function
+}
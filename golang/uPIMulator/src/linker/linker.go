@@ -245,6 +245,7 @@ func (this *Linker) LoadExecutable() {
 func (this *Linker) DumpExecutable() {
 	this.linker_script.DumpValues(filepath.Join(this.bin_dirpath, "values.txt"))
 	this.executable.DumpAddresses(filepath.Join(this.bin_dirpath, "addresses.txt"))
+	this.executable.DumpLines(filepath.Join(this.bin_dirpath, "lines.txt"))
 	this.executable.DumpAtomic(filepath.Join(this.bin_dirpath, "atomic.bin"))
 	this.executable.DumpIram(filepath.Join(this.bin_dirpath, "iram.bin"))
 	this.executable.DumpWram(filepath.Join(this.bin_dirpath, "wram.bin"))
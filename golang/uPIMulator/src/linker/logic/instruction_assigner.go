@@ -2,6 +2,8 @@ package logic
 
 import (
 	"errors"
+	"fmt"
+	"path/filepath"
 	"strconv"
 	"uPIMulator/src/linker/kernel"
 	"uPIMulator/src/linker/kernel/directive"
@@ -19,17 +21,26 @@ type InstructionAssigner struct {
 	executable    *kernel.Executable
 	walker        *parser.Walker
 	linker_script *LinkerScript
+
+	files map[int64]string
 }
 
 func (this *InstructionAssigner) Init(linker_script *LinkerScript) {
 	this.linker_script = linker_script
 
+	this.files = make(map[int64]string, 0)
+
 	this.walker = new(parser.Walker)
 	this.walker.Init()
 
 	this.walker.RegisterStmtCallback(stmt.ASCII, this.WalkAsciiStmt)
 	this.walker.RegisterStmtCallback(stmt.ASCIZ, this.WalkAscizStmt)
 	this.walker.RegisterStmtCallback(stmt.BYTE, this.WalkByteStmt)
+	this.walker.RegisterStmtCallback(stmt.FILE_NUMBER, this.WalkFileNumberStmt)
+	this.walker.RegisterStmtCallback(stmt.FILE_STRING, this.WalkFileStringStmt)
+	this.walker.RegisterStmtCallback(stmt.LOC_IS_STMT, this.WalkLocIsStmtStmt)
+	this.walker.RegisterStmtCallback(stmt.LOC_NUMBER, this.WalkLocNumberStmt)
+	this.walker.RegisterStmtCallback(stmt.LOC_PROLOGUE_END, this.WalkLocPrologueEndStmt)
 	this.walker.RegisterStmtCallback(stmt.LONG_PROGRAM_COUNTER, this.WalkLongProgramCounterStmt)
 	this.walker.RegisterStmtCallback(stmt.LONG_SECTION_NAME, this.WalkLongSectionNameStmt)
 	this.walker.RegisterStmtCallback(stmt.QUAD, this.WalkQuadStmt)
@@ -43,6 +54,7 @@ func (this *InstructionAssigner) Init(linker_script *LinkerScript) {
 	this.walker.RegisterStmtCallback(stmt.SECTION_STRING, this.WalkSectionStringStmt)
 	this.walker.RegisterStmtCallback(stmt.SHORT, this.WalkShortStmt)
 	this.walker.RegisterStmtCallback(stmt.TEXT, this.WalkTextStmt)
+	this.walker.RegisterStmtCallback(stmt.TYPE, this.WalkTypeStmt)
 	this.walker.RegisterStmtCallback(stmt.ZERO_DOUBLE_NUMBER, this.WalkZeroDoubleNumberStmt)
 	this.walker.RegisterStmtCallback(stmt.ZERO_SINGLE_NUMBER, this.WalkZeroSingleNumberStmt)
 
@@ -164,6 +176,75 @@ func (this *InstructionAssigner) WalkByteStmt(stmt_ *stmt.Stmt) {
 	cur_label.Append(byte_directive)
 }
 
+func (this *InstructionAssigner) WalkFileNumberStmt(stmt_ *stmt.Stmt) {
+	file_number_stmt := stmt_.FileNumberStmt()
+
+	file_number := this.EvaluateProgramCounter(file_number_stmt.Expr())
+
+	dirpath := file_number_stmt.Token1().Attribute()
+	dirpath = dirpath[1 : len(dirpath)-1]
+
+	filename := file_number_stmt.Token2().Attribute()
+	filename = filename[1 : len(filename)-1]
+
+	if filepath.IsAbs(filename) {
+		this.files[file_number] = filename
+	} else {
+		this.files[file_number] = filepath.Join(dirpath, filename)
+	}
+}
+
+// each relocatable starts with a .file directive of its own, and the file numbers of the .loc
+// directives are only unique within a relocatable
+func (this *InstructionAssigner) WalkFileStringStmt(stmt_ *stmt.Stmt) {
+	this.files = make(map[int64]string, 0)
+}
+
+func (this *InstructionAssigner) WalkLocIsStmtStmt(stmt_ *stmt.Stmt) {
+	loc_is_stmt_stmt := stmt_.LocIsStmtStmt()
+
+	file_number := this.EvaluateProgramCounter(loc_is_stmt_stmt.Expr1())
+	line_number := this.EvaluateProgramCounter(loc_is_stmt_stmt.Expr2())
+
+	this.AddLoc(file_number, line_number)
+}
+
+func (this *InstructionAssigner) WalkLocNumberStmt(stmt_ *stmt.Stmt) {
+	loc_number_stmt := stmt_.LocNumberStmt()
+
+	file_number := this.EvaluateProgramCounter(loc_number_stmt.Expr1())
+	line_number := this.EvaluateProgramCounter(loc_number_stmt.Expr2())
+
+	this.AddLoc(file_number, line_number)
+}
+
+func (this *InstructionAssigner) WalkLocPrologueEndStmt(stmt_ *stmt.Stmt) {
+	loc_prologue_end_stmt := stmt_.LocPrologueEndStmt()
+
+	file_number := this.EvaluateProgramCounter(loc_prologue_end_stmt.Expr1())
+	line_number := this.EvaluateProgramCounter(loc_prologue_end_stmt.Expr2())
+
+	this.AddLoc(file_number, line_number)
+}
+
+func (this *InstructionAssigner) AddLoc(file_number int64, line_number int64) {
+	if this.executable.CurSection().SectionName() != kernel.TEXT {
+		err := errors.New(".loc directive is not in a text section")
+		panic(err)
+	}
+
+	file, found := this.files[file_number]
+	if !found {
+		err_msg := fmt.Sprintf("file number (%d) is not declared", file_number)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	address := this.executable.CurSection().CurLabel().CurAddress()
+
+	this.executable.AddLoc(address, file, int(line_number))
+}
+
 func (this *InstructionAssigner) WalkLongProgramCounterStmt(stmt_ *stmt.Stmt) {
 	long_program_counter_stmt := stmt_.LongProgramCounterStmt()
 
@@ -305,6 +386,17 @@ func (this *InstructionAssigner) WalkTextStmt(stmt_ *stmt.Stmt) {
 	this.executable.CheckoutSection(section_name, name)
 }
 
+func (this *InstructionAssigner) WalkTypeStmt(stmt_ *stmt.Stmt) {
+	type_stmt := stmt_.TypeStmt()
+
+	if type_stmt.Expr2().SymbolTypeExpr().Token().TokenType() == lexer.FUNCTION {
+		program_counter_expr := type_stmt.Expr1().ProgramCounterExpr()
+		primary_expr := program_counter_expr.Expr().PrimaryExpr()
+
+		this.executable.AddFunction(primary_expr.Token().Attribute())
+	}
+}
+
 func (this *InstructionAssigner) WalkZeroDoubleNumberStmt(stmt_ *stmt.Stmt) {
 	zero_double_number_stmt := stmt_.ZeroDoubleNumberStmt()
 
@@ -1032,7 +1124,7 @@ func (this *InstructionAssigner) WalkSRrciStmt(stmt_ *stmt.Stmt) {
 func (this *InstructionAssigner) WalkSRrcStmt(stmt_ *stmt.Stmt) {
 	s_rrc_stmt := stmt_.SRrcStmt()
 
-	op_code := this.ConvertRriOpCode(s_rrc_stmt.OpCode())
+	op_code := this.ConvertRrOpCode(s_rrc_stmt.OpCode())
 	suffix := this.ConvertSuffix(s_rrc_stmt.Suffix(), instruction.RRC)
 	dc := this.ConvertPairReg(s_rrc_stmt.Dc())
 	ra := this.ConvertSrcReg(s_rrc_stmt.Ra())
@@ -1067,7 +1159,6 @@ func (this *InstructionAssigner) WalkSRricStmt(stmt_ *stmt.Stmt) {
 	s_rric_stmt := stmt_.SRricStmt()
 
 	op_code := this.ConvertRriOpCode(s_rric_stmt.OpCode())
-	suffix := this.ConvertSuffix(s_rric_stmt.Suffix(), instruction.RRIC)
 	dc := this.ConvertPairReg(s_rric_stmt.Dc())
 	ra := this.ConvertSrcReg(s_rric_stmt.Ra())
 	imm := this.EvaluateProgramCounter(s_rric_stmt.Imm())
@@ -1075,8 +1166,10 @@ func (this *InstructionAssigner) WalkSRricStmt(stmt_ *stmt.Stmt) {
 
 	instruction_ := new(instruction.Instruction)
 	if condition != cc.FALSE {
+		suffix := this.ConvertSuffix(s_rric_stmt.Suffix(), instruction.RRIC)
 		instruction_.InitSRric(op_code, suffix, dc, ra, imm, condition)
 	} else {
+		suffix := this.ConvertSuffix(s_rric_stmt.Suffix(), instruction.RRIF)
 		instruction_.InitSRrif(op_code, suffix, dc, ra, imm, condition)
 	}
 
@@ -1138,7 +1231,7 @@ func (this *InstructionAssigner) WalkSRrrcStmt(stmt_ *stmt.Stmt) {
 func (this *InstructionAssigner) WalkSRrriciStmt(stmt_ *stmt.Stmt) {
 	s_rrrici_stmt := stmt_.SRrriciStmt()
 
-	op_code := this.ConvertRriOpCode(s_rrrici_stmt.OpCode())
+	op_code := this.ConvertRrriOpCode(s_rrrici_stmt.OpCode())
 	suffix := this.ConvertSuffix(s_rrrici_stmt.Suffix(), instruction.RRRICI)
 	dc := this.ConvertPairReg(s_rrrici_stmt.Dc())
 	ra := this.ConvertSrcReg(s_rrrici_stmt.Ra())
@@ -1157,7 +1250,7 @@ func (this *InstructionAssigner) WalkSRrriciStmt(stmt_ *stmt.Stmt) {
 func (this *InstructionAssigner) WalkSRrriStmt(stmt_ *stmt.Stmt) {
 	s_rrri_stmt := stmt_.SRrriStmt()
 
-	op_code := this.ConvertRriOpCode(s_rrri_stmt.OpCode())
+	op_code := this.ConvertRrriOpCode(s_rrri_stmt.OpCode())
 	suffix := this.ConvertSuffix(s_rrri_stmt.Suffix(), instruction.RRRI)
 	dc := this.ConvertPairReg(s_rrri_stmt.Dc())
 	ra := this.ConvertSrcReg(s_rrri_stmt.Ra())
@@ -1221,7 +1314,7 @@ func (this *InstructionAssigner) WalkSRrStmt(stmt_ *stmt.Stmt) {
 func (this *InstructionAssigner) WalkSRStmt(stmt_ *stmt.Stmt) {
 	s_r_stmt := stmt_.SRStmt()
 
-	op_code := this.ConvertRriOpCode(s_r_stmt.OpCode())
+	op_code := this.ConvertROpCode(s_r_stmt.OpCode())
 	suffix := this.ConvertSuffix(s_r_stmt.Suffix(), instruction.R)
 	dc := this.ConvertPairReg(s_r_stmt.Dc())
 
@@ -2068,6 +2161,8 @@ func (this *InstructionAssigner) ConvertSuffix(
 			return instruction.S_RRICI
 		} else if base == instruction.RRIC {
 			return instruction.S_RRIC
+		} else if base == instruction.RRIF {
+			return instruction.S_RRIF
 		} else if base == instruction.RRI {
 			return instruction.S_RRI
 		} else if base == instruction.RRRCI {
@@ -2105,6 +2200,8 @@ func (this *InstructionAssigner) ConvertSuffix(
 			return instruction.U_RRICI
 		} else if base == instruction.RRIC {
 			return instruction.U_RRIC
+		} else if base == instruction.RRIF {
+			return instruction.U_RRIF
 		} else if base == instruction.RRI {
 			return instruction.U_RRI
 		} else if base == instruction.RRRCI {
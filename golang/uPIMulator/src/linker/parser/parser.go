@@ -111,6 +111,7 @@ func (this *Parser) Init() {
 	this.RegisterSErriStmt()
 	this.RegisterSRciStmt()
 	this.RegisterSRirciStmt()
+	this.RegisterSRircStmt()
 	this.RegisterSRrciStmt()
 	this.RegisterSRrcStmt()
 	this.RegisterSRriciStmt()
@@ -4624,7 +4625,7 @@ func (this *Parser) RegisterSRrrciStmt() {
 		dc := stack_items[2].Token()
 		ra := stack_items[4].Expr()
 		rb := stack_items[6].Expr()
-		condition := stack_items[7].Expr()
+		condition := stack_items[8].Expr()
 		pc := stack_items[10].Expr()
 
 		s_rrrci_stmt := new(stmt.Stmt)
@@ -4680,7 +4681,7 @@ func (this *Parser) RegisterSRrrcStmt() {
 		dc := stack_items[2].Token()
 		ra := stack_items[4].Expr()
 		rb := stack_items[6].Expr()
-		condition := stack_items[7].Expr()
+		condition := stack_items[8].Expr()
 
 		s_rrrc_stmt := new(stmt.Stmt)
 		s_rrrc_stmt.InitSRrrcStmt(op_code, suffix, dc, ra, rb, condition)
@@ -4705,7 +4706,7 @@ func (this *Parser) RegisterSRrriciStmt() {
 			return false
 		} else {
 			if stack_items[0].StackItemType() == EXPR &&
-				stack_items[0].Expr().ExprType() == expr.RRI_OP_CODE &&
+				stack_items[0].Expr().ExprType() == expr.RRRI_OP_CODE &&
 				stack_items[1].StackItemType() == EXPR &&
 				stack_items[1].Expr().ExprType() == expr.SUFFIX &&
 				stack_items[2].StackItemType() == TOKEN &&
@@ -4770,7 +4771,7 @@ func (this *Parser) RegisterSRrriStmt() {
 			return false
 		} else {
 			if stack_items[0].StackItemType() == EXPR &&
-				stack_items[0].Expr().ExprType() == expr.RRI_OP_CODE &&
+				stack_items[0].Expr().ExprType() == expr.RRRI_OP_CODE &&
 				stack_items[1].StackItemType() == EXPR &&
 				stack_items[1].Expr().ExprType() == expr.SUFFIX &&
 				stack_items[2].StackItemType() == TOKEN &&
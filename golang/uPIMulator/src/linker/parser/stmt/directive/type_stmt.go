@@ -21,7 +21,8 @@ func (this *TypeStmt) Init(expr1 *expr.Expr, expr2 *expr.Expr) {
 		panic(err)
 	}
 
-	this.expr1 = expr2
+	this.expr1 = expr1
+	this.expr2 = expr2
 }
 
 func (this *TypeStmt) Expr1() *expr.Expr {
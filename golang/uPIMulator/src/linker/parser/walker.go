@@ -48,8 +48,18 @@ func (this *Walker) Walk(ast *Ast) {
 			this.WalkAscizStmt(stmt_)
 		} else if stmt_type == stmt.BYTE {
 			this.WalkByteStmt(stmt_)
+		} else if stmt_type == stmt.FILE_NUMBER {
+			this.WalkFileNumberStmt(stmt_)
+		} else if stmt_type == stmt.FILE_STRING {
+			this.WalkFileStringStmt(stmt_)
 		} else if stmt_type == stmt.GLOBAL {
 			this.WalkGlobalStmt(stmt_)
+		} else if stmt_type == stmt.LOC_IS_STMT {
+			this.WalkLocIsStmtStmt(stmt_)
+		} else if stmt_type == stmt.LOC_NUMBER {
+			this.WalkLocNumberStmt(stmt_)
+		} else if stmt_type == stmt.LOC_PROLOGUE_END {
+			this.WalkLocPrologueEndStmt(stmt_)
 		} else if stmt_type == stmt.LONG_PROGRAM_COUNTER {
 			this.WalkLongProgramCounterStmt(stmt_)
 		} else if stmt_type == stmt.LONG_SECTION_NAME {
@@ -76,6 +86,8 @@ func (this *Walker) Walk(ast *Ast) {
 			this.WalkSizeStmt(stmt_)
 		} else if stmt_type == stmt.TEXT {
 			this.WalkTextStmt(stmt_)
+		} else if stmt_type == stmt.TYPE {
+			this.WalkTypeStmt(stmt_)
 		} else if stmt_type == stmt.ZERO_DOUBLE_NUMBER {
 			this.WalkZeroDoubleNumberStmt(stmt_)
 		} else if stmt_type == stmt.ZERO_SINGLE_NUMBER {
@@ -352,6 +364,34 @@ func (this *Walker) WalkByteStmt(stmt_ *stmt.Stmt) {
 	this.WalkProgramCounterExpr(expr_)
 }
 
+func (this *Walker) WalkFileNumberStmt(stmt_ *stmt.Stmt) {
+	if stmt_.StmtType() != stmt.FILE_NUMBER {
+		err := errors.New("stmt type is not a file number stmt")
+		panic(err)
+	}
+
+	if stmt_callback, found := this.stmt_callbacks[stmt.FILE_NUMBER]; found {
+		stmt_callback(stmt_)
+	}
+
+	file_number_stmt := stmt_.FileNumberStmt()
+
+	expr_ := file_number_stmt.Expr()
+
+	this.WalkProgramCounterExpr(expr_)
+}
+
+func (this *Walker) WalkFileStringStmt(stmt_ *stmt.Stmt) {
+	if stmt_.StmtType() != stmt.FILE_STRING {
+		err := errors.New("stmt type is not a file string stmt")
+		panic(err)
+	}
+
+	if stmt_callback, found := this.stmt_callbacks[stmt.FILE_STRING]; found {
+		stmt_callback(stmt_)
+	}
+}
+
 func (this *Walker) WalkGlobalStmt(stmt_ *stmt.Stmt) {
 	if stmt_.StmtType() != stmt.GLOBAL {
 		err := errors.New("stmt type is not a global stmt")
@@ -369,6 +409,71 @@ func (this *Walker) WalkGlobalStmt(stmt_ *stmt.Stmt) {
 	this.WalkProgramCounterExpr(expr_)
 }
 
+func (this *Walker) WalkLocIsStmtStmt(stmt_ *stmt.Stmt) {
+	if stmt_.StmtType() != stmt.LOC_IS_STMT {
+		err := errors.New("stmt type is not a loc is_stmt stmt")
+		panic(err)
+	}
+
+	if stmt_callback, found := this.stmt_callbacks[stmt.LOC_IS_STMT]; found {
+		stmt_callback(stmt_)
+	}
+
+	loc_is_stmt_stmt := stmt_.LocIsStmtStmt()
+
+	expr1 := loc_is_stmt_stmt.Expr1()
+	expr2 := loc_is_stmt_stmt.Expr2()
+	expr3 := loc_is_stmt_stmt.Expr3()
+	expr4 := loc_is_stmt_stmt.Expr4()
+
+	this.WalkProgramCounterExpr(expr1)
+	this.WalkProgramCounterExpr(expr2)
+	this.WalkProgramCounterExpr(expr3)
+	this.WalkProgramCounterExpr(expr4)
+}
+
+func (this *Walker) WalkLocNumberStmt(stmt_ *stmt.Stmt) {
+	if stmt_.StmtType() != stmt.LOC_NUMBER {
+		err := errors.New("stmt type is not a loc number stmt")
+		panic(err)
+	}
+
+	if stmt_callback, found := this.stmt_callbacks[stmt.LOC_NUMBER]; found {
+		stmt_callback(stmt_)
+	}
+
+	loc_number_stmt := stmt_.LocNumberStmt()
+
+	expr1 := loc_number_stmt.Expr1()
+	expr2 := loc_number_stmt.Expr2()
+	expr3 := loc_number_stmt.Expr3()
+
+	this.WalkProgramCounterExpr(expr1)
+	this.WalkProgramCounterExpr(expr2)
+	this.WalkProgramCounterExpr(expr3)
+}
+
+func (this *Walker) WalkLocPrologueEndStmt(stmt_ *stmt.Stmt) {
+	if stmt_.StmtType() != stmt.LOC_PROLOGUE_END {
+		err := errors.New("stmt type is not a loc prologue_end stmt")
+		panic(err)
+	}
+
+	if stmt_callback, found := this.stmt_callbacks[stmt.LOC_PROLOGUE_END]; found {
+		stmt_callback(stmt_)
+	}
+
+	loc_prologue_end_stmt := stmt_.LocPrologueEndStmt()
+
+	expr1 := loc_prologue_end_stmt.Expr1()
+	expr2 := loc_prologue_end_stmt.Expr2()
+	expr3 := loc_prologue_end_stmt.Expr3()
+
+	this.WalkProgramCounterExpr(expr1)
+	this.WalkProgramCounterExpr(expr2)
+	this.WalkProgramCounterExpr(expr3)
+}
+
 func (this *Walker) WalkLongProgramCounterStmt(stmt_ *stmt.Stmt) {
 	if stmt_.StmtType() != stmt.LONG_PROGRAM_COUNTER {
 		err := errors.New("stmt type is not a long program counter stmt")
@@ -545,6 +650,23 @@ func (this *Walker) WalkTextStmt(stmt_ *stmt.Stmt) {
 	}
 }
 
+func (this *Walker) WalkTypeStmt(stmt_ *stmt.Stmt) {
+	if stmt_.StmtType() != stmt.TYPE {
+		err := errors.New("stmt type is not a type stmt")
+		panic(err)
+	}
+
+	if stmt_callback, found := this.stmt_callbacks[stmt.TYPE]; found {
+		stmt_callback(stmt_)
+	}
+
+	type_stmt := stmt_.TypeStmt()
+
+	expr_ := type_stmt.Expr1()
+
+	this.WalkProgramCounterExpr(expr_)
+}
+
 func (this *Walker) WalkZeroDoubleNumberStmt(stmt_ *stmt.Stmt) {
 	if stmt_.StmtType() != stmt.ZERO_DOUBLE_NUMBER {
 		err := errors.New("stmt type is not a zero double number stmt")
@@ -1207,7 +1329,7 @@ func (this *Walker) WalkSRrrcStmt(stmt_ *stmt.Stmt) {
 		panic(err)
 	}
 
-	if stmt_callback, found := this.stmt_callbacks[stmt.RRRC]; found {
+	if stmt_callback, found := this.stmt_callbacks[stmt.S_RRRC]; found {
 		stmt_callback(stmt_)
 	}
 }
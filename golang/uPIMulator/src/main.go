@@ -1,33 +1,73 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"uPIMulator/src/assembler"
 	"uPIMulator/src/compiler"
 	"uPIMulator/src/linker"
 	"uPIMulator/src/misc"
 	"uPIMulator/src/simulator"
+	"uPIMulator/src/sweeper"
 )
 
 func main() {
 	command_line_parser := InitCommandLineParser()
-	command_line_parser.Parse(os.Args)
+
+	var simulator_ *simulator.Simulator
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			os.Exit(Fail(command_line_parser, simulator_, recovered))
+		}
+	}()
+
+	// the sweep subcommand, e.g., uPIMulator sweep --sweep_filepath sweep.json --bin_dirpath sweep
+	is_sweep := len(os.Args) > 1 && os.Args[1] == "sweep"
+
+	Stage("CommandLineParser", misc.CONFIG_ERROR, func() {
+		if is_sweep {
+			command_line_parser.Parse(os.Args[1:])
+		} else {
+			command_line_parser.Parse(os.Args)
+		}
+	})
 
 	if command_line_parser.IsArgSet("help") {
 		fmt.Printf("%s", command_line_parser.StringifyHelpMsgs())
+	} else if is_sweep {
+		var sweeper_ *sweeper.Sweeper
+
+		Stage("SweepFile", misc.CONFIG_ERROR, func() {
+			sweeper_ = new(sweeper.Sweeper)
+			sweeper_.Init(command_line_parser)
+		})
+
+		Stage("Sweeper", misc.INTERNAL_ERROR, func() {
+			sweeper_.Run()
+		})
 	} else {
-		command_line_validator := new(misc.CommandLineValidator)
-		command_line_validator.Init(command_line_parser)
-		command_line_validator.Validate()
+		Stage("ConfigFile", misc.CONFIG_ERROR, func() {
+			ResolveConfig(command_line_parser)
+		})
 
-		config_loader := new(misc.ConfigLoader)
-		config_loader.Init()
+		Stage("CommandLineValidator", misc.CONFIG_ERROR, func() {
+			command_line_validator := new(misc.CommandLineValidator)
+			command_line_validator.Init(command_line_parser)
+			command_line_validator.Validate()
+		})
+
+		Stage("ConfigValidator", misc.CONFIG_ERROR, func() {
+			config_loader := new(misc.ConfigLoader)
+			config_loader.Init()
 
-		config_validator := new(misc.ConfigValidator)
-		config_validator.Init(config_loader)
-		config_validator.Validate()
+			config_validator := new(misc.ConfigValidator)
+			config_validator.Init(config_loader)
+			config_validator.Validate()
+		})
 
 		bin_dirpath := command_line_parser.StringParameter("bin_dirpath")
 		args_filepath := filepath.Join(bin_dirpath, "args.txt")
@@ -41,30 +81,171 @@ func main() {
 		options_file_dumper.Init(options_filepath)
 		options_file_dumper.WriteLines([]string{command_line_parser.StringifyOptions()})
 
-		compiler_ := new(compiler.Compiler)
-		compiler_.Init(command_line_parser)
-		compiler_.Compile()
+		config_loader := new(misc.ConfigLoader)
+		config_loader.Init()
+
+		resolved_config_file := new(misc.ConfigFile)
+		resolved_config_file.InitResolved(config_loader, command_line_parser)
+		resolved_config_file.Write(filepath.Join(bin_dirpath, "config.json"))
+
+		restore_filepath := command_line_parser.StringParameter("restore_filepath")
 
-		linker_ := new(linker.Linker)
-		linker_.Init(command_line_parser)
-		linker_.Link()
+		if restore_filepath == "" && !command_line_parser.BoolParameter("skip_build") {
+			Stage("Compiler", misc.INTERNAL_ERROR, func() {
+				compiler_ := new(compiler.Compiler)
+				compiler_.Init(command_line_parser)
+				compiler_.Compile()
+			})
 
-		assembler_ := new(assembler.Assembler)
-		assembler_.Init(command_line_parser)
-		assembler_.Assemble()
+			Stage("Linker", misc.INTERNAL_ERROR, func() {
+				linker_ := new(linker.Linker)
+				linker_.Init(command_line_parser)
+				linker_.Link()
+			})
 
-		simulator_ := new(simulator.Simulator)
-		simulator_.Init(command_line_parser)
+			Stage("Assembler", misc.INTERNAL_ERROR, func() {
+				assembler_ := new(assembler.Assembler)
+				assembler_.Init(command_line_parser)
+				assembler_.Assemble()
+			})
+		}
 
-		for !simulator_.IsFinished() {
-			simulator_.Cycle()
+		if command_line_parser.BoolParameter("build_only") {
+			return
 		}
 
+		Stage("Simulator", misc.INTERNAL_ERROR, func() {
+			simulator_ = new(simulator.Simulator)
+			simulator_.Init(command_line_parser)
+
+			if restore_filepath != "" {
+				simulator_.Restore(restore_filepath)
+			}
+
+			if command_line_parser.BoolParameter("debug") {
+				debugger := new(simulator.Debugger)
+				debugger.Init(simulator_, command_line_parser)
+				debugger.Run()
+			} else if command_line_parser.IntParameter("gdb_port") > 0 {
+				gdb_server := new(simulator.GdbServer)
+				gdb_server.Init(simulator_, command_line_parser)
+				gdb_server.Run()
+			} else {
+				for !simulator_.IsFinished() {
+					simulator_.Cycle()
+				}
+			}
+
+			if simulator_.IsHung() {
+				err := new(misc.SimulationError)
+				err.Init(misc.NO_PROGRESS, "DPUs make no progress within watchdog_window")
+				panic(err)
+			}
+		})
+
 		simulator_.Dump()
 		simulator_.Fini()
 	}
 }
 
+// ResolveConfig applies the config file and then the config overrides to the architecture and to
+// the options that are not given on the command line, and resolves the architecture for the run.
+func ResolveConfig(command_line_parser *misc.CommandLineParser) {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.InitDefault()
+
+	if config_filepath := command_line_parser.StringParameter("config_filepath"); config_filepath != "" {
+		config_file := new(misc.ConfigFile)
+		config_file.Init(config_filepath)
+
+		for parameter, value := range config_file.Architecture() {
+			config_loader.SetValue(parameter, value)
+		}
+
+		for option, parameter := range config_file.Options() {
+			command_line_parser.SetConfigParameter(option, parameter)
+		}
+	}
+
+	for name, value := range command_line_parser.ConfigOverrides() {
+		if config_loader.HasParameter(name) {
+			int_value, parse_err := strconv.ParseInt(value, 10, 64)
+			if parse_err != nil {
+				err_msg := fmt.Sprintf("architecture parameter (%s) is not an integer", name)
+				err := errors.New(err_msg)
+				panic(err)
+			}
+
+			config_loader.SetValue(name, int_value)
+		} else {
+			command_line_parser.SetConfigParameter(name, value)
+		}
+	}
+
+	config_loader.Resolve()
+}
+
+// Stage runs a stage of the run, and attaches the stage to what it raises as the component, with
+// the kind if it is not a simulation error already, e.g., a malformed option is a config error.
+func Stage(component string, kind misc.SimulationErrorKind, stage func()) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			simulation_error := new(misc.SimulationError)
+			simulation_error.InitRecovered(recovered, kind)
+			simulation_error.SetComponent(component)
+			panic(simulation_error)
+		}
+	}()
+
+	stage()
+}
+
+// Fail reports the simulation error that has stopped the run: it prints the error, dumps the stats
+// that the simulator has gathered so far, writes failure.json to bin_dirpath, and returns the exit
+// code of the error.
+func Fail(
+	command_line_parser *misc.CommandLineParser,
+	simulator_ *simulator.Simulator,
+	recovered interface{},
+) int {
+	simulation_error := new(misc.SimulationError)
+	simulation_error.InitRecovered(recovered, misc.INTERNAL_ERROR)
+
+	fmt.Fprintln(os.Stderr, simulation_error.Error())
+
+	failure_report := new(misc.FailureReport)
+	failure_report.Init(simulation_error)
+
+	if simulator_ != nil {
+		failure_report.SetProgress(simulator_.Execution(), simulator_.Cycles())
+		failure_report.SetPartialStats(DumpPartialStats(simulator_))
+	}
+
+	bin_dirpath := command_line_parser.StringParameter("bin_dirpath")
+	if _, stat_err := os.Stat(bin_dirpath); stat_err == nil {
+		failure_path := filepath.Join(bin_dirpath, "failure.json")
+		failure_report.Write(failure_path)
+
+		fmt.Fprintf(os.Stderr, "failure report is written, see (%s)...\n", failure_path)
+	}
+
+	return simulation_error.ExitCode()
+}
+
+// DumpPartialStats dumps the stats of a simulator that has failed, and returns whether it could,
+// since the failure may have left the simulator in a state that cannot be dumped.
+func DumpPartialStats(simulator_ *simulator.Simulator) (is_dumped bool) {
+	defer func() {
+		if recover() != nil {
+			is_dumped = false
+		}
+	}()
+
+	simulator_.Dump()
+
+	return true
+}
+
 func InitCommandLineParser() *misc.CommandLineParser {
 	command_line_parser := new(misc.CommandLineParser)
 	command_line_parser.Init()
@@ -78,6 +259,19 @@ func InitCommandLineParser() *misc.CommandLineParser {
 	command_line_parser.AddOption(misc.INT, "num_simulation_threads", "16",
 		"number of simulation threads to launch")
 
+	command_line_parser.AddOption(
+		misc.STRING,
+		"config_filepath",
+		"",
+		"path to a JSON config file with the architecture and the options, which the command line overrides",
+	)
+	command_line_parser.AddOption(
+		misc.STRING,
+		"config_overrides",
+		"",
+		"comma-separated name=value pairs of architecture parameters or options that override the config file",
+	)
+
 	command_line_parser.AddOption(misc.STRING, "benchmark", "BS", "benchmark name")
 
 	command_line_parser.AddOption(misc.INT, "num_channels", "1", "number of PIM memory channels")
@@ -149,5 +343,170 @@ func InitCommandLineParser() *misc.CommandLineParser {
 		"write bandwidth per DPU per rank [bytes/cycle]",
 	)
 
+	command_line_parser.AddOption(
+		misc.INT,
+		"checkpoint_period",
+		"0",
+		"number of logic cycles between checkpoints (0 disables checkpointing)",
+	)
+	command_line_parser.AddOption(
+		misc.STRING,
+		"restore_filepath",
+		"",
+		"path to the checkpoint to restore the simulation from",
+	)
+	command_line_parser.AddOption(
+		misc.BOOL,
+		"fast_forward",
+		"false",
+		"skip logic cycles in which every tasklet is stalled on DMA",
+	)
+	command_line_parser.AddOption(
+		misc.STRING,
+		"mode",
+		"timing",
+		"simulation mode (timing, functional, or sampled)",
+	)
+	command_line_parser.AddOption(
+		misc.INT,
+		"sampling_period",
+		"1000000",
+		"number of instructions executed functionally between samples in the sampled mode",
+	)
+	command_line_parser.AddOption(
+		misc.INT,
+		"sampling_warmup",
+		"1000",
+		"number of unmeasured logic cycles before each sample in the sampled mode",
+	)
+	command_line_parser.AddOption(
+		misc.INT,
+		"sampling_window",
+		"10000",
+		"number of measured logic cycles per sample in the sampled mode",
+	)
+	command_line_parser.AddOption(
+		misc.STRING,
+		"stats_format",
+		"text",
+		"comma-separated formats of the stats written next to log.txt (text, json, or csv)",
+	)
+	command_line_parser.AddOption(
+		misc.INT,
+		"interval_period",
+		"0",
+		"number of logic cycles between interval stats records (0 disables interval stats)",
+	)
+	command_line_parser.AddOption(
+		misc.STRING,
+		"interval_stats",
+		"num_instructions,active_tasklets_*,backpressure,breakdown_dma,num_activations,read_bytes,write_bytes",
+		"comma-separated patterns of the stats recorded every interval",
+	)
+	command_line_parser.AddOption(
+		misc.BOOL,
+		"interval_aggregate",
+		"false",
+		"sum the interval stats over all DPUs instead of recording them per DPU",
+	)
+
+	command_line_parser.AddOption(
+		misc.BOOL,
+		"trace",
+		"false",
+		"write the tasklet states, DMA commands, and host transfers to trace.json in Chrome trace-event format",
+	)
+
+	command_line_parser.AddOption(
+		misc.BOOL,
+		"profile",
+		"false",
+		"attribute the issued instructions, stall cycles, and DMA wait cycles to the source lines and functions in profile.txt",
+	)
+	command_line_parser.AddOption(
+		misc.BOOL,
+		"call_graph",
+		"false",
+		"write the per-function cycles, instructions, and DMA bytes along the tasklets' call stacks to call_graph.txt and call_graph.folded",
+	)
+
+	command_line_parser.AddOption(
+		misc.BOOL,
+		"detect_races",
+		"false",
+		"report the WRAM accesses of the tasklets that are not ordered by locks, boots, or resumes to races.txt",
+	)
+	command_line_parser.AddOption(
+		misc.BOOL,
+		"sanitize",
+		"false",
+		"report out-of-bounds, uninitialized, misaligned, and stack-overflowing memory accesses of the tasklets to sanitizer.txt",
+	)
+
+	command_line_parser.AddOption(
+		misc.INT,
+		"watchdog_window",
+		"0",
+		"stop and report to watchdog.txt a DPU whose tasklets neither finish, retire a DMA command, nor acquire or release a lock for this many cycles (0 to disable)",
+	)
+	command_line_parser.AddOption(
+		misc.INT,
+		"watchdog_history",
+		"16",
+		"number of the last issued instructions of each tasklet that the watchdog reports",
+	)
+
+	command_line_parser.AddOption(
+		misc.BOOL,
+		"debug",
+		"false",
+		"run the simulator under the interactive debugger",
+	)
+	command_line_parser.AddOption(
+		misc.STRING,
+		"debug_script",
+		"",
+		"read the debugger commands from the file instead of the standard input",
+	)
+
+	command_line_parser.AddOption(
+		misc.INT,
+		"gdb_port",
+		"0",
+		"serve the GDB remote protocol on the local TCP port (0 to disable)",
+	)
+	command_line_parser.AddOption(
+		misc.INT,
+		"gdb_dpu",
+		"0",
+		"index of the DPU that GDB attaches to",
+	)
+
+	command_line_parser.AddOption(
+		misc.BOOL,
+		"build_only",
+		"false",
+		"compile, link, and assemble the benchmark into bin_dirpath without simulating it",
+	)
+	command_line_parser.AddOption(
+		misc.BOOL,
+		"skip_build",
+		"false",
+		"simulate the binary that is already assembled in bin_dirpath",
+	)
+
+	command_line_parser.AddOption(
+		misc.STRING,
+		"sweep_filepath",
+		"",
+		"path to the JSON sweep file whose cases the sweep subcommand runs under bin_dirpath",
+	)
+	command_line_parser.AddOption(
+		misc.INT,
+		"num_sweep_jobs",
+		"4",
+		"number of the cases of a sweep that are simulated in parallel",
+	)
+
 	return command_line_parser
 }
@@ -0,0 +1,410 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+	"uPIMulator/src/assembler"
+	"uPIMulator/src/linker"
+	"uPIMulator/src/misc"
+	"uPIMulator/src/simulator"
+)
+
+var update = flag.Bool("update", false, "regenerate the golden stats from the simulator")
+
+// A Golden holds the options that a benchmark is simulated with and the stats that it is expected
+// to end with, i.e., the cycles and the stats of the components summed over the DPUs. A stat may
+// deviate from its golden value by its tolerance, which is relative to the golden value, and which
+// is the tolerance of the golden unless the stat has a tolerance of its own.
+type Golden struct {
+	Options    map[string]string  `json:"options"`
+	Tolerance  float64            `json:"tolerance"`
+	Tolerances map[string]float64 `json:"tolerances,omitempty"`
+	Stats      map[string]int64   `json:"stats"`
+}
+
+// TestKernels simulates the hand-written DPU kernels that are checked in under
+// testdata/kernels/<benchmark>/bin, each with the host of the benchmark of its name, and compares
+// their stats to testdata/kernels/<benchmark>/golden.json, so that it needs neither Docker nor the
+// SDK. The kernels are not those that the SDK builds of the benchmarks, so that they keep the
+// simulator consistent with itself rather than with the benchmarks. The golden.json of a kernel is
+// written with
+//
+//	go test ./src -run TestKernels -update
+//
+// which keeps the options and the tolerances of the existing golden.json.
+func TestKernels(t *testing.T) {
+	entries, read_err := os.ReadDir(filepath.Join("testdata", "kernels"))
+	if read_err != nil {
+		t.Fatal(read_err)
+	}
+
+	for _, entry := range entries {
+		t.Run(entry.Name(), func(t *testing.T) {
+			RunGolden(t, entry.Name(), filepath.Join("testdata", "kernels", entry.Name()))
+		})
+	}
+}
+
+// RunGolden simulates the binary under the bin directory of the golden directory and compares its
+// stats to the golden.json of the directory, or writes them to it with -update.
+func RunGolden(t *testing.T, benchmark string, golden_dirpath string) {
+	golden := ReadGolden(t, filepath.Join(golden_dirpath, "golden.json"))
+	stats := SimulateGolden(t, benchmark, filepath.Join(golden_dirpath, "bin"), golden.Options)
+
+	if *update {
+		golden.Stats = stats
+		WriteGolden(t, filepath.Join(golden_dirpath, "golden.json"), golden)
+		return
+	}
+
+	CompareGolden(t, golden, stats)
+}
+
+func ReadGolden(t *testing.T, path string) *Golden {
+	golden := new(Golden)
+	golden.Options = make(map[string]string, 0)
+	golden.Stats = make(map[string]int64, 0)
+
+	content, read_err := os.ReadFile(path)
+	if os.IsNotExist(read_err) && *update {
+		return golden
+	} else if read_err != nil {
+		t.Fatalf("golden (%s) cannot be read: %v", path, read_err)
+	}
+
+	if unmarshal_err := json.Unmarshal(content, golden); unmarshal_err != nil {
+		t.Fatalf("golden (%s) is not valid: %v", path, unmarshal_err)
+	}
+	return golden
+}
+
+func WriteGolden(t *testing.T, path string, golden *Golden) {
+	content, marshal_err := json.MarshalIndent(golden, "", "  ")
+	if marshal_err != nil {
+		t.Fatal(marshal_err)
+	}
+
+	if write_err := os.WriteFile(path, append(content, '\n'), 0644); write_err != nil {
+		t.Fatal(write_err)
+	}
+}
+
+// SimulateGolden simulates the binary and returns the stats that the simulation ends with.
+func SimulateGolden(
+	t *testing.T,
+	benchmark string,
+	golden_bin_dirpath string,
+	options map[string]string,
+) (stats map[string]int64) {
+	simulator_ := SimulateBinary(t, benchmark, golden_bin_dirpath, options)
+
+	stats = make(map[string]int64, 0)
+	stats["cycles"] = simulator_.Cycles()
+
+	for _, dpu_ := range simulator_.Host().Dpus() {
+		for component, component_stats := range dpu_.ComponentStats() {
+			for stat, value := range component_stats {
+				stats[component+"."+stat] += value
+			}
+		}
+	}
+
+	simulator_.Fini()
+
+	return stats
+}
+
+// SimulateBinary simulates a copy of the binary, since the simulator writes its outputs next to
+// the binary, and returns the simulator once the simulation has finished.
+func SimulateBinary(
+	t *testing.T,
+	benchmark string,
+	golden_bin_dirpath string,
+	options map[string]string,
+) *simulator.Simulator {
+	bin_dirpath := t.TempDir()
+
+	entries, read_err := os.ReadDir(golden_bin_dirpath)
+	if read_err != nil {
+		t.Fatal(read_err)
+	}
+
+	for _, entry := range entries {
+		content, file_read_err := os.ReadFile(filepath.Join(golden_bin_dirpath, entry.Name()))
+		if file_read_err != nil {
+			t.Fatal(file_read_err)
+		}
+
+		write_err := os.WriteFile(filepath.Join(bin_dirpath, entry.Name()), content, 0644)
+		if write_err != nil {
+			t.Fatal(write_err)
+		}
+	}
+
+	args := []string{
+		"uPIMulator",
+		"--benchmark",
+		benchmark,
+		"--root_dirpath",
+		"..",
+		"--bin_dirpath",
+		bin_dirpath,
+	}
+
+	option_names := make([]string, 0)
+	for option := range options {
+		option_names = append(option_names, option)
+	}
+	slices.Sort(option_names)
+
+	for _, option := range option_names {
+		args = append(args, "--"+option, options[option])
+	}
+	args = append(args, "--skip_build", "true")
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			simulation_error := new(misc.SimulationError)
+			simulation_error.InitRecovered(recovered, misc.INTERNAL_ERROR)
+
+			t.Fatalf("simulation of %s has failed: %s", benchmark, simulation_error.Error())
+		}
+	}()
+
+	command_line_parser := InitCommandLineParser()
+	command_line_parser.Parse(args)
+
+	ResolveConfig(command_line_parser)
+
+	command_line_validator := new(misc.CommandLineValidator)
+	command_line_validator.Init(command_line_parser)
+	command_line_validator.Validate()
+
+	simulator_ := new(simulator.Simulator)
+	simulator_.Init(command_line_parser)
+
+	for !simulator_.IsFinished() {
+		simulator_.Cycle()
+	}
+
+	if simulator_.IsHung() {
+		t.Fatalf("simulation of %s makes no progress at cycle %d", benchmark, simulator_.Cycles())
+	}
+
+	return simulator_
+}
+
+func CompareGolden(t *testing.T, golden *Golden, stats map[string]int64) {
+	names := make([]string, 0)
+	for name := range golden.Stats {
+		names = append(names, name)
+	}
+	for name := range stats {
+		if _, found := golden.Stats[name]; !found {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		golden_value, is_golden := golden.Stats[name]
+		value, is_simulated := stats[name]
+
+		if !is_golden {
+			t.Errorf("%s: %d is not in the golden, run with -update to add it", name, value)
+			continue
+		} else if !is_simulated {
+			t.Errorf("%s: golden %d is not simulated", name, golden_value)
+			continue
+		}
+
+		tolerance := golden.Tolerance
+		if stat_tolerance, found := golden.Tolerances[name]; found {
+			tolerance = stat_tolerance
+		}
+
+		deviation := math.Abs(float64(value - golden_value))
+		if deviation > tolerance*math.Abs(float64(golden_value)) {
+			t.Errorf(
+				"%s: golden %d, simulated %d (%+.2f%%, tolerance %.2f%%)",
+				name,
+				golden_value,
+				value,
+				100*float64(value-golden_value)/math.Max(1, math.Abs(float64(golden_value))),
+				100*tolerance,
+			)
+		}
+	}
+}
+
+// TestSampled simulates the VA kernel in the sampled mode and compares it to the full simulation of
+// its golden.json. The counters that the functional mode keeps counting are exact, and the cycles
+// are extrapolated to within the confidence interval that the sampler reports for them.
+func TestSampled(t *testing.T) {
+	golden_dirpath := filepath.Join("testdata", "kernels", "VA")
+	golden := ReadGolden(t, filepath.Join(golden_dirpath, "golden.json"))
+
+	options := map[string]string{
+		"mode":            "sampled",
+		"sampling_period": "500",
+		"sampling_warmup": "100",
+		"sampling_window": "500",
+	}
+	for option, value := range golden.Options {
+		options[option] = value
+	}
+
+	simulator_ := SimulateBinary(t, "VA", filepath.Join(golden_dirpath, "bin"), options)
+	defer simulator_.Fini()
+
+	exact_stats := []string{
+		"Logic.num_instructions",
+		"RowBuffer.num_reads",
+		"RowBuffer.read_bytes",
+		"RowBuffer.num_writes",
+		"RowBuffer.write_bytes",
+	}
+	cycle_stats := []string{"Logic.logic_cycle", "MemoryController.memory_cycle"}
+
+	for _, sampler := range simulator_.Samplers() {
+		if sampler.NumSamples() < 2 {
+			t.Fatalf("sampler takes %d samples, expected at least 2", sampler.NumSamples())
+		}
+
+		for component, stat_factory := range sampler.Dpu().StatComponents() {
+			for _, stat := range stat_factory.Stats() {
+				name := component + "." + stat
+				value, half_width := sampler.Extrapolate(stat_factory, stat)
+				golden_value := golden.Stats[name]
+
+				if slices.Contains(exact_stats, name) && value != golden_value {
+					t.Errorf("%s: sampled %d, expected exactly %d", name, value, golden_value)
+				} else if slices.Contains(cycle_stats, name) &&
+					math.Abs(float64(value-golden_value)) > half_width {
+					t.Errorf("%s: sampled %d +- %.1f, which misses %d", name, value, half_width, golden_value)
+				}
+			}
+		}
+	}
+}
+
+// TestResolveConfig resolves the default architecture twice, which is allowed, and then a different
+// one, which fails, as the components of the process keep the architecture that is resolved first.
+func TestResolveConfig(t *testing.T) {
+	for i := 0; i < 2; i++ {
+		command_line_parser := InitCommandLineParser()
+		command_line_parser.Parse([]string{"uPIMulator"})
+
+		ResolveConfig(command_line_parser)
+	}
+
+	command_line_parser := InitCommandLineParser()
+	command_line_parser.Parse([]string{"uPIMulator", "--config_overrides", "wram_size=262144"})
+
+	defer func() {
+		if recovered := recover(); recovered == nil {
+			t.Fatalf("resolving a different architecture does not fail")
+		}
+
+		config_loader := new(misc.ConfigLoader)
+		config_loader.Init()
+
+		if config_loader.WramSize() != 128*1024 {
+			t.Fatalf("WRAM size is resolved to %d, expected the default", config_loader.WramSize())
+		}
+	}()
+
+	ResolveConfig(command_line_parser)
+}
+
+// TestArchitecture links, assembles, and simulates VA on an architecture of twice the WRAM and of
+// four times the stack, which the stacks of the tasklets do not fit in otherwise, with more than
+// 16 tasklets. The kernel under testdata/architecture is that of testdata/kernels/VA, but its crt0
+// has a stack for each of the 24 tasklets and each tasklet buffers its blocks in its stack, so that
+// the tasklets past the default WRAM load and store there with DMA, and the host check of VA holds
+// only if the layout of the linker, the loading of the host, and the DMA all follow the
+// architecture. The architecture is resolved once
+// per process, so that the test runs in a process of its own.
+func TestArchitecture(t *testing.T) {
+	bin_dirpath := os.Getenv("UPIMULATOR_ARCHITECTURE_BIN_DIRPATH")
+	if bin_dirpath == "" {
+		command := exec.Command(os.Args[0], "-test.run=^TestArchitecture$")
+		command.Env = append(os.Environ(), "UPIMULATOR_ARCHITECTURE_BIN_DIRPATH="+t.TempDir())
+
+		if output, err := command.CombinedOutput(); err != nil {
+			t.Fatalf("simulation of the architecture fails: %v\n%s", err, output)
+		}
+		return
+	}
+
+	command_line_parser := InitCommandLineParser()
+	command_line_parser.Parse([]string{
+		"uPIMulator",
+		"--benchmark",
+		"VA",
+		"--num_tasklets",
+		"20",
+		"--data_prep_params",
+		"2048",
+		"--root_dirpath",
+		filepath.Join("testdata", "architecture"),
+		"--bin_dirpath",
+		bin_dirpath,
+		"--config_overrides",
+		"wram_size=262144,stack_size=8192",
+	})
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			simulation_error := new(misc.SimulationError)
+			simulation_error.InitRecovered(recovered, misc.INTERNAL_ERROR)
+
+			t.Fatalf("simulation of the architecture has failed: %s", simulation_error.Error())
+		}
+	}()
+
+	ResolveConfig(command_line_parser)
+
+	command_line_validator := new(misc.CommandLineValidator)
+	command_line_validator.Init(command_line_parser)
+	command_line_validator.Validate()
+
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	config_validator := new(misc.ConfigValidator)
+	config_validator.Init(config_loader)
+	config_validator.Validate()
+
+	linker_ := new(linker.Linker)
+	linker_.Init(command_line_parser)
+	linker_.Link()
+
+	assembler_ := new(assembler.Assembler)
+	assembler_.Init(command_line_parser)
+	assembler_.Assemble()
+
+	simulator_ := new(simulator.Simulator)
+	simulator_.Init(command_line_parser)
+
+	// the stack of the last tasklet lies past the default WRAM
+	if stack := simulator_.Host().Values()["__sys_stack_thread_19"]; stack < 512+128*1024 {
+		t.Fatalf("stack of tasklet 19 is at 0x%x, expected past the default WRAM", stack)
+	}
+
+	for !simulator_.IsFinished() {
+		simulator_.Cycle()
+	}
+
+	if simulator_.IsHung() {
+		t.Fatalf("simulation of the architecture makes no progress at cycle %d", simulator_.Cycles())
+	}
+
+	simulator_.Fini()
+}
@@ -0,0 +1,147 @@
+package misc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A CallGraphProfiler keeps a shadow call stack per tasklet of a DPU. A call instruction pushes a
+// frame with the return address of the call, and a tasklet that reaches the return address of a
+// frame pops it together with the frames above it. The cycles, the issued instructions, and the
+// DMA bytes are counted per distinct stack, i.e., as folded stacks, from which the inclusive and
+// exclusive counts of the functions follow. A profiler is not thread-safe, so it must only be
+// accessed by the goroutine that cycles its DPU.
+type CallGraphProfiler struct {
+	functions map[int64]string
+
+	stacks     map[int][]string
+	return_pcs map[int][]int64
+	keys       map[int]string
+
+	num_cycles       map[string]int64
+	num_instructions map[string]int64
+	num_dma_bytes    map[string]int64
+	num_calls        map[string]int64
+}
+
+func (this *CallGraphProfiler) Init(functions map[int64]string) {
+	this.functions = functions
+
+	this.stacks = make(map[int][]string, 0)
+	this.return_pcs = make(map[int][]int64, 0)
+	this.keys = make(map[int]string, 0)
+
+	this.Reset()
+}
+
+func (this *CallGraphProfiler) Reset() {
+	this.num_cycles = make(map[string]int64, 0)
+	this.num_instructions = make(map[string]int64, 0)
+	this.num_dma_bytes = make(map[string]int64, 0)
+	this.num_calls = make(map[string]int64, 0)
+}
+
+func (this *CallGraphProfiler) NumCycles() map[string]int64 {
+	return this.num_cycles
+}
+
+func (this *CallGraphProfiler) NumInstructions() map[string]int64 {
+	return this.num_instructions
+}
+
+func (this *CallGraphProfiler) NumDmaBytes() map[string]int64 {
+	return this.num_dma_bytes
+}
+
+func (this *CallGraphProfiler) NumCalls() map[string]int64 {
+	return this.num_calls
+}
+
+func (this *CallGraphProfiler) Function(pc int64) string {
+	if function, found := this.functions[pc]; found {
+		return function
+	}
+	return fmt.Sprintf("0x%x", pc)
+}
+
+// Issue counts an instruction issued by the tasklet at the PC. The first instruction of a tasklet
+// opens the root frame of its stack.
+func (this *CallGraphProfiler) Issue(thread_id int, pc int64) {
+	if len(this.stacks[thread_id]) == 0 {
+		this.stacks[thread_id] = []string{this.Function(pc)}
+		this.return_pcs[thread_id] = []int64{-1}
+		this.UpdateKey(thread_id)
+	} else {
+		for i := len(this.return_pcs[thread_id]) - 1; i > 0; i-- {
+			if this.return_pcs[thread_id][i] == pc {
+				this.stacks[thread_id] = this.stacks[thread_id][:i]
+				this.return_pcs[thread_id] = this.return_pcs[thread_id][:i]
+				this.UpdateKey(thread_id)
+				break
+			}
+		}
+	}
+
+	this.num_instructions[this.keys[thread_id]]++
+}
+
+func (this *CallGraphProfiler) Call(thread_id int, target_pc int64, return_pc int64) {
+	if len(this.stacks[thread_id]) > 0 {
+		function := this.Function(target_pc)
+
+		this.stacks[thread_id] = append(this.stacks[thread_id], function)
+		this.return_pcs[thread_id] = append(this.return_pcs[thread_id], return_pc)
+		this.UpdateKey(thread_id)
+
+		this.num_calls[function]++
+	}
+}
+
+// TailCall handles a call that does not link a return address. A jump to the return address of a
+// frame is a return, so it pops the frame, whereas any other jump is a tail call that replaces the
+// function of the top frame, since it returns to the caller of its caller.
+func (this *CallGraphProfiler) TailCall(thread_id int, target_pc int64) {
+	if len(this.stacks[thread_id]) > 0 {
+		for i := len(this.return_pcs[thread_id]) - 1; i > 0; i-- {
+			if this.return_pcs[thread_id][i] == target_pc {
+				this.stacks[thread_id] = this.stacks[thread_id][:i]
+				this.return_pcs[thread_id] = this.return_pcs[thread_id][:i]
+				this.UpdateKey(thread_id)
+				return
+			}
+		}
+
+		function := this.Function(target_pc)
+
+		this.stacks[thread_id][len(this.stacks[thread_id])-1] = function
+		this.UpdateKey(thread_id)
+
+		this.num_calls[function]++
+	}
+}
+
+func (this *CallGraphProfiler) Transfer(thread_id int, num_bytes int64) {
+	if len(this.stacks[thread_id]) > 0 {
+		this.num_dma_bytes[this.keys[thread_id]] += num_bytes
+	}
+}
+
+func (this *CallGraphProfiler) Tick(thread_id int, num_cycles int64) {
+	if len(this.stacks[thread_id]) > 0 {
+		this.num_cycles[this.keys[thread_id]] += num_cycles
+	}
+}
+
+// Exit clears the stack of a tasklet that has stopped, so that it starts afresh when it is booted
+// again.
+func (this *CallGraphProfiler) Exit(thread_id int) {
+	if len(this.stacks[thread_id]) > 0 {
+		delete(this.stacks, thread_id)
+		delete(this.return_pcs, thread_id)
+		delete(this.keys, thread_id)
+	}
+}
+
+func (this *CallGraphProfiler) UpdateKey(thread_id int) {
+	this.keys[thread_id] = strings.Join(this.stacks[thread_id], ";")
+}
@@ -0,0 +1,132 @@
+package misc
+
+import (
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"uPIMulator/src/abi/encoding"
+)
+
+type CheckpointReader struct {
+	path string
+
+	bytes      []uint8
+	pos        int
+	references map[int64]Checkpointable
+}
+
+func (this *CheckpointReader) Init(path string) {
+	this.path = path
+
+	file, open_err := os.Open(path)
+
+	if open_err != nil {
+		panic(open_err)
+	}
+
+	defer file.Close()
+
+	gzip_reader, gzip_err := gzip.NewReader(file)
+
+	if gzip_err != nil {
+		panic(gzip_err)
+	}
+
+	bytes, read_err := io.ReadAll(gzip_reader)
+
+	if read_err != nil {
+		panic(read_err)
+	}
+
+	this.bytes = bytes
+	this.pos = 0
+	this.references = make(map[int64]Checkpointable, 0)
+}
+
+func (this *CheckpointReader) IsEnd() bool {
+	return this.pos == len(this.bytes)
+}
+
+func (this *CheckpointReader) ReadInt() int64 {
+	this.Verify(8)
+
+	value := int64(binary.LittleEndian.Uint64(this.bytes[this.pos : this.pos+8]))
+	this.pos += 8
+	return value
+}
+
+func (this *CheckpointReader) ReadBool() bool {
+	this.Verify(1)
+
+	value := this.bytes[this.pos]
+	this.pos++
+
+	if value == 1 {
+		return true
+	} else if value == 0 {
+		return false
+	} else {
+		err := errors.New("checkpoint bool is corrupted")
+		panic(err)
+	}
+}
+
+func (this *CheckpointReader) ReadString() string {
+	size := int(this.ReadInt())
+
+	this.Verify(size)
+
+	value := string(this.bytes[this.pos : this.pos+size])
+	this.pos += size
+	return value
+}
+
+func (this *CheckpointReader) ReadByteStream() *encoding.ByteStream {
+	size := int(this.ReadInt())
+
+	this.Verify(size)
+
+	byte_stream := new(encoding.ByteStream)
+	byte_stream.Init()
+	for i := 0; i < size; i++ {
+		byte_stream.Append(this.bytes[this.pos+i])
+	}
+
+	this.pos += size
+	return byte_stream
+}
+
+func (this *CheckpointReader) ReadObject(object Checkpointable) Checkpointable {
+	if !this.ReadBool() {
+		return nil
+	}
+
+	reference := this.ReadInt()
+
+	if this.ReadBool() {
+		if _, found := this.references[reference]; found {
+			err := errors.New("checkpoint reference is already restored")
+			panic(err)
+		}
+
+		this.references[reference] = object
+		object.Restore(this)
+		return object
+	} else {
+		if _, found := this.references[reference]; !found {
+			err := errors.New("checkpoint reference is not restored")
+			panic(err)
+		}
+
+		return this.references[reference]
+	}
+}
+
+func (this *CheckpointReader) Verify(size int) {
+	if size < 0 || this.pos+size > len(this.bytes) {
+		err := errors.New("checkpoint is truncated")
+		panic(err)
+	}
+}
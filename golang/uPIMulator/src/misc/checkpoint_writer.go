@@ -0,0 +1,97 @@
+package misc
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"os"
+	"reflect"
+	"uPIMulator/src/abi/encoding"
+)
+
+type CheckpointWriter struct {
+	path string
+
+	bytes      []uint8
+	references map[Checkpointable]int64
+}
+
+func (this *CheckpointWriter) Init(path string) {
+	this.path = path
+
+	this.bytes = make([]uint8, 0)
+	this.references = make(map[Checkpointable]int64, 0)
+}
+
+func (this *CheckpointWriter) WriteInt(value int64) {
+	this.bytes = binary.LittleEndian.AppendUint64(this.bytes, uint64(value))
+}
+
+func (this *CheckpointWriter) WriteBool(value bool) {
+	if value {
+		this.bytes = append(this.bytes, 1)
+	} else {
+		this.bytes = append(this.bytes, 0)
+	}
+}
+
+func (this *CheckpointWriter) WriteString(value string) {
+	this.WriteInt(int64(len(value)))
+	this.bytes = append(this.bytes, value...)
+}
+
+func (this *CheckpointWriter) WriteByteStream(byte_stream *encoding.ByteStream) {
+	this.WriteInt(byte_stream.Size())
+	for i := int64(0); i < byte_stream.Size(); i++ {
+		this.bytes = append(this.bytes, byte_stream.Get(int(i)))
+	}
+}
+
+func (this *CheckpointWriter) WriteObject(object Checkpointable) {
+	if object == nil || reflect.ValueOf(object).IsNil() {
+		this.WriteBool(false)
+		return
+	}
+
+	this.WriteBool(true)
+
+	if reference, found := this.references[object]; found {
+		this.WriteInt(reference)
+		this.WriteBool(false)
+	} else {
+		reference = int64(len(this.references))
+		this.references[object] = reference
+
+		this.WriteInt(reference)
+		this.WriteBool(true)
+
+		object.Checkpoint(this)
+	}
+}
+
+func (this *CheckpointWriter) Flush() {
+	file, create_err := os.Create(this.path)
+
+	if create_err != nil {
+		panic(create_err)
+	}
+
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	gzip_writer := gzip.NewWriter(writer)
+
+	_, write_err := gzip_writer.Write(this.bytes)
+
+	if write_err != nil {
+		panic(write_err)
+	}
+
+	close_err := gzip_writer.Close()
+
+	if close_err != nil {
+		panic(close_err)
+	}
+
+	writer.Flush()
+}
@@ -0,0 +1,6 @@
+package misc
+
+type Checkpointable interface {
+	Checkpoint(checkpoint_writer *CheckpointWriter)
+	Restore(checkpoint_reader *CheckpointReader)
+}
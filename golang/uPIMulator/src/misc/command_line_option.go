@@ -18,6 +18,7 @@ type CommandLineOption struct {
 	command_line_option_type CommandLineOptionType
 	option                   string
 	default_parameter        string
+	config_parameter         string
 	custom_parameter         string
 	help_msg                 string
 }
@@ -31,6 +32,7 @@ func (this *CommandLineOption) Init(
 	this.command_line_option_type = command_line_option_type
 	this.option = option
 	this.default_parameter = default_parameter
+	this.config_parameter = ""
 	this.custom_parameter = ""
 	this.help_msg = help_msg
 }
@@ -43,14 +45,23 @@ func (this *CommandLineOption) Option() string {
 	return this.option
 }
 
+// Parameter returns the parameter given on the command line, or else the one given by the config
+// file, or else the default one.
 func (this *CommandLineOption) Parameter() string {
-	if this.custom_parameter == "" {
-		return this.default_parameter
-	} else {
+	if this.custom_parameter != "" {
 		return this.custom_parameter
+	} else if this.config_parameter != "" {
+		return this.config_parameter
+	} else {
+		return this.default_parameter
 	}
 }
 
+// IsCustom returns whether the parameter is given on the command line.
+func (this *CommandLineOption) IsCustom() bool {
+	return this.custom_parameter != ""
+}
+
 func (this *CommandLineOption) HelpMsg() string {
 	return this.help_msg
 }
@@ -61,9 +72,35 @@ func (this *CommandLineOption) SetCustomParameter(custom_parameter string) {
 		panic(err)
 	}
 
+	this.CheckParameter(custom_parameter)
+
 	this.custom_parameter = custom_parameter
 }
 
+// SetConfigParameter sets the parameter given by the config file, which the config files that
+// are applied later override.
+func (this *CommandLineOption) SetConfigParameter(config_parameter string) {
+	this.CheckParameter(config_parameter)
+
+	this.config_parameter = config_parameter
+}
+
+func (this *CommandLineOption) CheckParameter(parameter string) {
+	if this.command_line_option_type == BOOL {
+		if parameter != "true" && parameter != "false" {
+			err_msg := fmt.Sprintf("parameter (%s) of option (%s) is not true or false", parameter, this.option)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+	} else if this.command_line_option_type == INT {
+		if _, parse_err := strconv.ParseInt(parameter, 10, 64); parse_err != nil {
+			err_msg := fmt.Sprintf("parameter (%s) of option (%s) is not an integer", parameter, this.option)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+	}
+}
+
 func (this *CommandLineOption) BoolParameter() bool {
 	if this.Parameter() == "true" {
 		return true
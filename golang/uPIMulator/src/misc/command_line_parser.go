@@ -39,14 +39,25 @@ func (this *CommandLineParser) Parse(os_args []string) {
 	for i := 1; i < len(os_args); i++ {
 		os_arg := os_args[i]
 
-		if os_arg[0:2] == "--" {
+		if strings.HasPrefix(os_arg, "--") {
 			option := os_arg[2:]
+
+			if _, found := this.command_line_options[option]; !found {
+				err_msg := fmt.Sprintf("option (%s) is not found", option)
+				err := errors.New(err_msg)
+				panic(err)
+			} else if i+1 == len(os_args) {
+				err_msg := fmt.Sprintf("option (%s) has no parameter", option)
+				err := errors.New(err_msg)
+				panic(err)
+			}
+
 			custom_parameter := os_args[i+1]
 
 			this.command_line_options[option].SetCustomParameter(custom_parameter)
 
 			i++
-		} else if os_arg[0:1] == "-" {
+		} else if strings.HasPrefix(os_arg, "-") {
 			arg := os_arg[1:]
 
 			if _, found := this.args[arg]; found {
@@ -63,6 +74,26 @@ func (this *CommandLineParser) Parse(os_args []string) {
 	}
 }
 
+func (this *CommandLineParser) SetConfigParameter(option string, config_parameter string) {
+	if _, found := this.command_line_options[option]; !found {
+		err_msg := fmt.Sprintf("option (%s) is not found", option)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	this.command_line_options[option].SetConfigParameter(config_parameter)
+}
+
+func (this *CommandLineParser) CommandLineOptionType(option string) CommandLineOptionType {
+	if _, found := this.command_line_options[option]; !found {
+		err_msg := fmt.Sprintf("option (%s) is not found", option)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	return this.command_line_options[option].CommandLineOptionType()
+}
+
 func (this *CommandLineParser) BoolParameter(option string) bool {
 	if _, found := this.command_line_options[option]; !found {
 		err_msg := fmt.Sprintf("option (%s) is not found", option)
@@ -96,6 +127,28 @@ func (this *CommandLineParser) StringParameter(option string) string {
 	return command_line_option.StringParameter()
 }
 
+func (this *CommandLineParser) Parameter(option string) string {
+	if _, found := this.command_line_options[option]; !found {
+		err_msg := fmt.Sprintf("option (%s) is not found", option)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	command_line_option := this.command_line_options[option]
+	return command_line_option.Parameter()
+}
+
+// CustomParameters returns the parameters of the options that are given on the command line.
+func (this *CommandLineParser) CustomParameters() map[string]string {
+	custom_parameters := make(map[string]string, 0)
+	for option, command_line_option := range this.command_line_options {
+		if command_line_option.IsCustom() {
+			custom_parameters[option] = command_line_option.Parameter()
+		}
+	}
+	return custom_parameters
+}
+
 func (this *CommandLineParser) DataPrepParams() []int {
 	string_params := strings.Split(this.StringParameter("data_prep_params"), ",")
 
@@ -111,6 +164,28 @@ func (this *CommandLineParser) DataPrepParams() []int {
 	return data_prep_params
 }
 
+// ConfigOverrides returns the parameters of config_overrides, which are given as a comma-separated
+// list of name=value pairs.
+func (this *CommandLineParser) ConfigOverrides() map[string]string {
+	config_overrides := make(map[string]string, 0)
+
+	if this.StringParameter("config_overrides") == "" {
+		return config_overrides
+	}
+
+	for _, config_override := range strings.Split(this.StringParameter("config_overrides"), ",") {
+		name, value, found := strings.Cut(config_override, "=")
+		if !found {
+			err_msg := fmt.Sprintf("config override (%s) is not a name=value pair", config_override)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+
+		config_overrides[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	return config_overrides
+}
+
 func (this *CommandLineParser) IsArgSet(arg string) bool {
 	if _, found := this.args[arg]; found {
 		return true
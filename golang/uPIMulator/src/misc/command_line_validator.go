@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 )
 
 type CommandLineValidator struct {
@@ -40,6 +41,14 @@ func (this *CommandLineValidator) Validate() {
 		panic(err)
 	}
 
+	config_loader := new(ConfigLoader)
+	config_loader.Init()
+
+	if this.command_line_parser.IntParameter("num_tasklets") > int64(config_loader.MaxNumTasklets()) {
+		err := errors.New("num_tasklets > max num tasklets")
+		panic(err)
+	}
+
 	if _, stat_err := os.Stat(this.command_line_parser.StringParameter("root_dirpath")); os.IsNotExist(
 		stat_err,
 	) {
@@ -118,4 +127,96 @@ func (this *CommandLineValidator) Validate() {
 		err := errors.New("write_bandwidth <= 0")
 		panic(err)
 	}
+
+	if this.command_line_parser.IntParameter("checkpoint_period") < 0 {
+		err := errors.New("checkpoint_period < 0")
+		panic(err)
+	}
+
+	if restore_filepath := this.command_line_parser.StringParameter("restore_filepath"); restore_filepath != "" {
+		if _, stat_err := os.Stat(restore_filepath); os.IsNotExist(stat_err) {
+			err := errors.New("restore_filepath does not exist")
+			panic(err)
+		}
+	}
+
+	mode := this.command_line_parser.StringParameter("mode")
+	if mode != "timing" && mode != "functional" && mode != "sampled" {
+		err := errors.New("mode is not timing, functional, or sampled")
+		panic(err)
+	} else if mode != "timing" && this.command_line_parser.BoolParameter("fast_forward") {
+		err := errors.New("fast_forward is only supported in the timing mode")
+		panic(err)
+	}
+
+	if this.command_line_parser.IntParameter("sampling_period") <= 0 {
+		err := errors.New("sampling_period <= 0")
+		panic(err)
+	}
+
+	if this.command_line_parser.IntParameter("sampling_warmup") < 0 {
+		err := errors.New("sampling_warmup < 0")
+		panic(err)
+	}
+
+	if this.command_line_parser.IntParameter("sampling_window") <= 0 {
+		err := errors.New("sampling_window <= 0")
+		panic(err)
+	}
+
+	for _, stats_format := range strings.Split(this.command_line_parser.StringParameter("stats_format"), ",") {
+		if stats_format != "text" && stats_format != "json" && stats_format != "csv" {
+			err := errors.New("stats_format is not a list of text, json, or csv")
+			panic(err)
+		}
+	}
+
+	if this.command_line_parser.IntParameter("interval_period") < 0 {
+		err := errors.New("interval_period < 0")
+		panic(err)
+	}
+
+	if debug_script := this.command_line_parser.StringParameter("debug_script"); debug_script != "" {
+		if !this.command_line_parser.BoolParameter("debug") {
+			err := errors.New("debug_script is only supported with debug")
+			panic(err)
+		} else if _, stat_err := os.Stat(debug_script); os.IsNotExist(stat_err) {
+			err := errors.New("debug_script does not exist")
+			panic(err)
+		}
+	}
+
+	if gdb_port := this.command_line_parser.IntParameter("gdb_port"); gdb_port < 0 || gdb_port > 65535 {
+		err := errors.New("gdb_port is not between 0 and 65535")
+		panic(err)
+	} else if gdb_port > 0 && this.command_line_parser.BoolParameter("debug") {
+		err := errors.New("gdb_port is not supported with debug")
+		panic(err)
+	}
+
+	if this.command_line_parser.IntParameter("gdb_dpu") < 0 {
+		err := errors.New("gdb_dpu < 0")
+		panic(err)
+	}
+
+	if this.command_line_parser.IntParameter("watchdog_window") < 0 {
+		err := errors.New("watchdog_window < 0")
+		panic(err)
+	}
+
+	if this.command_line_parser.IntParameter("watchdog_history") < 0 {
+		err := errors.New("watchdog_history < 0")
+		panic(err)
+	}
+
+	if this.command_line_parser.IntParameter("num_sweep_jobs") <= 0 {
+		err := errors.New("num_sweep_jobs <= 0")
+		panic(err)
+	}
+
+	if this.command_line_parser.BoolParameter("build_only") &&
+		this.command_line_parser.BoolParameter("skip_build") {
+		err := errors.New("build_only and skip_build are both set")
+		panic(err)
+	}
 }
@@ -0,0 +1,162 @@
+package misc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+)
+
+// A ConfigFile is a JSON file that sets the architecture of the DPUs and the command line options
+// of a run, e.g.,
+//
+//	{
+//	  "include": ["upmem.json"],
+//	  "architecture": {"wram_size": 65536, "max_num_tasklets": 16},
+//	  "options": {"benchmark": "VA", "num_tasklets": 16, "fast_forward": true}
+//	}
+//
+// The included files are resolved relative to the file that includes them and applied first, in
+// order, so that the file overrides what it includes. The architecture takes the names of the
+// parameters of the ConfigLoader, and the options the names of the command line options.
+type ConfigFile struct {
+	architecture map[string]int64
+	options      map[string]interface{}
+}
+
+func (this *ConfigFile) Init(path string) {
+	this.architecture = make(map[string]int64, 0)
+	this.options = make(map[string]interface{}, 0)
+
+	this.Include(path, make([]string, 0))
+}
+
+// InitResolved initializes the config file with the architecture and the options that the run has
+// resolved, so that writing it out gives a config file that reproduces the run.
+func (this *ConfigFile) InitResolved(
+	config_loader *ConfigLoader,
+	command_line_parser *CommandLineParser,
+) {
+	this.architecture = config_loader.Values()
+	this.options = make(map[string]interface{}, 0)
+
+	for _, option := range command_line_parser.Options() {
+		command_line_option_type := command_line_parser.CommandLineOptionType(option)
+
+		if command_line_option_type == BOOL {
+			this.options[option] = command_line_parser.BoolParameter(option)
+		} else if command_line_option_type == INT {
+			this.options[option] = command_line_parser.IntParameter(option)
+		} else {
+			this.options[option] = command_line_parser.StringParameter(option)
+		}
+	}
+}
+
+func (this *ConfigFile) Architecture() map[string]int64 {
+	return this.architecture
+}
+
+// Options returns the options as they would be given on the command line.
+func (this *ConfigFile) Options() map[string]string {
+	options := make(map[string]string, 0)
+	for option, value := range this.options {
+		if bool_value, ok := value.(bool); ok {
+			options[option] = strconv.FormatBool(bool_value)
+		} else {
+			options[option] = fmt.Sprintf("%v", value)
+		}
+	}
+	return options
+}
+
+func (this *ConfigFile) Include(path string, including_paths []string) {
+	abs_path, abs_err := filepath.Abs(path)
+	if abs_err != nil {
+		panic(abs_err)
+	}
+
+	if slices.Contains(including_paths, abs_path) {
+		err_msg := fmt.Sprintf("config file (%s) is included in a cycle", path)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	content, read_err := os.ReadFile(abs_path)
+	if read_err != nil {
+		err_msg := fmt.Sprintf("config file (%s) cannot be read: %s", path, read_err.Error())
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	var sections struct {
+		Include      []string               `json:"include"`
+		Architecture map[string]json.Number `json:"architecture"`
+		Options      map[string]interface{} `json:"options"`
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	decoder.DisallowUnknownFields()
+
+	if decode_err := decoder.Decode(&sections); decode_err != nil {
+		err_msg := fmt.Sprintf("config file (%s) is not valid: %s", path, decode_err.Error())
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	including_paths = append(slices.Clone(including_paths), abs_path)
+
+	for _, include := range sections.Include {
+		if !filepath.IsAbs(include) {
+			include = filepath.Join(filepath.Dir(abs_path), include)
+		}
+
+		this.Include(include, including_paths)
+	}
+
+	for parameter, number := range sections.Architecture {
+		value, parse_err := number.Int64()
+		if parse_err != nil {
+			err_msg := fmt.Sprintf("architecture parameter (%s) in config file (%s) is not an integer", parameter, path)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+
+		this.architecture[parameter] = value
+	}
+
+	for option, value := range sections.Options {
+		_, is_bool := value.(bool)
+		_, is_number := value.(json.Number)
+		_, is_string := value.(string)
+
+		if !is_bool && !is_number && !is_string {
+			err_msg := fmt.Sprintf("option (%s) in config file (%s) is not a string, number, or bool", option, path)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+
+		this.options[option] = value
+	}
+}
+
+func (this *ConfigFile) Write(path string) {
+	sections := map[string]interface{}{
+		"architecture": this.architecture,
+		"options":      this.options,
+	}
+
+	bytes, err := json.MarshalIndent(sections, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+
+	file_dumper := new(FileDumper)
+	file_dumper.Init(path)
+	file_dumper.WriteLines([]string{string(bytes)})
+}
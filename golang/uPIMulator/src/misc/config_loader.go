@@ -1,75 +1,238 @@
 package misc
 
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// The architecture that the config file of the run has resolved, or nil to keep the defaults. It is
+// resolved once per process before the components are initialized, and every config loader starts
+// from it, so that the components that create a config loader of their own all see the same
+// architecture.
+var resolved_config_loader *ConfigLoader = nil
+
 type ConfigLoader struct {
+	address_width     int
+	atomic_data_width int
+	atomic_offset     int64
+	atomic_size       int64
+	iram_data_width   int
+	iram_offset       int64
+	iram_size         int64
+	wram_data_width   int
+	wram_offset       int64
+	wram_size         int64
+	mram_data_width   int
+	mram_offset       int64
+	mram_size         int64
+	stack_size        int64
+	heap_size         int64
+	num_gp_registers  int
+	max_num_tasklets  int
 }
 
 func (this *ConfigLoader) Init() {
+	if resolved_config_loader != nil {
+		*this = *resolved_config_loader
+		return
+	}
+
+	this.InitDefault()
+}
+
+// InitDefault initializes the config loader with the default architecture, whether or not another
+// one is resolved.
+func (this *ConfigLoader) InitDefault() {
+	this.address_width = 32
+	this.atomic_data_width = 32
+	this.atomic_offset = 0
+	this.atomic_size = 256
+	this.iram_data_width = 96
+	this.iram_offset = 384 * 1024
+	this.iram_size = 48 * 1024
+	this.wram_data_width = 32
+	this.wram_offset = 512
+	this.wram_size = 128 * 1024
+	this.mram_data_width = 32
+	this.mram_offset = 512 * 1024
+	this.mram_size = 64 * 1024 * 1024
+	this.stack_size = 2 * 1024
+	this.heap_size = 4 * 1024
+	this.num_gp_registers = 24
+	this.max_num_tasklets = 24
+}
+
+// Resolve makes the architecture of the config loader the one that every config loader of the
+// process is initialized with from now on. The architecture is resolved once per process, as the
+// components keep what they have been initialized with, so that resolving it again is only allowed
+// to the same architecture.
+func (this *ConfigLoader) Resolve() {
+	if resolved_config_loader != nil {
+		resolved_values := resolved_config_loader.Values()
+		for _, parameter := range this.Parameters() {
+			if value := this.Values()[parameter]; value != resolved_values[parameter] {
+				err_msg := fmt.Sprintf(
+					"architecture parameter (%s) is already resolved to %d, not %d",
+					parameter,
+					resolved_values[parameter],
+					value,
+				)
+				err := errors.New(err_msg)
+				panic(err)
+			}
+		}
+
+		return
+	}
+
+	resolved_config_loader = new(ConfigLoader)
+	*resolved_config_loader = *this
+}
+
+func (this *ConfigLoader) Parameters() []string {
+	parameters := make([]string, 0)
+	for parameter := range this.Values() {
+		parameters = append(parameters, parameter)
+	}
+
+	slices.Sort(parameters)
+	return parameters
+}
+
+func (this *ConfigLoader) HasParameter(parameter string) bool {
+	_, found := this.Values()[parameter]
+	return found
+}
+
+func (this *ConfigLoader) Values() map[string]int64 {
+	return map[string]int64{
+		"address_width":     int64(this.address_width),
+		"atomic_data_width": int64(this.atomic_data_width),
+		"atomic_offset":     this.atomic_offset,
+		"atomic_size":       this.atomic_size,
+		"iram_data_width":   int64(this.iram_data_width),
+		"iram_offset":       this.iram_offset,
+		"iram_size":         this.iram_size,
+		"wram_data_width":   int64(this.wram_data_width),
+		"wram_offset":       this.wram_offset,
+		"wram_size":         this.wram_size,
+		"mram_data_width":   int64(this.mram_data_width),
+		"mram_offset":       this.mram_offset,
+		"mram_size":         this.mram_size,
+		"stack_size":        this.stack_size,
+		"heap_size":         this.heap_size,
+		"num_gp_registers":  int64(this.num_gp_registers),
+		"max_num_tasklets":  int64(this.max_num_tasklets),
+	}
+}
+
+func (this *ConfigLoader) SetValue(parameter string, value int64) {
+	if parameter == "address_width" {
+		this.address_width = int(value)
+	} else if parameter == "atomic_data_width" {
+		this.atomic_data_width = int(value)
+	} else if parameter == "atomic_offset" {
+		this.atomic_offset = value
+	} else if parameter == "atomic_size" {
+		this.atomic_size = value
+	} else if parameter == "iram_data_width" {
+		this.iram_data_width = int(value)
+	} else if parameter == "iram_offset" {
+		this.iram_offset = value
+	} else if parameter == "iram_size" {
+		this.iram_size = value
+	} else if parameter == "wram_data_width" {
+		this.wram_data_width = int(value)
+	} else if parameter == "wram_offset" {
+		this.wram_offset = value
+	} else if parameter == "wram_size" {
+		this.wram_size = value
+	} else if parameter == "mram_data_width" {
+		this.mram_data_width = int(value)
+	} else if parameter == "mram_offset" {
+		this.mram_offset = value
+	} else if parameter == "mram_size" {
+		this.mram_size = value
+	} else if parameter == "stack_size" {
+		this.stack_size = value
+	} else if parameter == "heap_size" {
+		this.heap_size = value
+	} else if parameter == "num_gp_registers" {
+		this.num_gp_registers = int(value)
+	} else if parameter == "max_num_tasklets" {
+		this.max_num_tasklets = int(value)
+	} else {
+		err_msg := fmt.Sprintf("architecture parameter (%s) is not found", parameter)
+		err := errors.New(err_msg)
+		panic(err)
+	}
 }
 
 func (this *ConfigLoader) AddressWidth() int {
-	return 32
+	return this.address_width
 }
 
 func (this *ConfigLoader) AtomicDataWidth() int {
-	return 32
+	return this.atomic_data_width
 }
 
 func (this *ConfigLoader) AtomicOffset() int64 {
-	return 0
+	return this.atomic_offset
 }
 
 func (this *ConfigLoader) AtomicSize() int64 {
-	return 256
+	return this.atomic_size
 }
 
 func (this *ConfigLoader) IramDataWidth() int {
-	return 96
+	return this.iram_data_width
 }
 
 func (this *ConfigLoader) IramOffset() int64 {
-	return 384 * 1024
+	return this.iram_offset
 }
 
 func (this *ConfigLoader) IramSize() int64 {
-	return 48 * 1024
+	return this.iram_size
 }
 
 func (this *ConfigLoader) WramDataWidth() int {
-	return 32
+	return this.wram_data_width
 }
 
 func (this *ConfigLoader) WramOffset() int64 {
-	return 512
+	return this.wram_offset
 }
 
 func (this *ConfigLoader) WramSize() int64 {
-	return 128 * 1024
+	return this.wram_size
 }
 
 func (this *ConfigLoader) MramDataWidth() int {
-	return 32
+	return this.mram_data_width
 }
 
 func (this *ConfigLoader) MramOffset() int64 {
-	return 512 * 1024
+	return this.mram_offset
 }
 
 func (this *ConfigLoader) MramSize() int64 {
-	return 64 * 1024 * 1024
+	return this.mram_size
 }
 
 func (this *ConfigLoader) StackSize() int64 {
-	return 2 * 1024
+	return this.stack_size
 }
 
 func (this *ConfigLoader) HeapSize() int64 {
-	return 4 * 1024
+	return this.heap_size
 }
 
 func (this *ConfigLoader) NumGpRegisters() int {
-	return 24
+	return this.num_gp_registers
 }
 
 func (this *ConfigLoader) MaxNumTasklets() int {
-	return 24
+	return this.max_num_tasklets
 }
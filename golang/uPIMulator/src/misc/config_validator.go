@@ -38,6 +38,26 @@ func (this *ConfigValidator) Validate() {
 		panic(err)
 	}
 
+	if this.config_loader.AtomicDataWidth()%8 != 0 {
+		err := errors.New("atomic data width is not a multiple of 8")
+		panic(err)
+	}
+
+	if this.config_loader.IramDataWidth()%8 != 0 {
+		err := errors.New("IRAM data width is not a multiple of 8")
+		panic(err)
+	}
+
+	if this.config_loader.WramDataWidth()%8 != 0 {
+		err := errors.New("WRAM data width is not a multiple of 8")
+		panic(err)
+	}
+
+	if this.config_loader.MramDataWidth()%8 != 0 {
+		err := errors.New("MRAM data width is not a multiple of 8")
+		panic(err)
+	}
+
 	if this.config_loader.AtomicOffset() < 0 {
 		err := errors.New("atomic offset < 0")
 		panic(err)
@@ -138,6 +158,40 @@ func (this *ConfigValidator) Validate() {
 		panic(err)
 	}
 
+	address_end := int64(1) << this.config_loader.AddressWidth()
+
+	if this.config_loader.AtomicOffset()+this.config_loader.AtomicSize() > address_end {
+		err := errors.New("atomic end address does not fit in the address width")
+		panic(err)
+	}
+
+	if this.config_loader.IramOffset()+this.config_loader.IramSize() > address_end {
+		err := errors.New("IRAM end address does not fit in the address width")
+		panic(err)
+	}
+
+	if this.config_loader.WramOffset()+this.config_loader.WramSize() > address_end {
+		err := errors.New("WRAM end address does not fit in the address width")
+		panic(err)
+	}
+
+	if this.config_loader.MramOffset()+this.config_loader.MramSize() > address_end {
+		err := errors.New("MRAM end address does not fit in the address width")
+		panic(err)
+	}
+
+	// the atomic addresses are encoded in the 16-bit signed immediates of acquire and release
+	if this.config_loader.AtomicOffset()+this.config_loader.AtomicSize() > int64(1)<<15 {
+		err := errors.New("atomic end address does not fit in the immediates of acquire and release")
+		panic(err)
+	}
+
+	// the WRAM addresses of the symbols are encoded in the 24-bit signed offsets of loads and stores
+	if this.config_loader.WramOffset()+this.config_loader.WramSize() > int64(1)<<23 {
+		err := errors.New("WRAM end address does not fit in the offsets of loads and stores")
+		panic(err)
+	}
+
 	if this.config_loader.StackSize() <= 0 {
 		err := errors.New("stack size <= 0")
 		panic(err)
@@ -153,10 +207,21 @@ func (this *ConfigValidator) Validate() {
 		panic(err)
 	}
 
+	// the GP registers are paired into the double registers
+	if this.config_loader.NumGpRegisters()%2 != 0 {
+		err := errors.New("num gp registers is not even")
+		panic(err)
+	}
+
 	if this.config_loader.MaxNumTasklets() <= 0 {
 		err := errors.New("max num tasklets <= 0")
 		panic(err)
 	}
+
+	if this.config_loader.StackSize()*int64(this.config_loader.MaxNumTasklets()) > this.config_loader.WramSize() {
+		err := errors.New("stacks of max num tasklets do not fit in WRAM")
+		panic(err)
+	}
 }
 
 func (this *ConfigValidator) AreOverlapped(
@@ -0,0 +1,61 @@
+package misc
+
+import (
+	"encoding/json"
+)
+
+// A FailureReport describes the simulation error that has stopped the run in JSON, so that the
+// batch scripts can classify the failure by its kind and locate it without parsing the log. The
+// execution and the cycle are those that the simulator has reached, or -1 if the run has failed
+// before the simulator is set up.
+type FailureReport struct {
+	simulation_error *SimulationError
+
+	execution         int
+	cycles            int64
+	has_partial_stats bool
+}
+
+func (this *FailureReport) Init(simulation_error *SimulationError) {
+	this.simulation_error = simulation_error
+
+	this.execution = -1
+	this.cycles = -1
+	this.has_partial_stats = false
+}
+
+func (this *FailureReport) SetProgress(execution int, cycles int64) {
+	this.execution = execution
+	this.cycles = cycles
+}
+
+func (this *FailureReport) SetPartialStats(has_partial_stats bool) {
+	this.has_partial_stats = has_partial_stats
+}
+
+func (this *FailureReport) Write(path string) {
+	report := map[string]interface{}{
+		"kind":          this.simulation_error.StringifyKind(),
+		"exit_code":     this.simulation_error.ExitCode(),
+		"message":       this.simulation_error.Message(),
+		"component":     this.simulation_error.Component(),
+		"channel_id":    this.simulation_error.ChannelId(),
+		"rank_id":       this.simulation_error.RankId(),
+		"dpu_id":        this.simulation_error.DpuId(),
+		"tasklet":       this.simulation_error.ThreadId(),
+		"pc":            this.simulation_error.Pc(),
+		"instruction":   this.simulation_error.Instruction(),
+		"execution":     this.execution,
+		"cycles":        this.cycles,
+		"partial_stats": this.has_partial_stats,
+	}
+
+	bytes, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+
+	file_dumper := new(FileDumper)
+	file_dumper.Init(path)
+	file_dumper.WriteLines([]string{string(bytes)})
+}
@@ -0,0 +1,42 @@
+package misc
+
+import (
+	"bufio"
+	"os"
+)
+
+type FileStreamer struct {
+	file   *os.File
+	writer *bufio.Writer
+}
+
+func (this *FileStreamer) Init(path string) {
+	file, create_err := os.Create(path)
+
+	if create_err != nil {
+		panic(create_err)
+	}
+
+	this.file = file
+	this.writer = bufio.NewWriter(file)
+}
+
+func (this *FileStreamer) Fini() {
+	flush_err := this.writer.Flush()
+
+	if flush_err != nil {
+		panic(flush_err)
+	}
+
+	this.file.Close()
+}
+
+func (this *FileStreamer) WriteLines(lines []string) {
+	for _, line := range lines {
+		_, write_err := this.writer.WriteString(line + "\n")
+
+		if write_err != nil {
+			panic(write_err)
+		}
+	}
+}
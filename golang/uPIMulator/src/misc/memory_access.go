@@ -0,0 +1,39 @@
+package misc
+
+type MemoryAccess struct {
+	thread_id int
+	pc        int64
+	clock     int64
+	is_write  bool
+}
+
+func (this *MemoryAccess) Init(thread_id int, pc int64, clock int64, is_write bool) {
+	this.thread_id = thread_id
+	this.pc = pc
+	this.clock = clock
+	this.is_write = is_write
+}
+
+func (this *MemoryAccess) ThreadId() int {
+	return this.thread_id
+}
+
+func (this *MemoryAccess) Pc() int64 {
+	return this.pc
+}
+
+func (this *MemoryAccess) Clock() int64 {
+	return this.clock
+}
+
+func (this *MemoryAccess) IsWrite() bool {
+	return this.is_write
+}
+
+func (this *MemoryAccess) Kind() string {
+	if this.is_write {
+		return "write"
+	} else {
+		return "read"
+	}
+}
@@ -0,0 +1,45 @@
+package misc
+
+// A Profiler counts the issued instructions of a DPU per PC, together with the cycles that its
+// tasklets spend stalled or waiting for a DMA at the PC. The cycles are counted per tasklet, so
+// two tasklets stalled at the same PC for a cycle count as two cycles. A profiler is not
+// thread-safe, so it must only be accessed by the goroutine that cycles its DPU.
+type Profiler struct {
+	num_instructions    map[int64]int64
+	num_stall_cycles    map[int64]int64
+	num_dma_wait_cycles map[int64]int64
+}
+
+func (this *Profiler) Init() {
+	this.Reset()
+}
+
+func (this *Profiler) Reset() {
+	this.num_instructions = make(map[int64]int64, 0)
+	this.num_stall_cycles = make(map[int64]int64, 0)
+	this.num_dma_wait_cycles = make(map[int64]int64, 0)
+}
+
+func (this *Profiler) NumInstructions() map[int64]int64 {
+	return this.num_instructions
+}
+
+func (this *Profiler) NumStallCycles() map[int64]int64 {
+	return this.num_stall_cycles
+}
+
+func (this *Profiler) NumDmaWaitCycles() map[int64]int64 {
+	return this.num_dma_wait_cycles
+}
+
+func (this *Profiler) Issue(pc int64) {
+	this.num_instructions[pc]++
+}
+
+func (this *Profiler) Stall(pc int64, num_cycles int64) {
+	this.num_stall_cycles[pc] += num_cycles
+}
+
+func (this *Profiler) WaitDma(pc int64, num_cycles int64) {
+	this.num_dma_wait_cycles[pc] += num_cycles
+}
@@ -0,0 +1,81 @@
+package misc
+
+import (
+	"errors"
+)
+
+// A ProgressTracker counts the events by which the tasklets of a DPU make forward progress, i.e.,
+// the retired DMA commands and the locks that are acquired or released, and keeps the last PCs
+// that each tasklet has issued, so that a DPU that stops making progress can be reported. The
+// tasklets that finish are not counted here, since the host shuts them down outside of the DPU. A
+// progress tracker is not thread-safe, so it must only be accessed by the goroutine that cycles
+// its DPU.
+type ProgressTracker struct {
+	num_history int
+
+	num_events int64
+
+	histories  [][]int64
+	num_issues []int64
+}
+
+func (this *ProgressTracker) Init(num_tasklets int, num_history int) {
+	if num_tasklets <= 0 {
+		err := errors.New("num tasklets <= 0")
+		panic(err)
+	} else if num_history < 0 {
+		err := errors.New("num history < 0")
+		panic(err)
+	}
+
+	this.num_history = num_history
+
+	this.num_events = 0
+
+	this.histories = make([][]int64, num_tasklets)
+	this.num_issues = make([]int64, num_tasklets)
+	for i := 0; i < num_tasklets; i++ {
+		this.histories[i] = make([]int64, num_history)
+	}
+}
+
+// Reset forgets the issued PCs, e.g., after a checkpoint is restored, since they do not belong to
+// the restored run.
+func (this *ProgressTracker) Reset() {
+	for i := range this.num_issues {
+		this.num_issues[i] = 0
+	}
+}
+
+func (this *ProgressTracker) NumEvents() int64 {
+	return this.num_events
+}
+
+func (this *ProgressTracker) Progress() {
+	this.num_events++
+}
+
+func (this *ProgressTracker) Issue(thread_id int, pc int64) {
+	if this.num_history > 0 {
+		this.histories[thread_id][this.num_issues[thread_id]%int64(this.num_history)] = pc
+	}
+
+	this.num_issues[thread_id]++
+}
+
+// History returns the last PCs that the tasklet has issued, from the oldest to the latest.
+func (this *ProgressTracker) History(thread_id int) []int64 {
+	history := make([]int64, 0)
+
+	num_issues := this.num_issues[thread_id]
+	first_issue := num_issues - int64(this.num_history)
+	if first_issue < 0 {
+		first_issue = 0
+	}
+
+	for i := first_issue; i < num_issues; i++ {
+		history = append(history, this.histories[thread_id][i%int64(this.num_history)])
+	}
+
+	return history
+}
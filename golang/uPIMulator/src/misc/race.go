@@ -0,0 +1,44 @@
+package misc
+
+// A Race is a pair of conflicting accesses of two tasklets that are not ordered by any
+// synchronization, i.e., the first access does not happen before the second. The races of the same
+// pair of PCs are merged, keeping the tasklets and the address of the first occurrence.
+type Race struct {
+	first   *MemoryAccess
+	second  *MemoryAccess
+	address int64
+
+	num_occurrences int64
+}
+
+func (this *Race) Init(first *MemoryAccess, second *MemoryAccess, address int64) {
+	this.first = first
+	this.second = second
+	this.address = address
+
+	this.num_occurrences = 0
+}
+
+func (this *Race) First() *MemoryAccess {
+	return this.first
+}
+
+func (this *Race) Second() *MemoryAccess {
+	return this.second
+}
+
+func (this *Race) Address() int64 {
+	return this.address
+}
+
+func (this *Race) NumOccurrences() int64 {
+	return this.num_occurrences
+}
+
+func (this *Race) Kind() string {
+	return this.first.Kind() + "-" + this.second.Kind()
+}
+
+func (this *Race) Increment() {
+	this.num_occurrences++
+}
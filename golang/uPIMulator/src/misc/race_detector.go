@@ -0,0 +1,155 @@
+package misc
+
+import (
+	"errors"
+	"fmt"
+)
+
+// A RaceDetector checks the WRAM accesses of the tasklets of a DPU for data races. It keeps a
+// vector clock per tasklet and per lock, and joins them along the happens-before edges that the
+// tasklets synchronize with, i.e., from the release of a lock to its next acquire, and from a
+// tasklet that boots or resumes another one to the tasklet that it boots or resumes. The barriers,
+// the handshakes, and the semaphores of the syslib are built upon these, so they need no special
+// care. Every byte of the WRAM remembers its last write and the last read of every tasklet, and an
+// access that conflicts with one of another tasklet that does not happen before it is a race. A
+// detector is not thread-safe, so it must only be accessed by the goroutine that cycles its DPU.
+type RaceDetector struct {
+	num_tasklets int
+
+	clocks      [][]int64
+	lock_clocks map[int64][]int64
+
+	writes map[int64]*MemoryAccess
+	reads  map[int64][]*MemoryAccess
+
+	races     []*Race
+	race_keys map[string]*Race
+}
+
+func (this *RaceDetector) Init(num_tasklets int) {
+	if num_tasklets <= 0 {
+		err := errors.New("num tasklets <= 0")
+		panic(err)
+	}
+
+	this.num_tasklets = num_tasklets
+
+	this.Launch()
+	this.Reset()
+}
+
+// Launch forgets the accesses and the synchronization of the previous launch of the DPU, since the
+// host waits for a launch to finish before the next one.
+func (this *RaceDetector) Launch() {
+	this.clocks = make([][]int64, this.num_tasklets)
+	for i := 0; i < this.num_tasklets; i++ {
+		this.clocks[i] = make([]int64, this.num_tasklets)
+		this.clocks[i][i] = 1
+	}
+
+	this.lock_clocks = make(map[int64][]int64, 0)
+
+	this.writes = make(map[int64]*MemoryAccess, 0)
+	this.reads = make(map[int64][]*MemoryAccess, 0)
+}
+
+func (this *RaceDetector) Reset() {
+	this.races = make([]*Race, 0)
+	this.race_keys = make(map[string]*Race, 0)
+}
+
+func (this *RaceDetector) Races() []*Race {
+	return this.races
+}
+
+// Access checks the bytes that the tasklet accesses at the PC against the last conflicting accesses
+// of the other tasklets. A race is only counted once per access, however many bytes it spans.
+func (this *RaceDetector) Access(thread_id int, pc int64, address int64, size int64, is_write bool) {
+	clock := this.clocks[thread_id]
+
+	access := new(MemoryAccess)
+	access.Init(thread_id, pc, clock[thread_id], is_write)
+
+	races := make(map[*Race]bool, 0)
+
+	for i := address; i < address+size; i++ {
+		if write, found := this.writes[i]; found && !this.HappensBefore(write, clock) {
+			races[this.Conflict(write, access, i)] = true
+		}
+
+		if is_write {
+			for _, read := range this.reads[i] {
+				if read != nil && !this.HappensBefore(read, clock) {
+					races[this.Conflict(read, access, i)] = true
+				}
+			}
+
+			this.writes[i] = access
+			delete(this.reads, i)
+		} else {
+			if _, found := this.reads[i]; !found {
+				this.reads[i] = make([]*MemoryAccess, this.num_tasklets)
+			}
+
+			this.reads[i][thread_id] = access
+		}
+	}
+
+	for race, _ := range races {
+		race.Increment()
+	}
+}
+
+func (this *RaceDetector) Acquire(thread_id int, lock_address int64) {
+	if lock_clock, found := this.lock_clocks[lock_address]; found {
+		this.Join(this.clocks[thread_id], lock_clock)
+	}
+}
+
+func (this *RaceDetector) Release(thread_id int, lock_address int64) {
+	lock_clock := make([]int64, this.num_tasklets)
+	copy(lock_clock, this.clocks[thread_id])
+
+	this.lock_clocks[lock_address] = lock_clock
+	this.clocks[thread_id][thread_id]++
+}
+
+// Signal orders the accesses of a tasklet before those that the tasklet that it boots or resumes
+// makes afterwards.
+func (this *RaceDetector) Signal(thread_id int, target_thread_id int) {
+	if target_thread_id != thread_id {
+		this.Join(this.clocks[target_thread_id], this.clocks[thread_id])
+	}
+
+	this.clocks[thread_id][thread_id]++
+}
+
+func (this *RaceDetector) HappensBefore(access *MemoryAccess, clock []int64) bool {
+	return access.Clock() <= clock[access.ThreadId()]
+}
+
+func (this *RaceDetector) Join(clock []int64, other_clock []int64) {
+	for i := 0; i < this.num_tasklets; i++ {
+		if other_clock[i] > clock[i] {
+			clock[i] = other_clock[i]
+		}
+	}
+}
+
+// Conflict returns the race of the pair of PCs, which is created at its first occurrence with no
+// occurrences counted yet.
+func (this *RaceDetector) Conflict(first *MemoryAccess, second *MemoryAccess, address int64) *Race {
+	key := fmt.Sprintf("%t %d %t %d", first.IsWrite(), first.Pc(), second.IsWrite(), second.Pc())
+
+	if race, found := this.race_keys[key]; found {
+		return race
+	}
+
+	race := new(Race)
+	race.Init(first, second, address)
+
+	this.races = append(this.races, race)
+	this.race_keys[key] = race
+
+	return race
+}
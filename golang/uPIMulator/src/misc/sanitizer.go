@@ -0,0 +1,326 @@
+package misc
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// A Sanitizer checks the WRAM, MRAM, and atomic accesses and the DMA commands of the tasklets of a
+// DPU against the memory layout that the linker has assigned. A WRAM access must fall within the
+// sections, the stacks, the software cache, or the heap, and a DMA command within the MRAM and
+// aligned to the minimum access granularity. A tasklet that accesses the stack of another tasklet
+// has most likely overflowed its own stack, since the stacks are laid out next to each other.
+// The bytes that are read must have been written before, either by the loader, the host, a DMA
+// command, or a store. The global variables are zero-initialized, so the sections of the WRAM and
+// the MRAM count as written from the start. An access that falls outside the memory altogether
+// cannot be carried out, so its finding stops the simulation, whereas the others are only
+// recorded. A sanitizer is not thread-safe, so it must only be accessed by the goroutine that
+// cycles its DPU.
+type Sanitizer struct {
+	name string
+
+	min_access_granularity int64
+
+	sections_end_address int64
+	stack_addresses      []int64
+	stack_sizes          []int64
+	sw_cache_end_address int64
+	heap_address         int64
+	sys_used_mram_end    int64
+	is_all_initialized   bool
+
+	wram_bitmap map[int64]uint64
+	mram_bitmap map[int64]uint64
+
+	findings     []*SanitizerFinding
+	finding_keys map[string]*SanitizerFinding
+
+	symbol_resolver SymbolResolver
+}
+
+func (this *Sanitizer) Init(
+	name string,
+	values map[string]int64,
+	min_access_granularity int64,
+	symbol_resolver SymbolResolver,
+) {
+	if min_access_granularity <= 0 {
+		err := errors.New("min access granularity <= 0")
+		panic(err)
+	}
+
+	config_loader := new(ConfigLoader)
+	config_loader.Init()
+
+	this.name = name
+
+	this.min_access_granularity = min_access_granularity
+
+	this.stack_addresses = make([]int64, 0)
+	this.stack_sizes = make([]int64, 0)
+	for i := 0; i < config_loader.MaxNumTasklets(); i++ {
+		this.stack_addresses = append(this.stack_addresses, values["__sys_stack_thread_"+strconv.Itoa(i)])
+		this.stack_sizes = append(this.stack_sizes, values["STACK_SIZE_TASKLET_"+strconv.Itoa(i)])
+	}
+
+	this.sections_end_address = this.stack_addresses[0]
+	this.sw_cache_end_address = values["__sw_cache_buffer"] + int64(8*config_loader.MaxNumTasklets())
+	this.heap_address = values["__sys_heap_pointer_reset"]
+	this.sys_used_mram_end = values["__sys_used_mram_end"]
+	this.is_all_initialized = false
+
+	this.wram_bitmap = make(map[int64]uint64, 0)
+	this.mram_bitmap = make(map[int64]uint64, 0)
+
+	this.Initialize(this.wram_bitmap, config_loader.WramOffset(), this.sections_end_address-config_loader.WramOffset())
+	this.Initialize(this.mram_bitmap, config_loader.MramOffset(), this.sys_used_mram_end-config_loader.MramOffset())
+
+	this.symbol_resolver = symbol_resolver
+
+	this.Reset()
+}
+
+func (this *Sanitizer) Reset() {
+	this.findings = make([]*SanitizerFinding, 0)
+	this.finding_keys = make(map[string]*SanitizerFinding, 0)
+}
+
+// InitializeAll counts every byte as written, e.g., after a checkpoint is restored, since the
+// history of the restored memory is unknown.
+func (this *Sanitizer) InitializeAll() {
+	this.is_all_initialized = true
+}
+
+func (this *Sanitizer) Findings() []*SanitizerFinding {
+	return this.findings
+}
+
+func (this *Sanitizer) InitializeWram(address int64, size int64) {
+	this.Initialize(this.wram_bitmap, address, size)
+}
+
+func (this *Sanitizer) InitializeMram(address int64, size int64) {
+	this.Initialize(this.mram_bitmap, address, size)
+}
+
+func (this *Sanitizer) AccessWram(
+	thread_id int,
+	pc int64,
+	address int64,
+	size int64,
+	is_write bool,
+) {
+	this.CheckWram(thread_id, pc, address, size)
+	this.CheckStack(thread_id, pc, address, size)
+
+	if is_write {
+		this.Initialize(this.wram_bitmap, address, size)
+	} else if uninitialized_address := this.Uninitialized(this.wram_bitmap, address, size); uninitialized_address != -1 {
+		message := fmt.Sprintf(
+			"%s reads %d bytes at %s at %s, of which %s is never written",
+			this.Tasklet(thread_id),
+			size,
+			this.symbol_resolver.WramAddress(address),
+			this.symbol_resolver.Pc(pc),
+			this.symbol_resolver.WramAddress(uninitialized_address),
+		)
+		this.Find("uninitialized-read", pc, message)
+	}
+}
+
+// AccessDma checks a DMA command of the tasklet, which reads the MRAM and writes the WRAM for an
+// ldma, and the other way around for an sdma. The WRAM that an ldma writes counts as written once
+// the DMA command completes.
+func (this *Sanitizer) AccessDma(
+	thread_id int,
+	pc int64,
+	wram_address int64,
+	mram_address int64,
+	size int64,
+	is_ldma bool,
+) {
+	config_loader := new(ConfigLoader)
+	config_loader.Init()
+
+	if wram_address%this.min_access_granularity != 0 ||
+		mram_address%this.min_access_granularity != 0 ||
+		size%this.min_access_granularity != 0 {
+		message := fmt.Sprintf(
+			"%s transfers %d bytes between WRAM %s and MRAM 0x%x at %s, which is not aligned to min_access_granularity (%d)",
+			this.Tasklet(thread_id),
+			size,
+			this.symbol_resolver.WramAddress(wram_address),
+			mram_address,
+			this.symbol_resolver.Pc(pc),
+			this.min_access_granularity,
+		)
+		this.Find("misaligned-dma", pc, message)
+	}
+
+	this.CheckWram(thread_id, pc, wram_address, size)
+	this.CheckStack(thread_id, pc, wram_address, size)
+
+	mram_end_address := config_loader.MramOffset() + config_loader.MramSize()
+	if mram_address < config_loader.MramOffset() || mram_address+size > mram_end_address {
+		message := fmt.Sprintf(
+			"%s transfers %d bytes at MRAM 0x%x at %s, beyond __sys_used_mram_end (0x%x) plus the heap up to 0x%x",
+			this.Tasklet(thread_id),
+			size,
+			mram_address,
+			this.symbol_resolver.Pc(pc),
+			this.sys_used_mram_end,
+			mram_end_address,
+		)
+		this.Abort("out-of-bounds", pc, message)
+	}
+
+	var bitmap map[int64]uint64
+	var address int64
+	var memory string
+	if is_ldma {
+		bitmap = this.mram_bitmap
+		address = mram_address
+		memory = fmt.Sprintf("MRAM 0x%x", mram_address)
+	} else {
+		bitmap = this.wram_bitmap
+		address = wram_address
+		memory = fmt.Sprintf("WRAM %s", this.symbol_resolver.WramAddress(wram_address))
+	}
+
+	if uninitialized_address := this.Uninitialized(bitmap, address, size); uninitialized_address != -1 {
+		message := fmt.Sprintf(
+			"%s transfers %d bytes from %s at %s, of which 0x%x is never written",
+			this.Tasklet(thread_id),
+			size,
+			memory,
+			this.symbol_resolver.Pc(pc),
+			uninitialized_address,
+		)
+		this.Find("uninitialized-read", pc, message)
+	}
+
+	if !is_ldma {
+		this.Initialize(this.mram_bitmap, mram_address, size)
+	}
+}
+
+func (this *Sanitizer) AccessAtomic(thread_id int, pc int64, address int64) {
+	config_loader := new(ConfigLoader)
+	config_loader.Init()
+
+	atomic_end_address := config_loader.AtomicOffset() + config_loader.AtomicSize()
+	if address < config_loader.AtomicOffset() || address >= atomic_end_address {
+		message := fmt.Sprintf(
+			"%s accesses the atomic bit %d at %s outside the atomic memory of %d bits",
+			this.Tasklet(thread_id),
+			address,
+			this.symbol_resolver.Pc(pc),
+			config_loader.AtomicSize(),
+		)
+		this.Abort("out-of-bounds", pc, message)
+	}
+}
+
+// CheckWram checks that the bytes fall within the WRAM, and within the regions that the linker has
+// assigned, i.e., outside the padding between the software cache and the heap.
+func (this *Sanitizer) CheckWram(thread_id int, pc int64, address int64, size int64) {
+	config_loader := new(ConfigLoader)
+	config_loader.Init()
+
+	wram_end_address := config_loader.WramOffset() + config_loader.WramSize()
+	if address < config_loader.WramOffset() || address+size > wram_end_address {
+		message := fmt.Sprintf(
+			"%s accesses %d bytes at 0x%x at %s outside the WRAM (0x%x - 0x%x)",
+			this.Tasklet(thread_id),
+			size,
+			address,
+			this.symbol_resolver.Pc(pc),
+			config_loader.WramOffset(),
+			wram_end_address,
+		)
+		this.Abort("out-of-bounds", pc, message)
+	} else if address < this.heap_address && address+size > this.sw_cache_end_address {
+		message := fmt.Sprintf(
+			"%s accesses %d bytes at %s at %s outside the sections, the stacks, and the heap",
+			this.Tasklet(thread_id),
+			size,
+			this.symbol_resolver.WramAddress(address),
+			this.symbol_resolver.Pc(pc),
+		)
+		this.Find("out-of-bounds", pc, message)
+	}
+}
+
+func (this *Sanitizer) CheckStack(thread_id int, pc int64, address int64, size int64) {
+	for i, stack_address := range this.stack_addresses {
+		stack_end_address := stack_address + this.stack_sizes[i]
+
+		if i != thread_id && address < stack_end_address && address+size > stack_address {
+			message := fmt.Sprintf(
+				"%s accesses %d bytes at %s at %s in the stack of tasklet %d, past its own stack of STACK_SIZE_TASKLET_%d (%d) bytes",
+				this.Tasklet(thread_id),
+				size,
+				this.symbol_resolver.WramAddress(address),
+				this.symbol_resolver.Pc(pc),
+				i,
+				thread_id,
+				this.stack_sizes[thread_id],
+			)
+			this.Find("stack-overflow", pc, message)
+			return
+		}
+	}
+}
+
+func (this *Sanitizer) Tasklet(thread_id int) string {
+	return fmt.Sprintf("%s: tasklet %d", this.name, thread_id)
+}
+
+// Find records the finding, and prints it the first time that it occurs at the PC.
+func (this *Sanitizer) Find(check string, pc int64, message string) *SanitizerFinding {
+	key := fmt.Sprintf("%s %d", check, pc)
+
+	finding, found := this.finding_keys[key]
+	if !found {
+		finding = new(SanitizerFinding)
+		finding.Init(check, pc, message)
+
+		this.findings = append(this.findings, finding)
+		this.finding_keys[key] = finding
+
+		fmt.Printf("sanitizer: %s: %s\n", check, message)
+	}
+
+	finding.Increment()
+
+	return finding
+}
+
+func (this *Sanitizer) Abort(check string, pc int64, message string) {
+	this.Find(check, pc, message)
+
+	err := errors.New(message)
+	panic(err)
+}
+
+func (this *Sanitizer) Initialize(bitmap map[int64]uint64, address int64, size int64) {
+	for i := address; i < address+size; i++ {
+		bitmap[i/64] |= 1 << uint(i%64)
+	}
+}
+
+// Uninitialized returns the first of the bytes that is never written, or -1 if there is none.
+func (this *Sanitizer) Uninitialized(bitmap map[int64]uint64, address int64, size int64) int64 {
+	if this.is_all_initialized {
+		return -1
+	}
+
+	for i := address; i < address+size; i++ {
+		if bitmap[i/64]&(1<<uint(i%64)) == 0 {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,39 @@
+package misc
+
+// A SanitizerFinding is a check that an access of a tasklet has failed. The findings of the same
+// check at the same PC are merged, keeping the message of the first occurrence.
+type SanitizerFinding struct {
+	check   string
+	pc      int64
+	message string
+
+	num_occurrences int64
+}
+
+func (this *SanitizerFinding) Init(check string, pc int64, message string) {
+	this.check = check
+	this.pc = pc
+	this.message = message
+
+	this.num_occurrences = 0
+}
+
+func (this *SanitizerFinding) Check() string {
+	return this.check
+}
+
+func (this *SanitizerFinding) Pc() int64 {
+	return this.pc
+}
+
+func (this *SanitizerFinding) Message() string {
+	return this.message
+}
+
+func (this *SanitizerFinding) NumOccurrences() int64 {
+	return this.num_occurrences
+}
+
+func (this *SanitizerFinding) Increment() {
+	this.num_occurrences++
+}
@@ -0,0 +1,177 @@
+package misc
+
+import (
+	"fmt"
+	"strings"
+)
+
+type SimulationErrorKind int
+
+const (
+	INTERNAL_ERROR SimulationErrorKind = iota
+	CONFIG_ERROR
+	FUNCTIONAL_MISMATCH
+	UNSUPPORTED_INSTRUCTION
+	NO_PROGRESS
+	DPU_FAULT
+)
+
+// A SimulationError is an error that stops the simulation, classified by its kind so that the
+// process can exit with a code per kind, and annotated with where it has been raised: the
+// component, the DPU, the tasklet, and the instruction with its PC. The errors are raised by panic
+// as everywhere else, and each component that a simulation error unwinds through attaches what it
+// knows, leaving alone what an inner component has already attached. Any other value that a panic
+// raises becomes an internal error once it reaches a component.
+type SimulationError struct {
+	kind    SimulationErrorKind
+	message string
+
+	component   string
+	channel_id  int
+	rank_id     int
+	dpu_id      int
+	thread_id   int
+	pc          int64
+	instruction string
+}
+
+func (this *SimulationError) Init(kind SimulationErrorKind, message string) {
+	this.kind = kind
+	this.message = message
+
+	this.component = ""
+	this.channel_id = -1
+	this.rank_id = -1
+	this.dpu_id = -1
+	this.thread_id = -1
+	this.pc = -1
+	this.instruction = ""
+}
+
+// InitRecovered initializes the error from a value that a panic has raised, copying it if it is a
+// simulation error already, and classifying it as the kind otherwise.
+func (this *SimulationError) InitRecovered(recovered interface{}, kind SimulationErrorKind) {
+	if simulation_error, ok := recovered.(*SimulationError); ok {
+		*this = *simulation_error
+	} else if err, ok := recovered.(error); ok {
+		this.Init(kind, err.Error())
+	} else {
+		this.Init(kind, fmt.Sprintf("%v", recovered))
+	}
+}
+
+func (this *SimulationError) Kind() SimulationErrorKind {
+	return this.kind
+}
+
+func (this *SimulationError) StringifyKind() string {
+	if this.kind == INTERNAL_ERROR {
+		return "internal_error"
+	} else if this.kind == CONFIG_ERROR {
+		return "config_error"
+	} else if this.kind == FUNCTIONAL_MISMATCH {
+		return "functional_mismatch"
+	} else if this.kind == UNSUPPORTED_INSTRUCTION {
+		return "unsupported_instruction"
+	} else if this.kind == NO_PROGRESS {
+		return "no_progress"
+	} else if this.kind == DPU_FAULT {
+		return "dpu_fault"
+	} else {
+		return "unknown"
+	}
+}
+
+// ExitCode is the exit code of the process that the error has stopped. An internal error exits with
+// 2 like a panic that the Go runtime reports, so that a panic that escapes the failure report is
+// classified the same.
+func (this *SimulationError) ExitCode() int {
+	if this.kind == CONFIG_ERROR {
+		return 3
+	} else if this.kind == FUNCTIONAL_MISMATCH {
+		return 4
+	} else if this.kind == UNSUPPORTED_INSTRUCTION {
+		return 5
+	} else if this.kind == NO_PROGRESS {
+		return 6
+	} else if this.kind == DPU_FAULT {
+		return 7
+	} else {
+		return 2
+	}
+}
+
+func (this *SimulationError) Message() string {
+	return this.message
+}
+
+func (this *SimulationError) Component() string {
+	return this.component
+}
+
+func (this *SimulationError) ChannelId() int {
+	return this.channel_id
+}
+
+func (this *SimulationError) RankId() int {
+	return this.rank_id
+}
+
+func (this *SimulationError) DpuId() int {
+	return this.dpu_id
+}
+
+func (this *SimulationError) ThreadId() int {
+	return this.thread_id
+}
+
+func (this *SimulationError) Pc() int64 {
+	return this.pc
+}
+
+func (this *SimulationError) Instruction() string {
+	return this.instruction
+}
+
+func (this *SimulationError) SetComponent(component string) {
+	if this.component == "" {
+		this.component = component
+	}
+}
+
+func (this *SimulationError) SetDpu(channel_id int, rank_id int, dpu_id int) {
+	if this.dpu_id == -1 {
+		this.channel_id = channel_id
+		this.rank_id = rank_id
+		this.dpu_id = dpu_id
+	}
+}
+
+func (this *SimulationError) SetInstruction(thread_id int, pc int64, instruction string) {
+	if this.thread_id == -1 {
+		this.thread_id = thread_id
+		this.pc = pc
+		this.instruction = instruction
+	}
+}
+
+func (this *SimulationError) Error() string {
+	context := make([]string, 0)
+
+	if this.component != "" {
+		context = append(context, this.component)
+	}
+	if this.dpu_id != -1 {
+		context = append(context, fmt.Sprintf("DPU%d-%d-%d", this.channel_id, this.rank_id, this.dpu_id))
+	}
+	if this.thread_id != -1 {
+		context = append(context, fmt.Sprintf("tasklet %d", this.thread_id))
+		context = append(context, fmt.Sprintf("pc 0x%x", this.pc))
+		context = append(context, fmt.Sprintf("instruction (%s)", this.instruction))
+	}
+
+	if len(context) == 0 {
+		return fmt.Sprintf("%s: %s", this.StringifyKind(), this.message)
+	}
+	return fmt.Sprintf("%s: %s [%s]", this.StringifyKind(), this.message, strings.Join(context, ", "))
+}
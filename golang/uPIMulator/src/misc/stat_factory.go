@@ -39,9 +39,29 @@ func (this *StatFactory) Increment(stat string, value int64) {
 
 func (this *StatFactory) ToLines() []string {
 	lines := make([]string, 0)
-	for stat, value := range this.stats {
-		line := fmt.Sprintf("%s_%s: %d", this.name, stat, value)
+	for _, stat := range this.Stats() {
+		line := fmt.Sprintf("%s_%s: %d", this.name, stat, this.stats[stat])
 		lines = append(lines, line)
 	}
 	return lines
 }
+
+func (this *StatFactory) Checkpoint(checkpoint_writer *CheckpointWriter) {
+	stats := this.Stats()
+
+	checkpoint_writer.WriteInt(int64(len(stats)))
+	for _, stat := range stats {
+		checkpoint_writer.WriteString(stat)
+		checkpoint_writer.WriteInt(this.stats[stat])
+	}
+}
+
+func (this *StatFactory) Restore(checkpoint_reader *CheckpointReader) {
+	this.stats = make(map[string]int64, 0)
+
+	num_stats := int(checkpoint_reader.ReadInt())
+	for i := 0; i < num_stats; i++ {
+		stat := checkpoint_reader.ReadString()
+		this.stats[stat] = checkpoint_reader.ReadInt()
+	}
+}
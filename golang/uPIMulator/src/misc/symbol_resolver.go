@@ -0,0 +1,8 @@
+package misc
+
+// A SymbolResolver names the PCs and the WRAM addresses of the kernel, so that the checkers of the
+// DPUs can report their findings in terms of the source.
+type SymbolResolver interface {
+	Pc(pc int64) string
+	WramAddress(address int64) string
+}
@@ -0,0 +1,64 @@
+package misc
+
+type TraceEvent struct {
+	name     string
+	category string
+	phase    string
+	cycles   int64
+	duration int64
+	tid      int
+	id       int64
+	args     map[string]interface{}
+}
+
+func (this *TraceEvent) Init(
+	name string,
+	category string,
+	phase string,
+	cycles int64,
+	duration int64,
+	tid int,
+	id int64,
+	args map[string]interface{},
+) {
+	this.name = name
+	this.category = category
+	this.phase = phase
+	this.cycles = cycles
+	this.duration = duration
+	this.tid = tid
+	this.id = id
+	this.args = args
+}
+
+func (this *TraceEvent) Name() string {
+	return this.name
+}
+
+func (this *TraceEvent) Category() string {
+	return this.category
+}
+
+func (this *TraceEvent) Phase() string {
+	return this.phase
+}
+
+func (this *TraceEvent) Cycles() int64 {
+	return this.cycles
+}
+
+func (this *TraceEvent) Duration() int64 {
+	return this.duration
+}
+
+func (this *TraceEvent) Tid() int {
+	return this.tid
+}
+
+func (this *TraceEvent) Id() int64 {
+	return this.id
+}
+
+func (this *TraceEvent) Args() map[string]interface{} {
+	return this.args
+}
@@ -0,0 +1,147 @@
+package misc
+
+import (
+	"errors"
+)
+
+// A Tracer buffers the trace events of a single traced process, e.g., a DPU or the host, which are
+// time-stamped with the cycles set by the owner of the tracer. A tracer is not thread-safe, so it
+// must only be accessed by the goroutine that cycles its owner.
+type Tracer struct {
+	pid    int
+	cycles int64
+
+	states       map[int]string
+	state_cycles map[int]int64
+
+	async_id    int64
+	async_ids   map[interface{}]int64
+	async_names map[interface{}]string
+
+	events []*TraceEvent
+}
+
+func (this *Tracer) Init(pid int) {
+	if pid < 0 {
+		err := errors.New("pid < 0")
+		panic(err)
+	}
+
+	this.pid = pid
+	this.cycles = 0
+
+	this.states = make(map[int]string, 0)
+	this.state_cycles = make(map[int]int64, 0)
+
+	this.async_id = 0
+	this.async_ids = make(map[interface{}]int64, 0)
+	this.async_names = make(map[interface{}]string, 0)
+
+	this.events = make([]*TraceEvent, 0)
+}
+
+func (this *Tracer) Pid() int {
+	return this.pid
+}
+
+func (this *Tracer) Cycles() int64 {
+	return this.cycles
+}
+
+func (this *Tracer) SetCycles(cycles int64) {
+	this.cycles = cycles
+}
+
+// Transition ends the current state of the thread as a complete event and begins the new state.
+func (this *Tracer) Transition(tid int, state string) {
+	this.EndState(tid)
+
+	this.states[tid] = state
+	this.state_cycles[tid] = this.cycles
+}
+
+func (this *Tracer) EndState(tid int) {
+	state, found := this.states[tid]
+	if !found {
+		return
+	}
+
+	begin_cycles := this.state_cycles[tid]
+	this.Complete(state, "state", tid, begin_cycles, this.cycles-begin_cycles, nil)
+
+	delete(this.states, tid)
+	delete(this.state_cycles, tid)
+}
+
+func (this *Tracer) EndStates() {
+	for tid := range this.states {
+		this.EndState(tid)
+	}
+}
+
+func (this *Tracer) Complete(
+	name string,
+	category string,
+	tid int,
+	cycles int64,
+	duration int64,
+	args map[string]interface{},
+) {
+	trace_event := new(TraceEvent)
+	trace_event.Init(name, category, "X", cycles, duration, tid, 0, args)
+
+	this.events = append(this.events, trace_event)
+}
+
+// An async span follows an object, e.g., a DMA command, from BeginAsync to EndAsync; the object
+// itself is the key of the span. Ending an unknown key, such as an object that was in flight
+// when a checkpoint was restored, is ignored.
+func (this *Tracer) BeginAsync(key interface{}, name string, category string, args map[string]interface{}) {
+	if _, found := this.async_ids[key]; found {
+		err := errors.New("async span has already begun")
+		panic(err)
+	}
+
+	this.async_id++
+	this.async_ids[key] = this.async_id
+	this.async_names[key] = name
+
+	trace_event := new(TraceEvent)
+	trace_event.Init(name, category, "b", this.cycles, 0, 0, this.async_id, args)
+
+	this.events = append(this.events, trace_event)
+}
+
+func (this *Tracer) StepAsync(key interface{}, step string, category string) {
+	id, found := this.async_ids[key]
+	if !found {
+		return
+	}
+
+	trace_event := new(TraceEvent)
+	trace_event.Init(step, category, "n", this.cycles, 0, 0, id, nil)
+
+	this.events = append(this.events, trace_event)
+}
+
+func (this *Tracer) EndAsync(key interface{}, category string) {
+	id, found := this.async_ids[key]
+	if !found {
+		return
+	}
+
+	trace_event := new(TraceEvent)
+	trace_event.Init(this.async_names[key], category, "e", this.cycles, 0, 0, id, nil)
+
+	this.events = append(this.events, trace_event)
+
+	delete(this.async_ids, key)
+	delete(this.async_names, key)
+}
+
+// Drain returns the buffered events and empties the buffer.
+func (this *Tracer) Drain() []*TraceEvent {
+	events := this.events
+	this.events = make([]*TraceEvent, 0)
+	return events
+}
@@ -0,0 +1,42 @@
+package simulator
+
+// A breakpoint with a DPU index or a tasklet ID of -1 applies to all DPUs or tasklets.
+type Breakpoint struct {
+	breakpoint_id int
+	address       int64
+	dpu_index     int
+	thread_id     int
+}
+
+func (this *Breakpoint) Init(breakpoint_id int, address int64, dpu_index int, thread_id int) {
+	this.breakpoint_id = breakpoint_id
+	this.address = address
+	this.dpu_index = dpu_index
+	this.thread_id = thread_id
+}
+
+func (this *Breakpoint) BreakpointId() int {
+	return this.breakpoint_id
+}
+
+func (this *Breakpoint) Address() int64 {
+	return this.address
+}
+
+func (this *Breakpoint) DpuIndex() int {
+	return this.dpu_index
+}
+
+func (this *Breakpoint) ThreadId() int {
+	return this.thread_id
+}
+
+func (this *Breakpoint) IsHit(dpu_index int, thread_id int, pc int64) bool {
+	if this.dpu_index != -1 && this.dpu_index != dpu_index {
+		return false
+	} else if this.thread_id != -1 && this.thread_id != thread_id {
+		return false
+	}
+
+	return this.address == pc
+}
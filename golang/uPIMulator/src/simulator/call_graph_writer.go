@@ -0,0 +1,277 @@
+package simulator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"uPIMulator/src/linker/kernel"
+	"uPIMulator/src/misc"
+	"uPIMulator/src/simulator/dpu"
+)
+
+// A CallGraphWriter sums the folded stacks of all DPUs. It writes them as they are, one stack per
+// line followed by its cycles, which flame graph tools such as flamegraph.pl read, and as a
+// gprof-style report with the exclusive and inclusive counts of every function and the cycles
+// spent in its callees. A function that recurses is only counted once per stack for its
+// inclusive counts.
+type CallGraphWriter struct {
+	profilers []*misc.CallGraphProfiler
+}
+
+func (this *CallGraphWriter) Init(
+	dpus []*dpu.Dpu,
+	addresses map[string]int64,
+	source_lines map[int64]*kernel.SourceLine,
+) {
+	functions := this.Functions(addresses, source_lines)
+
+	this.profilers = make([]*misc.CallGraphProfiler, 0)
+	for _, dpu_ := range dpus {
+		profiler := new(misc.CallGraphProfiler)
+		profiler.Init(functions)
+
+		dpu_.ConnectCallGraphProfiler(profiler)
+
+		this.profilers = append(this.profilers, profiler)
+	}
+}
+
+// Functions names the PCs after the function that they belong to. A PC with labels of its own,
+// e.g., the target of a call to an untyped assembly routine, is named after its label instead,
+// skipping the hidden labels of the sections and the local labels of the assembler.
+func (this *CallGraphWriter) Functions(
+	addresses map[string]int64,
+	source_lines map[int64]*kernel.SourceLine,
+) map[int64]string {
+	functions := make(map[int64]string, 0)
+
+	for address, source_line := range source_lines {
+		if source_line.Function() != "??" {
+			functions[address] = source_line.Function()
+		}
+	}
+
+	label_names := make(map[int64]string, 0)
+	for label_name, address := range addresses {
+		if _, found := source_lines[address]; !found {
+			continue
+		} else if strings.HasPrefix(label_name, "text.") || strings.Contains(label_name, ".L") {
+			continue
+		} else if function_address, found := addresses[functions[address]]; found && function_address == address {
+			continue
+		}
+
+		if label_name_, found := label_names[address]; !found || label_name < label_name_ {
+			label_names[address] = label_name
+		}
+	}
+
+	for address, label_name := range label_names {
+		functions[address] = label_name
+	}
+
+	return functions
+}
+
+func (this *CallGraphWriter) RecordBaseline() {
+	for _, profiler := range this.profilers {
+		profiler.Reset()
+	}
+}
+
+func (this *CallGraphWriter) Write(folded_path string, report_path string) {
+	num_cycles := make(map[string]int64, 0)
+	num_instructions := make(map[string]int64, 0)
+	num_dma_bytes := make(map[string]int64, 0)
+	num_calls := make(map[string]int64, 0)
+
+	for _, profiler := range this.profilers {
+		for stack, count := range profiler.NumCycles() {
+			num_cycles[stack] += count
+		}
+		for stack, count := range profiler.NumInstructions() {
+			num_instructions[stack] += count
+		}
+		for stack, count := range profiler.NumDmaBytes() {
+			num_dma_bytes[stack] += count
+		}
+		for function, count := range profiler.NumCalls() {
+			num_calls[function] += count
+		}
+	}
+
+	this.WriteFolded(folded_path, num_cycles)
+	this.WriteReport(report_path, num_cycles, num_instructions, num_dma_bytes, num_calls)
+}
+
+func (this *CallGraphWriter) WriteFolded(path string, num_cycles map[string]int64) {
+	stacks := make([]string, 0)
+	for stack, _ := range num_cycles {
+		stacks = append(stacks, stack)
+	}
+	sort.Strings(stacks)
+
+	lines := make([]string, 0)
+	for _, stack := range stacks {
+		lines = append(lines, fmt.Sprintf("%s %d", stack, num_cycles[stack]))
+	}
+
+	file_dumper := new(misc.FileDumper)
+	file_dumper.Init(path)
+	file_dumper.WriteLines(lines)
+}
+
+func (this *CallGraphWriter) WriteReport(
+	path string,
+	num_cycles map[string]int64,
+	num_instructions map[string]int64,
+	num_dma_bytes map[string]int64,
+	num_calls map[string]int64,
+) {
+	counts := []map[string]int64{num_cycles, num_instructions, num_dma_bytes}
+
+	exclusive_counts := make(map[string][]int64, 0)
+	inclusive_counts := make(map[string][]int64, 0)
+	callee_cycles := make(map[string]map[string]int64, 0)
+	caller_cycles := make(map[string]map[string]int64, 0)
+	total_counts := make([]int64, 3)
+
+	for i, stack_counts := range counts {
+		for stack, count := range stack_counts {
+			functions := strings.Split(stack, ";")
+
+			leaf := functions[len(functions)-1]
+			if _, found := exclusive_counts[leaf]; !found {
+				exclusive_counts[leaf] = make([]int64, 3)
+			}
+			exclusive_counts[leaf][i] += count
+
+			is_counted := make(map[string]bool, 0)
+			for j, function := range functions {
+				if _, found := inclusive_counts[function]; !found {
+					inclusive_counts[function] = make([]int64, 3)
+				}
+
+				if !is_counted[function] {
+					inclusive_counts[function][i] += count
+					is_counted[function] = true
+				}
+
+				if i == 0 && j > 0 {
+					caller := functions[j-1]
+
+					if _, found := callee_cycles[caller]; !found {
+						callee_cycles[caller] = make(map[string]int64, 0)
+					}
+					if _, found := caller_cycles[function]; !found {
+						caller_cycles[function] = make(map[string]int64, 0)
+					}
+
+					callee_cycles[caller][function] += count
+					caller_cycles[function][caller] += count
+				}
+			}
+
+			total_counts[i] += count
+		}
+	}
+
+	functions := make([]string, 0)
+	for function, _ := range inclusive_counts {
+		functions = append(functions, function)
+	}
+
+	sort_fn := func(i int, j int) bool {
+		if inclusive_counts[functions[i]][0] != inclusive_counts[functions[j]][0] {
+			return inclusive_counts[functions[i]][0] > inclusive_counts[functions[j]][0]
+		}
+		return functions[i] < functions[j]
+	}
+
+	sort.Slice(functions, sort_fn)
+
+	lines := make([]string, 0)
+
+	lines = append(lines, "== flat profile ==")
+	lines = append(
+		lines,
+		fmt.Sprintf(
+			"%7s %14s %14s %14s %14s %12s %12s %10s  %s",
+			"%",
+			"self_cycles",
+			"total_cycles",
+			"self_instrs",
+			"total_instrs",
+			"self_dma",
+			"total_dma",
+			"calls",
+			"function",
+		),
+	)
+
+	for _, function := range functions {
+		if _, found := exclusive_counts[function]; !found {
+			exclusive_counts[function] = make([]int64, 3)
+		}
+	}
+
+	for _, function := range functions {
+		percentage := 0.0
+		if total_counts[0] > 0 {
+			percentage = 100 * float64(inclusive_counts[function][0]) / float64(total_counts[0])
+		}
+
+		line := fmt.Sprintf(
+			"%6.2f%% %14d %14d %14d %14d %12d %12d %10d  %s",
+			percentage,
+			exclusive_counts[function][0],
+			inclusive_counts[function][0],
+			exclusive_counts[function][1],
+			inclusive_counts[function][1],
+			exclusive_counts[function][2],
+			inclusive_counts[function][2],
+			num_calls[function],
+			function,
+		)
+		lines = append(lines, line)
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, "== call graph (total cycles) ==")
+
+	for _, function := range functions {
+		lines = append(lines, "")
+
+		for _, caller := range this.SortByCycles(caller_cycles[function]) {
+			lines = append(lines, fmt.Sprintf("%28d      %s", caller_cycles[function][caller], caller))
+		}
+
+		lines = append(lines, fmt.Sprintf("%14d %14d  %s", exclusive_counts[function][0], inclusive_counts[function][0], function))
+
+		for _, callee := range this.SortByCycles(callee_cycles[function]) {
+			lines = append(lines, fmt.Sprintf("%28d      %s", callee_cycles[function][callee], callee))
+		}
+	}
+
+	file_dumper := new(misc.FileDumper)
+	file_dumper.Init(path)
+	file_dumper.WriteLines(lines)
+}
+
+func (this *CallGraphWriter) SortByCycles(cycles map[string]int64) []string {
+	functions := make([]string, 0)
+	for function, _ := range cycles {
+		functions = append(functions, function)
+	}
+
+	sort_fn := func(i int, j int) bool {
+		if cycles[functions[i]] != cycles[functions[j]] {
+			return cycles[functions[i]] > cycles[functions[j]]
+		}
+		return functions[i] < functions[j]
+	}
+
+	sort.Slice(functions, sort_fn)
+
+	return functions
+}
@@ -21,6 +21,9 @@ type Channel struct {
 	input_q         *ChannelMessageQ
 	communication_q *ChannelMessageQ
 	ready_q         *ChannelMessageQ
+
+	cycles int64
+	tracer *misc.Tracer
 }
 
 func (this *Channel) Init(channel_id int, command_line_parser *misc.CommandLineParser) {
@@ -50,6 +53,9 @@ func (this *Channel) Init(channel_id int, command_line_parser *misc.CommandLineP
 
 	this.ready_q = new(ChannelMessageQ)
 	this.ready_q.Init(-1, 0)
+
+	this.cycles = 0
+	this.tracer = nil
 }
 
 func (this *Channel) Fini() {
@@ -62,10 +68,23 @@ func (this *Channel) Fini() {
 	this.ready_q.Fini()
 }
 
+func (this *Channel) ConnectTracer(tracer *misc.Tracer) {
+	if this.tracer != nil {
+		err := errors.New("tracer is already set")
+		panic(err)
+	}
+
+	this.tracer = tracer
+}
+
 func (this *Channel) ChannelId() int {
 	return this.channel_id
 }
 
+func (this *Channel) Cycles() int64 {
+	return this.cycles
+}
+
 func (this *Channel) NumRanks() int {
 	return len(this.ranks)
 }
@@ -84,6 +103,10 @@ func (this *Channel) Dpus() []*dpu.Dpu {
 	return dpus
 }
 
+func (this *Channel) IsEmpty() bool {
+	return this.input_q.IsEmpty() && this.communication_q.IsEmpty() && this.ready_q.IsEmpty()
+}
+
 func (this *Channel) Lock() {
 	this.mutex.Lock()
 }
@@ -152,6 +175,8 @@ func (this *Channel) Cycle() {
 	this.input_q.Cycle()
 	this.communication_q.Cycle()
 	this.ready_q.Cycle()
+
+	this.cycles++
 }
 
 func (this *Channel) ServiceInputQ() {
@@ -170,6 +195,24 @@ func (this *Channel) ServiceInputQ() {
 			panic(err)
 		}
 		this.communication_q.PushWithTimer(channel_messaage, latency)
+
+		if this.tracer != nil {
+			var name string
+			if channel_operation == READ {
+				name = "read"
+			} else {
+				name = "write"
+			}
+
+			args := map[string]interface{}{
+				"rank_id": channel_messaage.RankId(),
+				"dpu_ids": channel_messaage.DpuIds(),
+				"address": channel_messaage.Address(),
+				"size":    channel_messaage.Size(),
+			}
+			this.tracer.SetCycles(this.cycles)
+			this.tracer.Complete(name, "channel", this.channel_id, this.cycles, latency, args)
+		}
 	}
 }
 
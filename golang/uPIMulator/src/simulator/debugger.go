@@ -0,0 +1,686 @@
+package simulator
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"uPIMulator/src/abi/encoding"
+	"uPIMulator/src/abi/word"
+	"uPIMulator/src/linker/kernel/instruction"
+	"uPIMulator/src/linker/kernel/instruction/reg_descriptor"
+	"uPIMulator/src/misc"
+	"uPIMulator/src/simulator/dpu"
+	"uPIMulator/src/simulator/dpu/logic"
+)
+
+const debugger_help = `commands:
+  break <pc|label> [dpu <index>] [tasklet <id>]  set a breakpoint on an IRAM address
+  delete <breakpoint>                            delete a breakpoint
+  breakpoints                                    list the breakpoints
+  continue                                       run until a breakpoint is hit
+  cycle [n]                                      run n cycles
+  stepi [n]                                      run until the selected tasklet has executed n instructions
+  dpu <index>                                    select a DPU
+  tasklet <id>                                   select a tasklet of the selected DPU
+  threads                                        print the tasklets of the selected DPU
+  where                                          print the next instruction of the selected tasklet
+  regs                                           print the registers and flags of the selected tasklet
+  set <rN|pc|zero|carry> <value>                 modify a register or a flag of the selected tasklet
+  wram <symbol|address> [size]                   dump a WRAM range of the selected DPU
+  mram <symbol|address> [size]                   dump an MRAM range of the selected DPU
+  watch <on|off>                                 print the tasklet state transitions while running
+  quit                                           run the rest of the simulation without stopping`
+
+// A Debugger drives the simulator one system cycle at a time from a line-oriented REPL or from a
+// script file. A breakpoint is hit when a tasklet arrives at its address, i.e., when the PC of a
+// running tasklet changes to the address or a tasklet is booted at the address. The instructions
+// of a tasklet are counted in the same way, so a branch to itself is not counted by stepi.
+type Debugger struct {
+	simulator *Simulator
+	dpus      []*dpu.Dpu
+	addresses map[string]int64
+
+	script []string
+	reader *bufio.Scanner
+
+	breakpoints   []*Breakpoint
+	breakpoint_id int
+
+	dpu_index int
+	thread_id int
+	watch     bool
+
+	pcs           [][]int64
+	thread_states [][]string
+}
+
+func (this *Debugger) Init(simulator *Simulator, command_line_parser *misc.CommandLineParser) {
+	this.simulator = simulator
+	this.dpus = simulator.Host().Dpus()
+	this.addresses = simulator.Host().Addresses()
+
+	debug_script := command_line_parser.StringParameter("debug_script")
+	if debug_script != "" {
+		file_scanner := new(misc.FileScanner)
+		file_scanner.Init(debug_script)
+
+		this.script = file_scanner.ReadLines()
+		this.reader = nil
+	} else {
+		this.script = nil
+		this.reader = bufio.NewScanner(os.Stdin)
+	}
+
+	this.breakpoints = make([]*Breakpoint, 0)
+	this.breakpoint_id = 0
+
+	this.dpu_index = 0
+	this.thread_id = 0
+	this.watch = false
+
+	this.pcs = make([][]int64, 0)
+	this.thread_states = make([][]string, 0)
+	for _, dpu_ := range this.dpus {
+		this.pcs = append(this.pcs, make([]int64, len(dpu_.Threads())))
+		this.thread_states = append(this.thread_states, make([]string, len(dpu_.Threads())))
+	}
+
+	this.Observe()
+}
+
+func (this *Debugger) Run() {
+	for {
+		line, is_read := this.ReadLine()
+		if !is_read {
+			break
+		}
+
+		words := strings.Fields(line)
+		if len(words) == 0 {
+			continue
+		} else if words[0] == "quit" || words[0] == "q" {
+			break
+		}
+
+		if err := this.Execute(words); err != nil {
+			fmt.Printf("error: %s\n", err)
+		}
+	}
+
+	for !this.simulator.IsFinished() {
+		this.simulator.Cycle()
+	}
+}
+
+func (this *Debugger) ReadLine() (string, bool) {
+	if this.reader == nil {
+		if len(this.script) == 0 {
+			return "", false
+		}
+
+		line := this.script[0]
+		this.script = this.script[1:]
+
+		fmt.Printf("(debug) %s\n", line)
+		return line, true
+	}
+
+	fmt.Print("(debug) ")
+	if !this.reader.Scan() {
+		fmt.Println()
+		return "", false
+	}
+	return this.reader.Text(), true
+}
+
+func (this *Debugger) Execute(words []string) error {
+	command := words[0]
+	args := words[1:]
+
+	if command == "help" || command == "h" {
+		fmt.Println(debugger_help)
+		return nil
+	} else if command == "break" || command == "b" {
+		return this.ExecuteBreak(args)
+	} else if command == "delete" || command == "d" {
+		return this.ExecuteDelete(args)
+	} else if command == "breakpoints" || command == "info" {
+		return this.ExecuteBreakpoints(args)
+	} else if command == "continue" || command == "c" {
+		return this.ExecuteContinue(args)
+	} else if command == "cycle" {
+		return this.ExecuteCycle(args)
+	} else if command == "stepi" || command == "si" {
+		return this.ExecuteStepi(args)
+	} else if command == "dpu" {
+		return this.ExecuteDpu(args)
+	} else if command == "tasklet" {
+		return this.ExecuteTasklet(args)
+	} else if command == "threads" {
+		return this.ExecuteThreads(args)
+	} else if command == "where" {
+		return this.ExecuteWhere(args)
+	} else if command == "regs" {
+		return this.ExecuteRegs(args)
+	} else if command == "set" {
+		return this.ExecuteSet(args)
+	} else if command == "wram" {
+		return this.ExecuteWram(args)
+	} else if command == "mram" {
+		return this.ExecuteMram(args)
+	} else if command == "watch" {
+		return this.ExecuteWatch(args)
+	} else {
+		err_msg := fmt.Sprintf("command (%s) is not valid, see help", command)
+		return errors.New(err_msg)
+	}
+}
+
+func (this *Debugger) ExecuteBreak(args []string) error {
+	if len(args) != 1 && len(args) != 3 && len(args) != 5 {
+		return errors.New("usage: break <pc|label> [dpu <index>] [tasklet <id>]")
+	}
+
+	address, err := this.ParseIramAddress(args[0])
+	if err != nil {
+		return err
+	}
+
+	dpu_index := -1
+	thread_id := -1
+	for i := 1; i < len(args); i += 2 {
+		value, parse_err := strconv.Atoi(args[i+1])
+		if parse_err != nil {
+			return parse_err
+		}
+
+		if args[i] == "dpu" {
+			if value < 0 || value >= len(this.dpus) {
+				return errors.New("DPU index is out of range")
+			}
+			dpu_index = value
+		} else if args[i] == "tasklet" {
+			if value < 0 || value >= len(this.dpus[0].Threads()) {
+				return errors.New("tasklet ID is out of range")
+			}
+			thread_id = value
+		} else {
+			return errors.New("usage: break <pc|label> [dpu <index>] [tasklet <id>]")
+		}
+	}
+
+	this.breakpoint_id++
+
+	breakpoint := new(Breakpoint)
+	breakpoint.Init(this.breakpoint_id, address, dpu_index, thread_id)
+
+	this.breakpoints = append(this.breakpoints, breakpoint)
+
+	fmt.Printf("breakpoint %d at %s\n", breakpoint.BreakpointId(), this.StringifyIramAddress(address))
+	return nil
+}
+
+func (this *Debugger) ExecuteDelete(args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: delete <breakpoint>")
+	}
+
+	breakpoint_id, err := strconv.Atoi(args[0])
+	if err != nil {
+		return err
+	}
+
+	for i, breakpoint := range this.breakpoints {
+		if breakpoint.BreakpointId() == breakpoint_id {
+			this.breakpoints = append(this.breakpoints[:i], this.breakpoints[i+1:]...)
+			return nil
+		}
+	}
+
+	return errors.New("breakpoint is not found")
+}
+
+func (this *Debugger) ExecuteBreakpoints(args []string) error {
+	for _, breakpoint := range this.breakpoints {
+		dpu_index := "all"
+		if breakpoint.DpuIndex() != -1 {
+			dpu_index = strconv.Itoa(breakpoint.DpuIndex())
+		}
+
+		thread_id := "all"
+		if breakpoint.ThreadId() != -1 {
+			thread_id = strconv.Itoa(breakpoint.ThreadId())
+		}
+
+		fmt.Printf(
+			"%d: %s dpu %s tasklet %s\n",
+			breakpoint.BreakpointId(),
+			this.StringifyIramAddress(breakpoint.Address()),
+			dpu_index,
+			thread_id,
+		)
+	}
+	return nil
+}
+
+func (this *Debugger) ExecuteContinue(args []string) error {
+	for !this.Cycle() {
+	}
+	return nil
+}
+
+func (this *Debugger) ExecuteCycle(args []string) error {
+	num_cycles, err := this.ParseCount(args)
+	if err != nil {
+		return err
+	}
+
+	for i := int64(0); i < num_cycles; i++ {
+		if this.Cycle() {
+			return nil
+		}
+	}
+
+	fmt.Printf("stopped at cycle %d\n", this.simulator.Cycles())
+	return nil
+}
+
+func (this *Debugger) ExecuteStepi(args []string) error {
+	num_instructions, err := this.ParseCount(args)
+	if err != nil {
+		return err
+	}
+
+	thread := this.Thread()
+
+	for i := int64(0); i < num_instructions; {
+		pc := thread.RegFile().ReadPcReg()
+
+		if this.Cycle() {
+			return nil
+		}
+
+		if thread.RegFile().ReadPcReg() != pc {
+			i++
+		} else if thread.ThreadState() == logic.ZOMBIE {
+			fmt.Printf("tasklet %d is shut down at cycle %d\n", thread.ThreadId(), this.simulator.Cycles())
+			return nil
+		}
+	}
+
+	return this.ExecuteWhere(nil)
+}
+
+func (this *Debugger) ExecuteDpu(args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: dpu <index>")
+	}
+
+	dpu_index, err := strconv.Atoi(args[0])
+	if err != nil {
+		return err
+	} else if dpu_index < 0 || dpu_index >= len(this.dpus) {
+		return errors.New("DPU index is out of range")
+	}
+
+	this.dpu_index = dpu_index
+
+	dpu_ := this.Dpu()
+	fmt.Printf("DPU %d (%d-%d-%d)\n", dpu_index, dpu_.ChannelId(), dpu_.RankId(), dpu_.DpuId())
+	return nil
+}
+
+func (this *Debugger) ExecuteTasklet(args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: tasklet <id>")
+	}
+
+	thread_id, err := strconv.Atoi(args[0])
+	if err != nil {
+		return err
+	} else if thread_id < 0 || thread_id >= len(this.Dpu().Threads()) {
+		return errors.New("tasklet ID is out of range")
+	}
+
+	this.thread_id = thread_id
+
+	return this.ExecuteWhere(nil)
+}
+
+func (this *Debugger) ExecuteThreads(args []string) error {
+	for _, thread := range this.Dpu().Threads() {
+		fmt.Printf(
+			"tasklet %d: %s at %s\n",
+			thread.ThreadId(),
+			thread.StringifyThreadState(),
+			this.StringifyIramAddress(thread.RegFile().ReadPcReg()),
+		)
+	}
+	return nil
+}
+
+func (this *Debugger) ExecuteWhere(args []string) error {
+	thread := this.Thread()
+	pc := thread.RegFile().ReadPcReg()
+
+	line := fmt.Sprintf(
+		"DPU %d tasklet %d (%s) at %s",
+		this.dpu_index,
+		thread.ThreadId(),
+		thread.StringifyThreadState(),
+		this.StringifyIramAddress(pc),
+	)
+
+	iram := this.Dpu().Iram()
+	if iram.Address() <= pc && pc < iram.Address()+iram.Size() {
+		line += ": " + iram.Read(pc).Stringify()
+	}
+
+	fmt.Println(line)
+	return nil
+}
+
+func (this *Debugger) ExecuteRegs(args []string) error {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	reg_file := this.Thread().RegFile()
+
+	for i := 0; i < config_loader.NumGpRegisters(); i++ {
+		gp_reg_descriptor := new(reg_descriptor.GpRegDescriptor)
+		gp_reg_descriptor.Init(i)
+
+		value := reg_file.ReadGpReg(gp_reg_descriptor, word.SIGNED)
+		fmt.Printf("r%d: %d (0x%x)\n", i, value, reg_file.ReadGpReg(gp_reg_descriptor, word.UNSIGNED))
+	}
+
+	fmt.Printf("pc: %s\n", this.StringifyIramAddress(reg_file.ReadPcReg()))
+	fmt.Printf("zero: %t\n", reg_file.ReadFlagReg(instruction.ZERO))
+	fmt.Printf("carry: %t\n", reg_file.ReadFlagReg(instruction.CARRY))
+	return nil
+}
+
+func (this *Debugger) ExecuteSet(args []string) error {
+	if len(args) != 2 {
+		return errors.New("usage: set <rN|pc|zero|carry> <value>")
+	}
+
+	reg_file := this.Thread().RegFile()
+
+	if args[0] == "pc" {
+		address, err := this.ParseIramAddress(args[1])
+		if err != nil {
+			return err
+		}
+
+		reg_file.WritePcReg(address)
+		return nil
+	}
+
+	value, err := strconv.ParseInt(args[1], 0, 64)
+	if err != nil {
+		return err
+	}
+
+	if args[0] == "zero" || args[0] == "carry" {
+		flag := instruction.ZERO
+		if args[0] == "carry" {
+			flag = instruction.CARRY
+		}
+
+		if value != 0 {
+			reg_file.SetFlag(flag)
+		} else {
+			reg_file.ClearFlag(flag)
+		}
+		return nil
+	}
+
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	index, parse_err := strconv.Atoi(strings.TrimPrefix(args[0], "r"))
+	if !strings.HasPrefix(args[0], "r") || parse_err != nil || index < 0 ||
+		index >= config_loader.NumGpRegisters() {
+		err_msg := fmt.Sprintf("register (%s) is not valid", args[0])
+		return errors.New(err_msg)
+	}
+
+	gp_reg_descriptor := new(reg_descriptor.GpRegDescriptor)
+	gp_reg_descriptor.Init(index)
+
+	reg_file.WriteGpReg(gp_reg_descriptor, value)
+	return nil
+}
+
+func (this *Debugger) ExecuteWram(args []string) error {
+	wram := this.Dpu().Wram()
+
+	address, size, err := this.ParseRange(args, "wram")
+	if err != nil {
+		return err
+	} else if address < wram.Address() || address+size > wram.Address()+wram.Size() {
+		return errors.New("range is out of WRAM")
+	}
+
+	this.PrintByteStream(address, wram.Read(address, size))
+	return nil
+}
+
+func (this *Debugger) ExecuteMram(args []string) error {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	address, size, err := this.ParseRange(args, "mram")
+	if err != nil {
+		return err
+	} else if address < config_loader.MramOffset() ||
+		address+size > config_loader.MramOffset()+config_loader.MramSize() {
+		return errors.New("range is out of MRAM")
+	}
+
+	byte_stream := this.Dpu().MemoryController().Peek(address, size)
+
+	this.PrintByteStream(address, byte_stream)
+	return nil
+}
+
+func (this *Debugger) ExecuteWatch(args []string) error {
+	if len(args) != 1 || (args[0] != "on" && args[0] != "off") {
+		return errors.New("usage: watch <on|off>")
+	}
+
+	this.watch = args[0] == "on"
+	return nil
+}
+
+// Cycle advances the simulator by a system cycle and returns whether the debugger has to stop.
+func (this *Debugger) Cycle() bool {
+	if this.simulator.IsFinished() {
+		fmt.Printf("simulation is finished at cycle %d\n", this.simulator.Cycles())
+		return true
+	}
+
+	this.simulator.Cycle()
+
+	is_stopped := false
+	for dpu_index, dpu_ := range this.dpus {
+		for _, thread := range dpu_.Threads() {
+			thread_id := thread.ThreadId()
+			pc := thread.RegFile().ReadPcReg()
+			thread_state := thread.StringifyThreadState()
+
+			prev_pc := this.pcs[dpu_index][thread_id]
+			prev_thread_state := this.thread_states[dpu_index][thread_id]
+
+			if this.watch && thread_state != prev_thread_state {
+				fmt.Printf(
+					"[%d] DPU %d tasklet %d: %s -> %s\n",
+					this.simulator.Cycles(),
+					dpu_index,
+					thread_id,
+					prev_thread_state,
+					thread_state,
+				)
+			}
+
+			is_running := thread_state != "EMBRYO" && thread_state != "ZOMBIE"
+			is_arrived := pc != prev_pc || prev_thread_state == "EMBRYO" || prev_thread_state == "ZOMBIE"
+
+			if !is_stopped && is_running && is_arrived {
+				for _, breakpoint := range this.breakpoints {
+					if breakpoint.IsHit(dpu_index, thread_id, pc) {
+						fmt.Printf(
+							"breakpoint %d hit at cycle %d\n",
+							breakpoint.BreakpointId(),
+							this.simulator.Cycles(),
+						)
+
+						this.dpu_index = dpu_index
+						this.thread_id = thread_id
+						this.ExecuteWhere(nil)
+
+						is_stopped = true
+						break
+					}
+				}
+			}
+		}
+	}
+
+	this.Observe()
+
+	if !is_stopped && this.simulator.IsFinished() {
+		fmt.Printf("simulation is finished at cycle %d\n", this.simulator.Cycles())
+		return true
+	}
+
+	return is_stopped
+}
+
+func (this *Debugger) Observe() {
+	for dpu_index, dpu_ := range this.dpus {
+		for _, thread := range dpu_.Threads() {
+			this.pcs[dpu_index][thread.ThreadId()] = thread.RegFile().ReadPcReg()
+			this.thread_states[dpu_index][thread.ThreadId()] = thread.StringifyThreadState()
+		}
+	}
+}
+
+func (this *Debugger) Dpu() *dpu.Dpu {
+	return this.dpus[this.dpu_index]
+}
+
+func (this *Debugger) Thread() *logic.Thread {
+	return this.Dpu().Threads()[this.thread_id]
+}
+
+func (this *Debugger) ParseCount(args []string) (int64, error) {
+	if len(args) == 0 {
+		return 1, nil
+	} else if len(args) > 1 {
+		return 0, errors.New("too many arguments")
+	}
+
+	count, err := strconv.ParseInt(args[0], 0, 64)
+	if err != nil {
+		return 0, err
+	} else if count <= 0 {
+		return 0, errors.New("count <= 0")
+	}
+
+	return count, nil
+}
+
+// ParseAddress parses a decimal or hexadecimal address or the name of a linker symbol.
+func (this *Debugger) ParseAddress(arg string) (int64, error) {
+	if address, found := this.addresses[arg]; found {
+		return address, nil
+	}
+
+	address, err := strconv.ParseInt(arg, 0, 64)
+	if err != nil {
+		err_msg := fmt.Sprintf("symbol (%s) is not found", arg)
+		return 0, errors.New(err_msg)
+	}
+
+	return address, nil
+}
+
+func (this *Debugger) ParseIramAddress(arg string) (int64, error) {
+	address, err := this.ParseAddress(arg)
+	if err != nil {
+		return 0, err
+	}
+
+	iram := this.Dpu().Iram()
+	if address < iram.Address() || address >= iram.Address()+iram.Size() {
+		return 0, errors.New("address is out of IRAM")
+	}
+
+	return address, nil
+}
+
+func (this *Debugger) ParseRange(args []string, command string) (int64, int64, error) {
+	if len(args) != 1 && len(args) != 2 {
+		err_msg := fmt.Sprintf("usage: %s <symbol|address> [size]", command)
+		return 0, 0, errors.New(err_msg)
+	}
+
+	address, err := this.ParseAddress(args[0])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	size := int64(16)
+	if len(args) == 2 {
+		size, err = strconv.ParseInt(args[1], 0, 64)
+		if err != nil {
+			return 0, 0, err
+		} else if size <= 0 {
+			return 0, 0, errors.New("size <= 0")
+		}
+	}
+
+	return address, size, nil
+}
+
+func (this *Debugger) PrintByteStream(address int64, byte_stream *encoding.ByteStream) {
+	for i := int64(0); i < byte_stream.Size(); i += 16 {
+		bytes := make([]string, 0)
+		for j := i; j < i+16 && j < byte_stream.Size(); j++ {
+			bytes = append(bytes, fmt.Sprintf("%02x", byte_stream.Get(int(j))))
+		}
+
+		fmt.Printf("0x%08x: %s\n", address+i, strings.Join(bytes, " "))
+	}
+}
+
+// StringifyIramAddress prints an IRAM address with the nearest preceding linker label.
+func (this *Debugger) StringifyIramAddress(address int64) string {
+	iram := this.Dpu().Iram()
+
+	label := ""
+	label_address := int64(-1)
+	for name, value := range this.addresses {
+		if value < iram.Address() || value >= iram.Address()+iram.Size() || value > address {
+			continue
+		}
+
+		if value > label_address ||
+			(value == label_address && (len(name) < len(label) || (len(name) == len(label) && name < label))) {
+			label = name
+			label_address = value
+		}
+	}
+
+	if label_address == -1 {
+		return fmt.Sprintf("0x%x", address)
+	} else if label_address == address {
+		return fmt.Sprintf("0x%x <%s>", address, label)
+	}
+	return fmt.Sprintf("0x%x <%s+%d>", address, label, address-label_address)
+}
@@ -31,6 +31,9 @@ type Dpu struct {
 	logic             *logic.Logic
 
 	stat_factory *misc.StatFactory
+
+	tracer        *misc.Tracer
+	race_detector *misc.RaceDetector
 }
 
 func (this *Dpu) Init(
@@ -108,6 +111,9 @@ func (this *Dpu) Init(
 	name := fmt.Sprintf("DPU%d-%d-%d", channel_id, rank_id, dpu_id)
 	this.stat_factory = new(misc.StatFactory)
 	this.stat_factory.Init(name)
+
+	this.tracer = nil
+	this.race_detector = nil
 }
 
 func (this *Dpu) Fini() {
@@ -127,6 +133,47 @@ func (this *Dpu) Fini() {
 	this.dma.Fini()
 }
 
+func (this *Dpu) ConnectTracer(tracer *misc.Tracer) {
+	if this.tracer != nil {
+		err := errors.New("tracer is already set")
+		panic(err)
+	}
+
+	this.tracer = tracer
+	this.SyncTracer()
+
+	this.thread_scheduler.ConnectTracer(tracer)
+	this.dma.ConnectTracer(tracer)
+}
+
+func (this *Dpu) ConnectProfiler(profiler *misc.Profiler) {
+	this.logic.ConnectProfiler(profiler)
+}
+
+func (this *Dpu) ConnectCallGraphProfiler(call_graph_profiler *misc.CallGraphProfiler) {
+	this.logic.ConnectCallGraphProfiler(call_graph_profiler)
+}
+
+func (this *Dpu) ConnectRaceDetector(race_detector *misc.RaceDetector) {
+	if this.race_detector != nil {
+		err := errors.New("race detector is already set")
+		panic(err)
+	}
+
+	this.race_detector = race_detector
+
+	this.logic.ConnectRaceDetector(race_detector)
+}
+
+func (this *Dpu) ConnectSanitizer(sanitizer *misc.Sanitizer) {
+	this.logic.ConnectSanitizer(sanitizer)
+	this.dma.ConnectSanitizer(sanitizer)
+}
+
+func (this *Dpu) ConnectProgressTracker(progress_tracker *misc.ProgressTracker) {
+	this.logic.ConnectProgressTracker(progress_tracker)
+}
+
 func (this *Dpu) ChannelId() int {
 	return this.channel_id
 }
@@ -143,6 +190,18 @@ func (this *Dpu) ThreadScheduler() *logic.ThreadScheduler {
 	return this.thread_scheduler
 }
 
+func (this *Dpu) Atomic() *sram.Atomic {
+	return this.atomic
+}
+
+func (this *Dpu) Iram() *sram.Iram {
+	return this.iram
+}
+
+func (this *Dpu) Wram() *sram.Wram {
+	return this.wram
+}
+
 func (this *Dpu) Logic() *logic.Logic {
 	return this.logic
 }
@@ -159,11 +218,57 @@ func (this *Dpu) Threads() []*logic.Thread {
 	return this.threads
 }
 
+func (this *Dpu) Cycles() int64 {
+	return this.cycles
+}
+
 func (this *Dpu) StatFactory() *misc.StatFactory {
 	return this.stat_factory
 }
 
+func (this *Dpu) StatComponents() map[string]*misc.StatFactory {
+	return map[string]*misc.StatFactory{
+		"Dpu":              this.stat_factory,
+		"ThreadScheduler":  this.thread_scheduler.StatFactory(),
+		"Logic":            this.logic.StatFactory(),
+		"CycleRule":        this.logic.CycleRule().StatFactory(),
+		"MemoryController": this.memory_controller.StatFactory(),
+		"MemoryScheduler":  this.memory_controller.MemoryScheduler().StatFactory(),
+		"RowBuffer":        this.memory_controller.RowBuffer().StatFactory(),
+	}
+}
+
+func (this *Dpu) ComponentStats() map[string]map[string]int64 {
+	component_stats := make(map[string]map[string]int64, 0)
+
+	for component, stat_factory := range this.StatComponents() {
+		component_stats[component] = make(map[string]int64, 0)
+
+		for _, stat := range stat_factory.Stats() {
+			component_stats[component][stat] = stat_factory.Value(stat)
+		}
+	}
+
+	return component_stats
+}
+
+func (this *Dpu) StatFactories() []*misc.StatFactory {
+	return []*misc.StatFactory{
+		this.stat_factory,
+		this.thread_scheduler.StatFactory(),
+		this.logic.StatFactory(),
+		this.logic.CycleRule().StatFactory(),
+		this.memory_controller.StatFactory(),
+		this.memory_controller.MemoryScheduler().StatFactory(),
+		this.memory_controller.RowBuffer().StatFactory(),
+	}
+}
+
 func (this *Dpu) Boot() {
+	if this.race_detector != nil {
+		this.race_detector.Launch()
+	}
+
 	this.thread_scheduler.Boot(0)
 }
 
@@ -176,7 +281,19 @@ func (this *Dpu) IsZombie() bool {
 	return this.logic.IsEmpty() && this.memory_controller.IsEmpty()
 }
 
+// FaultedThread is the thread whose fault has stopped the DPU, if any.
+func (this *Dpu) FaultedThread() *logic.Thread {
+	for _, thread := range this.threads {
+		if thread.RegFile().HasException() {
+			return thread
+		}
+	}
+	return nil
+}
+
 func (this *Dpu) Cycle() {
+	defer this.Recover()
+
 	for _, thread := range this.threads {
 		thread.IncrementIssueCycle()
 	}
@@ -185,10 +302,184 @@ func (this *Dpu) Cycle() {
 	this.logic.Cycle()
 	this.dma.Cycle()
 
-	num_memory_cycles := int(this.frequency_ratio*float64(this.cycles) - this.frequency_ratio*float64(this.cycles-1))
-	for i := 0; i < num_memory_cycles; i++ {
+	num_memory_cycles := this.NumMemoryCycles(this.cycles)
+	for i := int64(0); i < num_memory_cycles; i++ {
+		this.memory_controller.Cycle()
+	}
+
+	this.cycles++
+
+	this.SyncTracer()
+}
+
+func (this *Dpu) Drain() {
+	defer this.Recover()
+
+	for _, thread := range this.threads {
+		thread.IncrementIssueCycle()
+	}
+
+	this.logic.Drain()
+	this.dma.Cycle()
+
+	num_memory_cycles := this.NumMemoryCycles(this.cycles)
+	for i := int64(0); i < num_memory_cycles; i++ {
 		this.memory_controller.Cycle()
 	}
 
 	this.cycles++
+
+	this.SyncTracer()
+}
+
+func (this *Dpu) IsDrained() bool {
+	return this.logic.IsEmpty() && this.dma.IsEmpty() && this.memory_controller.IsEmpty()
+}
+
+func (this *Dpu) Step() {
+	defer this.Recover()
+
+	this.logic.Step()
+
+	this.cycles++
+
+	this.SyncTracer()
+}
+
+func (this *Dpu) IsStalled() bool {
+	return this.logic.IsStalled() && !this.memory_controller.CanPop()
+}
+
+func (this *Dpu) IsIdle() bool {
+	return this.logic.IsStalled() && this.memory_controller.IsEmpty()
+}
+
+func (this *Dpu) FastForward(max_num_cycles int64) {
+	defer this.Recover()
+
+	if !this.IsStalled() || this.memory_controller.IsEmpty() {
+		this.Cycle()
+		return
+	}
+
+	num_cycles := int64(0)
+	for num_cycles < max_num_cycles && !this.memory_controller.CanPop() &&
+		!this.memory_controller.IsEmpty() {
+		this.memory_controller.FastForward(this.NumMemoryCycles(this.cycles + num_cycles))
+		num_cycles++
+	}
+
+	for _, thread := range this.threads {
+		thread.SkipIssueCycle(num_cycles)
+	}
+
+	this.logic.Skip(num_cycles)
+
+	this.cycles += num_cycles
+
+	this.SyncTracer()
+}
+
+func (this *Dpu) Skip(num_cycles int64) {
+	defer this.Recover()
+
+	for _, thread := range this.threads {
+		thread.SkipIssueCycle(num_cycles)
+	}
+
+	this.logic.Skip(num_cycles)
+
+	num_memory_cycles := int64(0)
+	for i := int64(0); i < num_cycles; i++ {
+		num_memory_cycles += this.NumMemoryCycles(this.cycles + i)
+	}
+	this.memory_controller.Skip(num_memory_cycles)
+
+	this.cycles += num_cycles
+
+	this.SyncTracer()
+}
+
+// Recover attaches the DPU to what the DPU raises while it runs.
+func (this *Dpu) Recover() {
+	if recovered := recover(); recovered != nil {
+		simulation_error := new(misc.SimulationError)
+		simulation_error.InitRecovered(recovered, misc.INTERNAL_ERROR)
+		simulation_error.SetComponent("Dpu")
+		simulation_error.SetDpu(this.channel_id, this.rank_id, this.dpu_id)
+		panic(simulation_error)
+	}
+}
+
+// The tracer time-stamps the events with the cycle the DPU is about to run, so that the events
+// raised by the host between two cycles are placed at the cycle that follows them.
+func (this *Dpu) SyncTracer() {
+	if this.tracer != nil {
+		this.tracer.SetCycles(this.cycles)
+	}
+}
+
+func (this *Dpu) NumMemoryCycles(cycle int64) int64 {
+	return int64(this.frequency_ratio*float64(cycle) - this.frequency_ratio*float64(cycle-1))
+}
+
+func (this *Dpu) Checkpoint(checkpoint_writer *misc.CheckpointWriter) {
+	checkpoint_writer.WriteInt(int64(this.channel_id))
+	checkpoint_writer.WriteInt(int64(this.rank_id))
+	checkpoint_writer.WriteInt(int64(this.dpu_id))
+
+	checkpoint_writer.WriteInt(this.cycles)
+
+	for _, thread := range this.threads {
+		thread.Checkpoint(checkpoint_writer)
+	}
+
+	this.thread_scheduler.Checkpoint(checkpoint_writer)
+	this.atomic.Checkpoint(checkpoint_writer)
+	this.iram.Checkpoint(checkpoint_writer)
+	this.wram.Checkpoint(checkpoint_writer)
+	this.mram.Checkpoint(checkpoint_writer)
+	this.logic.Checkpoint(checkpoint_writer)
+	this.dma.Checkpoint(checkpoint_writer)
+	this.memory_controller.Checkpoint(checkpoint_writer)
+
+	this.stat_factory.Checkpoint(checkpoint_writer)
+}
+
+func (this *Dpu) Restore(checkpoint_reader *misc.CheckpointReader) {
+	channel_id := int(checkpoint_reader.ReadInt())
+	rank_id := int(checkpoint_reader.ReadInt())
+	dpu_id := int(checkpoint_reader.ReadInt())
+
+	if channel_id != this.channel_id || rank_id != this.rank_id || dpu_id != this.dpu_id {
+		err_msg := fmt.Sprintf(
+			"checkpoint of DPU (%d, %d, %d) cannot be restored to DPU (%d, %d, %d)",
+			channel_id,
+			rank_id,
+			dpu_id,
+			this.channel_id,
+			this.rank_id,
+			this.dpu_id,
+		)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	this.cycles = checkpoint_reader.ReadInt()
+	this.SyncTracer()
+
+	for _, thread := range this.threads {
+		thread.Restore(checkpoint_reader)
+	}
+
+	this.thread_scheduler.Restore(checkpoint_reader)
+	this.atomic.Restore(checkpoint_reader)
+	this.iram.Restore(checkpoint_reader)
+	this.wram.Restore(checkpoint_reader)
+	this.mram.Restore(checkpoint_reader)
+	this.logic.Restore(checkpoint_reader, this.threads)
+	this.dma.Restore(checkpoint_reader)
+	this.memory_controller.Restore(checkpoint_reader)
+
+	this.stat_factory.Restore(checkpoint_reader)
 }
@@ -4,11 +4,13 @@ import (
 	"errors"
 	"uPIMulator/src/abi/encoding"
 	"uPIMulator/src/linker/kernel/instruction"
+	"uPIMulator/src/misc"
 )
 
 type DmaCommand struct {
 	memory_operation MemoryOperation
 	wram_address     *int64
+	iram_address     *int64
 	mram_address     *int64
 	size             int64
 
@@ -21,6 +23,7 @@ type DmaCommand struct {
 func (this *DmaCommand) InitReadFromMram(mram_address int64, size int64) {
 	this.memory_operation = READ
 	this.wram_address = nil
+	this.iram_address = nil
 
 	this.mram_address = new(int64)
 	*this.mram_address = mram_address
@@ -48,6 +51,7 @@ func (this *DmaCommand) InitWriteToMram(
 ) {
 	this.memory_operation = WRITE
 	this.wram_address = nil
+	this.iram_address = nil
 
 	this.mram_address = new(int64)
 	*this.mram_address = mram_address
@@ -79,6 +83,46 @@ func (this *DmaCommand) InitReadFromMramToWram(
 	this.wram_address = new(int64)
 	*this.wram_address = wram_address
 
+	this.iram_address = nil
+
+	this.mram_address = new(int64)
+	*this.mram_address = mram_address
+
+	this.size = size
+
+	this.byte_stream = new(encoding.ByteStream)
+	this.byte_stream.Init()
+	for i := int64(0); i < size; i++ {
+		this.byte_stream.Append(0)
+	}
+
+	this.acks = make([]bool, 0)
+	for i := int64(0); i < size; i++ {
+		this.acks = append(this.acks, false)
+	}
+
+	this.instruction = instruction_
+}
+
+// InitReadFromMramToIram initializes the command of an ldmai, which loads the instructions of an
+// overlay from the MRAM into the IRAM.
+func (this *DmaCommand) InitReadFromMramToIram(
+	iram_address int64,
+	mram_address int64,
+	size int64,
+	instruction_ *instruction.Instruction,
+) {
+	if instruction_.OpCode() != instruction.LDMAI {
+		err := errors.New("instruction's op code != LDMAI")
+		panic(err)
+	}
+
+	this.memory_operation = READ
+	this.wram_address = nil
+
+	this.iram_address = new(int64)
+	*this.iram_address = iram_address
+
 	this.mram_address = new(int64)
 	*this.mram_address = mram_address
 
@@ -115,6 +159,8 @@ func (this *DmaCommand) InitWriteToMramFromWram(
 	this.wram_address = new(int64)
 	*this.wram_address = wram_address
 
+	this.iram_address = nil
+
 	this.mram_address = new(int64)
 	*this.mram_address = mram_address
 
@@ -149,6 +195,19 @@ func (this *DmaCommand) WramAddress() int64 {
 	return *this.wram_address
 }
 
+func (this *DmaCommand) HasIramAddress() bool {
+	return this.iram_address != nil
+}
+
+func (this *DmaCommand) IramAddress() int64 {
+	if this.iram_address == nil {
+		err := errors.New("DMA command does not have an IRAM address")
+		panic(err)
+	}
+
+	return *this.iram_address
+}
+
 func (this *DmaCommand) MramAddress() int64 {
 	if this.mram_address == nil {
 		err := errors.New("DMA command does not have an MRAM address")
@@ -233,3 +292,65 @@ func (this *DmaCommand) Index(mram_address int64) int {
 
 	return int(mram_address - this.MramAddress())
 }
+
+func (this *DmaCommand) Checkpoint(checkpoint_writer *misc.CheckpointWriter) {
+	checkpoint_writer.WriteInt(int64(this.memory_operation))
+
+	checkpoint_writer.WriteBool(this.wram_address != nil)
+	if this.wram_address != nil {
+		checkpoint_writer.WriteInt(*this.wram_address)
+	}
+
+	checkpoint_writer.WriteBool(this.iram_address != nil)
+	if this.iram_address != nil {
+		checkpoint_writer.WriteInt(*this.iram_address)
+	}
+
+	checkpoint_writer.WriteBool(this.mram_address != nil)
+	if this.mram_address != nil {
+		checkpoint_writer.WriteInt(*this.mram_address)
+	}
+
+	checkpoint_writer.WriteInt(this.size)
+	checkpoint_writer.WriteByteStream(this.byte_stream)
+
+	checkpoint_writer.WriteInt(int64(len(this.acks)))
+	for _, ack := range this.acks {
+		checkpoint_writer.WriteBool(ack)
+	}
+
+	checkpoint_writer.WriteObject(this.instruction)
+}
+
+func (this *DmaCommand) Restore(checkpoint_reader *misc.CheckpointReader) {
+	this.memory_operation = MemoryOperation(checkpoint_reader.ReadInt())
+
+	this.wram_address = nil
+	if checkpoint_reader.ReadBool() {
+		this.wram_address = new(int64)
+		*this.wram_address = checkpoint_reader.ReadInt()
+	}
+
+	this.iram_address = nil
+	if checkpoint_reader.ReadBool() {
+		this.iram_address = new(int64)
+		*this.iram_address = checkpoint_reader.ReadInt()
+	}
+
+	this.mram_address = nil
+	if checkpoint_reader.ReadBool() {
+		this.mram_address = new(int64)
+		*this.mram_address = checkpoint_reader.ReadInt()
+	}
+
+	this.size = checkpoint_reader.ReadInt()
+	this.byte_stream = checkpoint_reader.ReadByteStream()
+
+	this.acks = make([]bool, 0)
+	num_acks := int(checkpoint_reader.ReadInt())
+	for i := 0; i < num_acks; i++ {
+		this.acks = append(this.acks, checkpoint_reader.ReadBool())
+	}
+
+	this.instruction, _ = checkpoint_reader.ReadObject(new(instruction.Instruction)).(*instruction.Instruction)
+}
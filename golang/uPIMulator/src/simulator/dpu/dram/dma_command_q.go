@@ -2,6 +2,7 @@ package dram
 
 import (
 	"errors"
+	"uPIMulator/src/misc"
 )
 
 type DmaCommandQ struct {
@@ -123,3 +124,30 @@ func (this *DmaCommandQ) Cycle() {
 		this.cycles[0] -= 1
 	}
 }
+
+func (this *DmaCommandQ) Skip(num_cycles int64) {
+	if !this.IsEmpty() {
+		this.cycles[0] -= num_cycles
+	}
+}
+
+func (this *DmaCommandQ) Checkpoint(checkpoint_writer *misc.CheckpointWriter) {
+	checkpoint_writer.WriteInt(int64(len(this.dma_commands)))
+	for i, dma_command := range this.dma_commands {
+		checkpoint_writer.WriteObject(dma_command)
+		checkpoint_writer.WriteInt(this.cycles[i])
+	}
+}
+
+func (this *DmaCommandQ) Restore(checkpoint_reader *misc.CheckpointReader) {
+	this.dma_commands = make([]*DmaCommand, 0)
+	this.cycles = make([]int64, 0)
+
+	num_dma_commands := int(checkpoint_reader.ReadInt())
+	for i := 0; i < num_dma_commands; i++ {
+		dma_command, _ := checkpoint_reader.ReadObject(new(DmaCommand)).(*DmaCommand)
+
+		this.dma_commands = append(this.dma_commands, dma_command)
+		this.cycles = append(this.cycles, checkpoint_reader.ReadInt())
+	}
+}
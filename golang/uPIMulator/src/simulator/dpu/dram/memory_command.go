@@ -3,6 +3,7 @@ package dram
 import (
 	"errors"
 	"uPIMulator/src/abi/encoding"
+	"uPIMulator/src/misc"
 )
 
 type MemoryOperation int
@@ -117,3 +118,29 @@ func (this *MemoryCommand) DmaCommand() *DmaCommand {
 
 	return this.dma_command
 }
+
+func (this *MemoryCommand) Checkpoint(checkpoint_writer *misc.CheckpointWriter) {
+	checkpoint_writer.WriteInt(int64(this.memory_operation))
+	checkpoint_writer.WriteInt(this.address)
+	checkpoint_writer.WriteInt(this.size)
+
+	checkpoint_writer.WriteBool(this.byte_stream != nil)
+	if this.byte_stream != nil {
+		checkpoint_writer.WriteByteStream(this.byte_stream)
+	}
+
+	checkpoint_writer.WriteObject(this.dma_command)
+}
+
+func (this *MemoryCommand) Restore(checkpoint_reader *misc.CheckpointReader) {
+	this.memory_operation = MemoryOperation(checkpoint_reader.ReadInt())
+	this.address = checkpoint_reader.ReadInt()
+	this.size = checkpoint_reader.ReadInt()
+
+	this.byte_stream = nil
+	if checkpoint_reader.ReadBool() {
+		this.byte_stream = checkpoint_reader.ReadByteStream()
+	}
+
+	this.dma_command, _ = checkpoint_reader.ReadObject(new(DmaCommand)).(*DmaCommand)
+}
@@ -2,6 +2,7 @@ package dram
 
 import (
 	"errors"
+	"uPIMulator/src/misc"
 )
 
 type MemoryCommandQ struct {
@@ -123,3 +124,30 @@ func (this *MemoryCommandQ) Cycle() {
 		this.cycles[0] -= 1
 	}
 }
+
+func (this *MemoryCommandQ) Skip(num_cycles int64) {
+	if !this.IsEmpty() {
+		this.cycles[0] -= num_cycles
+	}
+}
+
+func (this *MemoryCommandQ) Checkpoint(checkpoint_writer *misc.CheckpointWriter) {
+	checkpoint_writer.WriteInt(int64(len(this.memory_commands)))
+	for i, memory_command := range this.memory_commands {
+		checkpoint_writer.WriteObject(memory_command)
+		checkpoint_writer.WriteInt(this.cycles[i])
+	}
+}
+
+func (this *MemoryCommandQ) Restore(checkpoint_reader *misc.CheckpointReader) {
+	this.memory_commands = make([]*MemoryCommand, 0)
+	this.cycles = make([]int64, 0)
+
+	num_memory_commands := int(checkpoint_reader.ReadInt())
+	for i := 0; i < num_memory_commands; i++ {
+		memory_command, _ := checkpoint_reader.ReadObject(new(MemoryCommand)).(*MemoryCommand)
+
+		this.memory_commands = append(this.memory_commands, memory_command)
+		this.cycles = append(this.cycles, checkpoint_reader.ReadInt())
+	}
+}
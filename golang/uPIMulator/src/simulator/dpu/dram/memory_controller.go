@@ -197,6 +197,61 @@ func (this *MemoryController) Write(address int64, size int64, byte_stream *enco
 	}
 }
 
+// Peek reads the MRAM as the DPU sees it, i.e., with the open row of the row buffer in place of
+// its stale wordline, without flushing the row buffer.
+func (this *MemoryController) Peek(address int64, size int64) *encoding.ByteStream {
+	byte_stream := this.Read(address, size)
+
+	row_address := this.row_buffer.RowAddress()
+	if row_address == nil {
+		return byte_stream
+	}
+
+	for i := int64(0); i < size; i++ {
+		if *row_address <= address+i && address+i < *row_address+this.wordline_size {
+			row_byte_stream := this.row_buffer.ReadFromRowBuffer(address+i, 1)
+			byte_stream.Set(int(i), row_byte_stream.Get(0))
+		}
+	}
+
+	return byte_stream
+}
+
+// Poke writes the MRAM and the open row of the row buffer alike, so that neither of them holds a
+// stale copy of the written bytes.
+func (this *MemoryController) Poke(address int64, byte_stream *encoding.ByteStream) {
+	this.Write(address, byte_stream.Size(), byte_stream)
+
+	row_address := this.row_buffer.RowAddress()
+	if row_address == nil {
+		return
+	}
+
+	for i := int64(0); i < byte_stream.Size(); i++ {
+		if *row_address <= address+i && address+i < *row_address+this.wordline_size {
+			row_byte_stream := new(encoding.ByteStream)
+			row_byte_stream.Init()
+			row_byte_stream.Append(byte_stream.Get(int(i)))
+
+			this.row_buffer.WriteToRowBuffer(address+i, 1, row_byte_stream)
+		}
+	}
+}
+
+// Account counts the reads and the writes of a DMA command that the DMA transfers functionally, i.e.,
+// without the memory controller, as the row buffer counts them when it serves the DMA command, so
+// that they stay exact while the DPU runs functionally.
+func (this *MemoryController) Account(dma_command *DmaCommand) {
+	end_address := dma_command.MramAddress() + dma_command.Size()
+
+	for address := dma_command.MramAddress(); address < end_address; {
+		size := this.memory_scheduler.MemoryCommandSize(address, end_address)
+		this.row_buffer.Account(dma_command.MemoryOperation(), size)
+
+		address += size
+	}
+}
+
 func (this *MemoryController) Flush() {
 	this.memory_scheduler.Flush()
 	this.row_buffer.Flush()
@@ -220,6 +275,54 @@ func (this *MemoryController) Cycle() {
 	this.stat_factory.Increment("memory_cycle", 1)
 }
 
+func (this *MemoryController) NumSkippableCycles() int64 {
+	if !this.input_q.IsEmpty() || !this.ready_q.IsEmpty() {
+		return 0
+	} else if !this.memory_command_q.IsEmpty() && this.row_buffer.CanPush() {
+		return 0
+	}
+
+	for i := 0; this.wait_q.CanPop(i + 1); i++ {
+		dma_command, _ := this.wait_q.Front(i)
+
+		if dma_command.IsReady() {
+			return 0
+		}
+	}
+
+	return this.Min(
+		this.memory_scheduler.NumSkippableCycles(),
+		this.row_buffer.NumSkippableCycles(),
+	)
+}
+
+func (this *MemoryController) FastForward(num_cycles int64) {
+	for num_cycles > 0 {
+		num_skippable_cycles := this.NumSkippableCycles()
+
+		if num_skippable_cycles == 0 {
+			this.Cycle()
+			num_cycles--
+		} else {
+			num_skippable_cycles = this.Min(num_skippable_cycles, num_cycles)
+			this.Skip(num_skippable_cycles)
+			num_cycles -= num_skippable_cycles
+		}
+	}
+}
+
+func (this *MemoryController) Skip(num_cycles int64) {
+	this.memory_scheduler.Skip(num_cycles)
+	this.row_buffer.Skip(num_cycles)
+
+	this.input_q.Skip(num_cycles)
+	this.wait_q.Skip(num_cycles)
+	this.memory_command_q.Skip(num_cycles)
+	this.ready_q.Skip(num_cycles)
+
+	this.stat_factory.Increment("memory_cycle", num_cycles)
+}
+
 func (this *MemoryController) ServiceInputQ() {
 	if this.input_q.CanPop(1) && this.wait_q.CanPush(1) && this.memory_scheduler.CanPush() {
 		dma_command := this.input_q.Pop()
@@ -294,3 +397,27 @@ func (this *MemoryController) Min(x int64, y int64) int64 {
 		return y
 	}
 }
+
+func (this *MemoryController) Checkpoint(checkpoint_writer *misc.CheckpointWriter) {
+	this.input_q.Checkpoint(checkpoint_writer)
+	this.wait_q.Checkpoint(checkpoint_writer)
+	this.memory_command_q.Checkpoint(checkpoint_writer)
+	this.ready_q.Checkpoint(checkpoint_writer)
+
+	this.memory_scheduler.Checkpoint(checkpoint_writer)
+	this.row_buffer.Checkpoint(checkpoint_writer)
+
+	this.stat_factory.Checkpoint(checkpoint_writer)
+}
+
+func (this *MemoryController) Restore(checkpoint_reader *misc.CheckpointReader) {
+	this.input_q.Restore(checkpoint_reader)
+	this.wait_q.Restore(checkpoint_reader)
+	this.memory_command_q.Restore(checkpoint_reader)
+	this.ready_q.Restore(checkpoint_reader)
+
+	this.memory_scheduler.Restore(checkpoint_reader)
+	this.row_buffer.Restore(checkpoint_reader)
+
+	this.stat_factory.Restore(checkpoint_reader)
+}
@@ -3,6 +3,7 @@ package dram
 import (
 	"errors"
 	"fmt"
+	"math"
 	"uPIMulator/src/misc"
 )
 
@@ -122,6 +123,20 @@ func (this *MemoryScheduler) Cycle() {
 	this.ready_q.Cycle()
 }
 
+func (this *MemoryScheduler) NumSkippableCycles() int64 {
+	if this.IsEmpty() {
+		return math.MaxInt64
+	} else {
+		return 0
+	}
+}
+
+func (this *MemoryScheduler) Skip(num_cycles int64) {
+	this.input_q.Skip(num_cycles)
+	this.reorder_buffer.Skip(num_cycles)
+	this.ready_q.Skip(num_cycles)
+}
+
 func (this *MemoryScheduler) ServiceInputQ() {
 	if this.input_q.CanPop(1) {
 		dma_command := this.input_q.Pop()
@@ -135,15 +150,7 @@ func (this *MemoryScheduler) PopulateMemoryCommands(dma_command *DmaCommand) {
 	end_address := dma_command.MramAddress() + dma_command.Size()
 
 	for address := begin_address; address < end_address; {
-		wordline_address := this.WordlineAddress(address)
-
-		size := this.Min(
-			this.Min(
-				address+this.min_access_granularity,
-				wordline_address+this.wordline_size,
-			),
-			end_address,
-		) - address
+		size := this.MemoryCommandSize(address, end_address)
 
 		memory_operation := dma_command.MemoryOperation()
 		memory_command := new(MemoryCommand)
@@ -163,6 +170,20 @@ func (this *MemoryScheduler) PopulateMemoryCommands(dma_command *DmaCommand) {
 	}
 }
 
+// MemoryCommandSize is the size of the memory command at the address, which accesses at most the
+// minimum access granularity within a wordline and up to the end address.
+func (this *MemoryScheduler) MemoryCommandSize(address int64, end_address int64) int64 {
+	wordline_address := this.WordlineAddress(address)
+
+	return this.Min(
+		this.Min(
+			address+this.min_access_granularity,
+			wordline_address+this.wordline_size,
+		),
+		end_address,
+	) - address
+}
+
 func (this *MemoryScheduler) ReorderFr() bool {
 	if this.row_address != nil && this.ready_q.CanPush(1) {
 		for i := 0; this.reorder_buffer.CanPop(i + 1); i++ {
@@ -240,3 +261,30 @@ func (this *MemoryScheduler) Min(x int64, y int64) int64 {
 		return y
 	}
 }
+
+func (this *MemoryScheduler) Checkpoint(checkpoint_writer *misc.CheckpointWriter) {
+	this.input_q.Checkpoint(checkpoint_writer)
+	this.reorder_buffer.Checkpoint(checkpoint_writer)
+	this.ready_q.Checkpoint(checkpoint_writer)
+
+	checkpoint_writer.WriteBool(this.row_address != nil)
+	if this.row_address != nil {
+		checkpoint_writer.WriteInt(*this.row_address)
+	}
+
+	this.stat_factory.Checkpoint(checkpoint_writer)
+}
+
+func (this *MemoryScheduler) Restore(checkpoint_reader *misc.CheckpointReader) {
+	this.input_q.Restore(checkpoint_reader)
+	this.reorder_buffer.Restore(checkpoint_reader)
+	this.ready_q.Restore(checkpoint_reader)
+
+	this.row_address = nil
+	if checkpoint_reader.ReadBool() {
+		this.row_address = new(int64)
+		*this.row_address = checkpoint_reader.ReadInt()
+	}
+
+	this.stat_factory.Restore(checkpoint_reader)
+}
@@ -78,3 +78,36 @@ func (this *Mram) Index(address int64) int {
 
 	return int((address - this.address) / this.wordline_size)
 }
+
+func (this *Mram) Checkpoint(checkpoint_writer *misc.CheckpointWriter) {
+	indices := make([]int, 0)
+	for i, wordline := range this.wordlines {
+		if !wordline.IsZero() {
+			indices = append(indices, i)
+		}
+	}
+
+	checkpoint_writer.WriteInt(int64(len(indices)))
+	for _, index := range indices {
+		checkpoint_writer.WriteInt(int64(index))
+		checkpoint_writer.WriteByteStream(this.wordlines[index].Read())
+	}
+}
+
+func (this *Mram) Restore(checkpoint_reader *misc.CheckpointReader) {
+	for _, wordline := range this.wordlines {
+		wordline.Clear()
+	}
+
+	num_wordlines := int(checkpoint_reader.ReadInt())
+	for i := 0; i < num_wordlines; i++ {
+		index := int(checkpoint_reader.ReadInt())
+
+		if index < 0 || index >= len(this.wordlines) {
+			err := errors.New("wordline index is out of MRAM")
+			panic(err)
+		}
+
+		this.wordlines[index].Write(checkpoint_reader.ReadByteStream())
+	}
+}
@@ -3,6 +3,7 @@ package dram
 import (
 	"errors"
 	"fmt"
+	"math"
 	"uPIMulator/src/abi/encoding"
 	"uPIMulator/src/misc"
 )
@@ -107,6 +108,10 @@ func (this *RowBuffer) ConnectMram(mram *Mram) {
 	this.mram = mram
 }
 
+func (this *RowBuffer) RowAddress() *int64 {
+	return this.row_address
+}
+
 func (this *RowBuffer) StatFactory() *misc.StatFactory {
 	return this.stat_factory
 }
@@ -169,6 +174,86 @@ func (this *RowBuffer) Cycle() {
 	this.precharge_q.Cycle()
 }
 
+func (this *RowBuffer) NumSkippableCycles() int64 {
+	if !this.ready_q.IsEmpty() {
+		return 0
+	} else if this.input_q.CanPop(1) && !this.IsInputQBlocked() {
+		return 0
+	}
+
+	num_skippable_cycles := int64(math.MaxInt64)
+
+	if !this.activation_q.IsEmpty() {
+		_, cycle := this.activation_q.Front(0)
+
+		if cycle <= 0 || cycle == this.t_ras-this.t_rcd {
+			return 0
+		} else if this.t_ras-this.t_rcd > 0 && cycle > this.t_ras-this.t_rcd {
+			num_skippable_cycles = this.Min(num_skippable_cycles, cycle-(this.t_ras-this.t_rcd))
+		} else {
+			num_skippable_cycles = this.Min(num_skippable_cycles, cycle)
+		}
+	}
+
+	if !this.io_q.IsEmpty() {
+		_, cycle := this.io_q.Front(0)
+
+		if cycle > 0 {
+			num_skippable_cycles = this.Min(num_skippable_cycles, cycle)
+		} else if this.bus_q.CanPush(1) {
+			return 0
+		}
+	}
+
+	if !this.bus_q.IsEmpty() {
+		_, cycle := this.bus_q.Front(0)
+
+		if cycle > 0 {
+			num_skippable_cycles = this.Min(num_skippable_cycles, cycle)
+		} else {
+			return 0
+		}
+	}
+
+	if !this.precharge_q.IsEmpty() {
+		_, cycle := this.precharge_q.Front(0)
+
+		if cycle > 0 {
+			num_skippable_cycles = this.Min(num_skippable_cycles, cycle)
+		} else {
+			return 0
+		}
+	}
+
+	return num_skippable_cycles
+}
+
+func (this *RowBuffer) IsInputQBlocked() bool {
+	memory_command, _ := this.input_q.Front(0)
+	memory_operation := memory_command.MemoryOperation()
+
+	if memory_operation == ACTIVATION {
+		return !this.activation_q.IsEmpty() || this.row_address != nil
+	} else if memory_operation == READ || memory_operation == WRITE {
+		return !this.io_q.CanPush(1) || this.row_address == nil
+	} else if memory_operation == PRECHARGE {
+		return !this.activation_q.IsEmpty() || !this.io_q.IsEmpty() || !this.bus_q.IsEmpty() ||
+			!this.precharge_q.IsEmpty()
+	} else {
+		err := errors.New("memory operation is not valid")
+		panic(err)
+	}
+}
+
+func (this *RowBuffer) Skip(num_cycles int64) {
+	this.input_q.Skip(num_cycles)
+	this.ready_q.Skip(num_cycles)
+	this.activation_q.Skip(num_cycles)
+	this.io_q.Skip(num_cycles)
+	this.bus_q.Skip(num_cycles)
+	this.precharge_q.Skip(num_cycles)
+}
+
 func (this *RowBuffer) ServiceInputQ() {
 	if this.input_q.CanPop(1) {
 		memory_command, _ := this.input_q.Front(0)
@@ -245,18 +330,26 @@ func (this *RowBuffer) ServiceBusQ() {
 		if memory_operation == READ {
 			byte_stream := this.ReadFromRowBuffer(memory_command.Address(), memory_command.Size())
 			memory_command.SetByteStream(byte_stream)
-
-			this.stat_factory.Increment("num_reads", 1)
-			this.stat_factory.Increment("read_bytes", memory_command.Size())
 		} else if memory_operation == WRITE {
 			this.WriteToRowBuffer(memory_command.Address(), memory_command.Size(), memory_command.ByteStream())
-
-			this.stat_factory.Increment("num_writes", 1)
-			this.stat_factory.Increment("write_bytes", memory_command.Size())
-		} else {
-			err := errors.New("memory operation is not valid")
-			panic(err)
 		}
+
+		this.Account(memory_operation, memory_command.Size())
+	}
+}
+
+// Account counts a read or a write of the size, whether the row buffer serves it or the DMA
+// transfers it functionally.
+func (this *RowBuffer) Account(memory_operation MemoryOperation, size int64) {
+	if memory_operation == READ {
+		this.stat_factory.Increment("num_reads", 1)
+		this.stat_factory.Increment("read_bytes", size)
+	} else if memory_operation == WRITE {
+		this.stat_factory.Increment("num_writes", 1)
+		this.stat_factory.Increment("write_bytes", size)
+	} else {
+		err := errors.New("memory operation is not valid")
+		panic(err)
 	}
 }
 
@@ -346,3 +439,54 @@ func (this *RowBuffer) Index(address int64) int {
 
 	return int(address - *this.row_address)
 }
+
+func (this *RowBuffer) Min(x int64, y int64) int64 {
+	if x <= y {
+		return x
+	} else {
+		return y
+	}
+}
+
+func (this *RowBuffer) Checkpoint(checkpoint_writer *misc.CheckpointWriter) {
+	checkpoint_writer.WriteBool(this.row_address != nil)
+	if this.row_address != nil {
+		checkpoint_writer.WriteInt(*this.row_address)
+	}
+
+	checkpoint_writer.WriteBool(this.row_buffer != nil)
+	if this.row_buffer != nil {
+		checkpoint_writer.WriteByteStream(this.row_buffer)
+	}
+
+	this.input_q.Checkpoint(checkpoint_writer)
+	this.ready_q.Checkpoint(checkpoint_writer)
+	this.activation_q.Checkpoint(checkpoint_writer)
+	this.io_q.Checkpoint(checkpoint_writer)
+	this.bus_q.Checkpoint(checkpoint_writer)
+	this.precharge_q.Checkpoint(checkpoint_writer)
+
+	this.stat_factory.Checkpoint(checkpoint_writer)
+}
+
+func (this *RowBuffer) Restore(checkpoint_reader *misc.CheckpointReader) {
+	this.row_address = nil
+	if checkpoint_reader.ReadBool() {
+		this.row_address = new(int64)
+		*this.row_address = checkpoint_reader.ReadInt()
+	}
+
+	this.row_buffer = nil
+	if checkpoint_reader.ReadBool() {
+		this.row_buffer = checkpoint_reader.ReadByteStream()
+	}
+
+	this.input_q.Restore(checkpoint_reader)
+	this.ready_q.Restore(checkpoint_reader)
+	this.activation_q.Restore(checkpoint_reader)
+	this.io_q.Restore(checkpoint_reader)
+	this.bus_q.Restore(checkpoint_reader)
+	this.precharge_q.Restore(checkpoint_reader)
+
+	this.stat_factory.Restore(checkpoint_reader)
+}
@@ -64,3 +64,18 @@ func (this *Wordline) Write(byte_stream *encoding.ByteStream) {
 		this.byte_stream.Set(int(i), byte_stream.Get(int(i)))
 	}
 }
+
+func (this *Wordline) IsZero() bool {
+	for i := int64(0); i < this.byte_stream.Size(); i++ {
+		if this.byte_stream.Get(int(i)) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (this *Wordline) Clear() {
+	for i := int64(0); i < this.byte_stream.Size(); i++ {
+		this.byte_stream.Set(int(i), 0)
+	}
+}
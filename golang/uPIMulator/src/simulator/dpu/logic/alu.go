@@ -105,9 +105,9 @@ func (this *Alu) Sub(operand1 int64, operand2 int64) (int64, bool, bool) {
 	result_word.SetValue(result)
 
 	var overflow bool
-	if word1.SignBit() && !word2.SignBit() && result_word.SignBit() {
+	if word1.SignBit() && !word2.SignBit() && !result_word.SignBit() {
 		overflow = true
-	} else if !word1.SignBit() && word2.SignBit() && !result_word.SignBit() {
+	} else if !word1.SignBit() && word2.SignBit() && result_word.SignBit() {
 		overflow = true
 	} else {
 		overflow = false
@@ -133,7 +133,7 @@ func (this *Alu) Subc(operand1 int64, operand2 int64, carry_flag bool) (int64, b
 	var result int64
 	var carry bool
 	if carry_flag {
-		if word1.Value(word.UNSIGNED)+1 >= word2.Value(word.UNSIGNED) {
+		if word1.Value(word.UNSIGNED) >= word2.Value(word.UNSIGNED)+1 {
 			result = word1.Value(word.UNSIGNED) - word2.Value(word.UNSIGNED) - 1
 			carry = false
 		} else {
@@ -155,9 +155,9 @@ func (this *Alu) Subc(operand1 int64, operand2 int64, carry_flag bool) (int64, b
 	result_word.SetValue(result)
 
 	var overflow bool
-	if word1.SignBit() && !word2.SignBit() && result_word.SignBit() {
+	if word1.SignBit() && !word2.SignBit() && !result_word.SignBit() {
 		overflow = true
-	} else if !word1.SignBit() && word2.SignBit() && !result_word.SignBit() {
+	} else if !word1.SignBit() && word2.SignBit() && result_word.SignBit() {
 		overflow = true
 	} else {
 		overflow = false
@@ -347,7 +347,7 @@ func (this *Alu) Xor(operand1 int64, operand2 int64) int64 {
 	for i := 0; i < mram_data_width; i++ {
 		if !word1.Bit(i) && word2.Bit(i) {
 			result_word.SetBit(i)
-		} else if word1.Bit(i) && word2.Bit(i) {
+		} else if word1.Bit(i) && !word2.Bit(i) {
 			result_word.SetBit(i)
 		} else {
 			result_word.ClearBit(i)
@@ -376,7 +376,7 @@ func (this *Alu) Nxor(operand1 int64, operand2 int64) int64 {
 	for i := 0; i < mram_data_width; i++ {
 		if !word1.Bit(i) && word2.Bit(i) {
 			result_word.ClearBit(i)
-		} else if word1.Bit(i) && word2.Bit(i) {
+		} else if word1.Bit(i) && !word2.Bit(i) {
 			result_word.ClearBit(i)
 		} else {
 			result_word.SetBit(i)
@@ -496,7 +496,8 @@ func (this *Alu) Lsl1(operand int64, shift int64) int64 {
 }
 
 func (this *Alu) Lsl1x(operand int64, shift int64) int64 {
-	err := errors.New("lsl1x is not yet implemented")
+	err := new(misc.SimulationError)
+	err.Init(misc.UNSUPPORTED_INSTRUCTION, "lsl1x is not yet implemented")
 	panic(err)
 }
 
@@ -587,7 +588,8 @@ func (this *Alu) Lsr1(operand int64, shift int64) int64 {
 }
 
 func (this *Alu) Lsr1x(operand int64, shift int64) int64 {
-	err := errors.New("lsr1x is not yet implemented")
+	err := new(misc.SimulationError)
+	err.Init(misc.UNSUPPORTED_INSTRUCTION, "lsr1x is not yet implemented")
 	panic(err)
 }
 
@@ -633,7 +635,6 @@ func (this *Alu) Rol(operand int64, shift int64) int64 {
 			} else {
 				result_word.ClearBit(i)
 			}
-			result_word.SetBit(i)
 		} else {
 			if word_.Bit(i - int(shift_value)) {
 				result_word.SetBit(i)
@@ -674,7 +675,6 @@ func (this *Alu) Ror(operand int64, shift int64) int64 {
 			} else {
 				result_word.ClearBit(i)
 			}
-			result_word.SetBit(i)
 		} else {
 			if word_.Bit(i + int(shift_value)) {
 				result_word.SetBit(i)
@@ -1197,12 +1197,14 @@ func (this *Alu) MulUlUl(operand1 int64, operand2 int64) int64 {
 }
 
 func (this *Alu) Sats(operand int64) int64 {
-	err := errors.New("sats is not yet implemented")
+	err := new(misc.SimulationError)
+	err.Init(misc.UNSUPPORTED_INSTRUCTION, "sats is not yet implemented")
 	panic(err)
 }
 
 func (this *Alu) Hash(operand1 int64, operand2 int64) int64 {
-	err := errors.New("hash is not yet implemented")
+	err := new(misc.SimulationError)
+	err.Init(misc.UNSUPPORTED_INSTRUCTION, "hash is not yet implemented")
 	panic(err)
 }
 
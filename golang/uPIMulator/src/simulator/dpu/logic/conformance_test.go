@@ -0,0 +1,954 @@
+package logic
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"uPIMulator/src/abi/word"
+	"uPIMulator/src/linker/kernel"
+	"uPIMulator/src/linker/kernel/instruction"
+	"uPIMulator/src/linker/kernel/instruction/reg_descriptor"
+	"uPIMulator/src/linker/lexer"
+	linker_logic "uPIMulator/src/linker/logic"
+	"uPIMulator/src/linker/parser"
+	"uPIMulator/src/misc"
+	"uPIMulator/src/simulator/dpu/sram"
+)
+
+// A ConformanceCase is an instruction in assembly text with the state of the thread that it is
+// executed from and the state that it is expected to leave. The instruction is placed at the start
+// of the IRAM and may name the label target, which is placed two instructions after it, so that a
+// taken branch is told apart from the next instruction. The registers are the GP registers, e.g.,
+// r0, and the WRAM is given in words of 32 bits at WRAM addresses.
+type ConformanceCase struct {
+	Name     string
+	Assembly string
+
+	Regs  map[string]int64
+	Zero  bool
+	Carry bool
+	Wram  map[int64]int64
+
+	Expected ConformanceState
+}
+
+// A ConformanceState is the state that an instruction is expected to leave: the registers and the
+// WRAM words that it names, the flags, and whether the PC has moved to the target label rather than
+// to the next instruction.
+type ConformanceState struct {
+	Regs  map[string]int64
+	Zero  bool
+	Carry bool
+	Wram  map[int64]int64
+	Taken bool
+}
+
+// A ConformanceRunner executes instructions one at a time through Logic.ExecuteInstruction with a
+// thread of its own, outside the pipeline and the cycle rule.
+type ConformanceRunner struct {
+	command_line_parser *misc.CommandLineParser
+	executables         map[string]*kernel.Executable
+
+	logic             *Logic
+	thread            *Thread
+	atomic            *sram.Atomic
+	iram              *sram.Iram
+	wram              *sram.Wram
+	operand_collector *OperandCollector
+}
+
+func (this *ConformanceRunner) Init() {
+	this.command_line_parser = new(misc.CommandLineParser)
+	this.command_line_parser.Init()
+	this.command_line_parser.AddOption(misc.INT, "num_channels", "1", "")
+	this.command_line_parser.AddOption(misc.INT, "num_ranks_per_channel", "1", "")
+	this.command_line_parser.AddOption(misc.INT, "num_dpus_per_rank", "1", "")
+	this.command_line_parser.AddOption(misc.INT, "num_tasklets", "1", "")
+	this.command_line_parser.AddOption(misc.INT, "num_pipeline_stages", "14", "")
+	this.command_line_parser.AddOption(misc.INT, "num_revolver_scheduling_cycles", "11", "")
+	this.command_line_parser.AddOption(misc.INT, "min_access_granularity", "8", "")
+	this.command_line_parser.AddOption(misc.INT, "verbose", "0", "")
+
+	this.executables = make(map[string]*kernel.Executable, 0)
+}
+
+// Assemble links the instruction at the start of the IRAM through the lexer, the parser, and the
+// instruction assigner of the linker, and returns the executable, which the runner keeps for the
+// next cases of the same instruction.
+func (this *ConformanceRunner) Assemble(t testing.TB, assembly string) *kernel.Executable {
+	if executable, found := this.executables[assembly]; found {
+		return executable
+	}
+
+	lines := []string{
+		"\t.section\t.text.__bootstrap,\"ax\",@progbits",
+		"__bootstrap:",
+		"\t" + assembly,
+		"\tnop",
+		"target:",
+		"\tnop",
+	}
+
+	path := filepath.Join(t.TempDir(), "main.S")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	executable := new(kernel.Executable)
+	executable.Init("conformance")
+	executable.SetPath(path)
+
+	lexer_ := new(lexer.Lexer)
+	lexer_.Init()
+	executable.SetTokenStream(lexer_.Lex(path))
+
+	parser_ := new(parser.Parser)
+	parser_.Init()
+	executable.SetAst(parser_.Parse(executable.TokenStream()))
+
+	label_assigner := new(linker_logic.LabelAssigner)
+	label_assigner.Init()
+	label_assigner.Assign(executable)
+
+	linker_script := new(linker_logic.LinkerScript)
+	linker_script.Init(this.command_line_parser)
+	linker_script.Assign(executable)
+
+	instruction_assigner := new(linker_logic.InstructionAssigner)
+	instruction_assigner.Init(linker_script)
+	instruction_assigner.Assign(executable)
+
+	this.executables[assembly] = executable
+	return executable
+}
+
+// Reset makes a DPU of a single runnable thread whose IRAM holds the instruction.
+func (this *ConformanceRunner) Reset(executable *kernel.Executable) {
+	this.thread = new(Thread)
+	this.thread.Init(0)
+	this.thread.SetThreadState(RUNNABLE)
+
+	thread_scheduler := new(ThreadScheduler)
+	thread_scheduler.Init(0, 0, 0, []*Thread{this.thread}, this.command_line_parser)
+
+	this.atomic = new(sram.Atomic)
+	this.atomic.Init()
+
+	this.iram = new(sram.Iram)
+	this.iram.Init()
+	this.iram.Write(this.iram.Address(), executable.IramByteStream())
+
+	this.wram = new(sram.Wram)
+	this.wram.Init()
+
+	this.operand_collector = new(OperandCollector)
+	this.operand_collector.Init()
+	this.operand_collector.ConnectWram(this.wram)
+
+	this.logic = new(Logic)
+	this.logic.Init(0, 0, 0, this.command_line_parser)
+	this.logic.ConnectThreadScheduler(thread_scheduler)
+	this.logic.ConnectAtomic(this.atomic)
+	this.logic.ConnectIram(this.iram)
+	this.logic.ConnectOperandCollector(this.operand_collector)
+
+	this.thread.RegFile().WritePcReg(this.iram.Address())
+}
+
+// Execute executes the instruction of the case from its initial state, returning what the
+// instruction has raised, if anything.
+func (this *ConformanceRunner) Execute(
+	t testing.TB,
+	conformance_case *ConformanceCase,
+) (recovered interface{}) {
+	this.Reset(this.Assemble(t, conformance_case.Assembly))
+
+	for name, value := range conformance_case.Regs {
+		this.thread.RegFile().WriteGpReg(this.GpRegDescriptor(t, name), value)
+	}
+
+	if conformance_case.Zero {
+		this.thread.RegFile().SetFlag(instruction.ZERO)
+	}
+	if conformance_case.Carry {
+		this.thread.RegFile().SetFlag(instruction.CARRY)
+	}
+
+	for address, value := range conformance_case.Wram {
+		this.operand_collector.Sw(address, value)
+	}
+
+	instruction_ := this.iram.Read(this.thread.RegFile().ReadPcReg())
+	this.logic.scoreboard[instruction_] = this.thread
+
+	defer func() {
+		recovered = recover()
+	}()
+
+	this.logic.ExecuteInstruction(instruction_)
+	return nil
+}
+
+// Check compares the state that the instruction has left with the expected state of the case.
+func (this *ConformanceRunner) Check(t testing.TB, conformance_case *ConformanceCase) {
+	t.Helper()
+
+	expected := conformance_case.Expected
+	reg_file := this.thread.RegFile()
+
+	for name, value := range expected.Regs {
+		reg := reg_file.ReadGpReg(this.GpRegDescriptor(t, name), word.UNSIGNED)
+		if reg != this.Unsigned(value) {
+			t.Errorf("%s: %s is %#x, expected %#x", conformance_case.Name, name, reg, this.Unsigned(value))
+		}
+	}
+
+	if zero := reg_file.ReadFlagReg(instruction.ZERO); zero != expected.Zero {
+		t.Errorf("%s: zero flag is %t, expected %t", conformance_case.Name, zero, expected.Zero)
+	}
+	if carry := reg_file.ReadFlagReg(instruction.CARRY); carry != expected.Carry {
+		t.Errorf("%s: carry flag is %t, expected %t", conformance_case.Name, carry, expected.Carry)
+	}
+
+	for address, value := range expected.Wram {
+		wram := this.operand_collector.Lw(address)
+		if wram != this.Unsigned(value) {
+			t.Errorf("%s: WRAM[%d] is %#x, expected %#x", conformance_case.Name, address, wram, this.Unsigned(value))
+		}
+	}
+
+	pc := reg_file.ReadPcReg()
+	expected_pc := this.NextPc()
+	if expected.Taken {
+		expected_pc = this.TargetPc()
+	}
+
+	if pc != expected_pc {
+		t.Errorf(
+			"%s: PC is %d, expected %d (branch taken: %t)",
+			conformance_case.Name,
+			pc,
+			expected_pc,
+			expected.Taken,
+		)
+	}
+}
+
+func (this *ConformanceRunner) Thread() *Thread {
+	return this.thread
+}
+
+func (this *ConformanceRunner) NextPc() int64 {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	return this.iram.Address() + int64(config_loader.IramDataWidth()/8)
+}
+
+func (this *ConformanceRunner) TargetPc() int64 {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	return this.iram.Address() + 2*int64(config_loader.IramDataWidth()/8)
+}
+
+func (this *ConformanceRunner) GpRegDescriptor(t testing.TB, name string) *reg_descriptor.GpRegDescriptor {
+	index, err := strconv.Atoi(strings.TrimPrefix(name, "r"))
+	if !strings.HasPrefix(name, "r") || err != nil {
+		t.Fatalf("register (%s) is not a GP register", name)
+	}
+
+	gp_reg_descriptor := new(reg_descriptor.GpRegDescriptor)
+	gp_reg_descriptor.Init(index)
+	return gp_reg_descriptor
+}
+
+// Unsigned is the value of a register of the MRAM data width that holds the value.
+func (this *ConformanceRunner) Unsigned(value int64) int64 {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	word_ := new(word.Word)
+	word_.Init(config_loader.MramDataWidth())
+	word_.SetValue(value)
+	return word_.Value(word.UNSIGNED)
+}
+
+// TestConformance executes each conformance case in isolation, as a subtest named after its
+// instruction, and checks the state that it leaves.
+func TestConformance(t *testing.T) {
+	runner := new(ConformanceRunner)
+	runner.Init()
+
+	conformance_cases := append(ConformanceCases(), new(ReferenceModel).ConformanceCases()...)
+	for _, conformance_case := range conformance_cases {
+		t.Run(conformance_case.Name, func(t *testing.T) {
+			if recovered := runner.Execute(t, conformance_case); recovered != nil {
+				t.Fatalf("%s: %s raises %v", conformance_case.Name, conformance_case.Assembly, recovered)
+			}
+
+			runner.Check(t, conformance_case)
+		})
+	}
+}
+
+// TestConformanceArchitecture executes the conformance cases again with an architecture other than
+// the default, i.e., a larger WRAM, more GP registers, and fewer tasklets, along with a case of a GP
+// register that only the architecture has. As the architecture is resolved once per process, the
+// test runs itself in a process of its own, which resolves the architecture of the environment.
+func TestConformanceArchitecture(t *testing.T) {
+	config_overrides := os.Getenv("UPIMULATOR_CONFORMANCE_ARCHITECTURE")
+	if config_overrides == "" {
+		command := exec.Command(os.Args[0], "-test.run=^TestConformanceArchitecture$")
+		command.Env = append(
+			os.Environ(),
+			"UPIMULATOR_CONFORMANCE_ARCHITECTURE=wram_size=262144,num_gp_registers=32,max_num_tasklets=16",
+		)
+
+		if output, err := command.CombinedOutput(); err != nil {
+			t.Fatalf("conformance of the architecture fails: %v\n%s", err, output)
+		}
+		return
+	}
+
+	config_loader := new(misc.ConfigLoader)
+	config_loader.InitDefault()
+
+	for _, config_override := range strings.Split(config_overrides, ",") {
+		name, value, _ := strings.Cut(config_override, "=")
+
+		int_value, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+		config_loader.SetValue(name, int_value)
+	}
+
+	config_validator := new(misc.ConfigValidator)
+	config_validator.Init(config_loader)
+	config_validator.Validate()
+
+	config_loader.Resolve()
+
+	TestConformance(t)
+
+	runner := new(ConformanceRunner)
+	runner.Init()
+
+	conformance_case := &ConformanceCase{
+		Name:     "add to the last GP register",
+		Assembly: "add r31, r0, r1",
+		Regs:     map[string]int64{"r0": 3, "r1": 4},
+		Expected: ConformanceState{Regs: map[string]int64{"r31": 7}},
+	}
+
+	if recovered := runner.Execute(t, conformance_case); recovered != nil {
+		t.Fatalf("%s: %s raises %v", conformance_case.Name, conformance_case.Assembly, recovered)
+	}
+
+	runner.Check(t, conformance_case)
+
+	if runner.wram.Size() != 256*1024 {
+		t.Fatalf("WRAM size is %d, expected %d", runner.wram.Size(), 256*1024)
+	}
+}
+
+// ConformanceCases are the conformance cases of the instructions, which are checked against the
+// default architecture.
+func ConformanceCases() []*ConformanceCase {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	next_pc := config_loader.IramOffset() + int64(config_loader.IramDataWidth()/8)
+
+	// the WRAM words that the loads and the stores access, past the start of the WRAM
+	wram := config_loader.WramOffset() + 512
+
+	return []*ConformanceCase{
+		{
+			Name:     "add",
+			Assembly: "add r2, r0, r1",
+			Regs:     map[string]int64{"r0": 3, "r1": 4},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 7}},
+		},
+		{
+			Name:     "add wraps around with a carry",
+			Assembly: "add r2, r0, r1",
+			Regs:     map[string]int64{"r0": -1, "r1": 1},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 0}, Zero: true, Carry: true},
+		},
+		{
+			Name:     "add immediate",
+			Assembly: "add r2, r0, -5",
+			Regs:     map[string]int64{"r0": 3},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": -2}},
+		},
+		{
+			Name:     "addc adds the carry flag",
+			Assembly: "addc r2, r0, r1",
+			Regs:     map[string]int64{"r0": 3, "r1": 4},
+			Carry:    true,
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 8}},
+		},
+		{
+			Name:     "add branches on zero",
+			Assembly: "add r2, r0, r1, z, target",
+			Regs:     map[string]int64{"r0": 5, "r1": -5},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 0}, Zero: true, Carry: true, Taken: true},
+		},
+		{
+			Name:     "add does not branch on non-zero",
+			Assembly: "add r2, r0, r1, z, target",
+			Regs:     map[string]int64{"r0": 5, "r1": -4},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 1}, Carry: true},
+		},
+		{
+			Name:     "add branches on a negative result",
+			Assembly: "add r2, r0, r1, mi, target",
+			Regs:     map[string]int64{"r0": 1, "r1": -3},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": -2}, Taken: true},
+		},
+		{
+			Name:     "add does not branch on a positive result",
+			Assembly: "add r2, r0, r1, mi, target",
+			Regs:     map[string]int64{"r0": 3, "r1": -1},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 2}, Carry: true},
+		},
+		{
+			Name:     "add branches on a signed overflow",
+			Assembly: "add r2, r0, r1, ov, target",
+			Regs:     map[string]int64{"r0": 0x7fffffff, "r1": 1},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": -0x80000000}, Taken: true},
+		},
+		{
+			Name:     "add immediate branches on a signed overflow",
+			Assembly: "add r2, r0, 1, ov, target",
+			Regs:     map[string]int64{"r0": 0x7fffffff},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": -0x80000000}, Taken: true},
+		},
+		{
+			Name:     "add branches on the sign of its source",
+			Assembly: "add r2, r0, r1, smi, target",
+			Regs:     map[string]int64{"r0": -1, "r1": 2},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 1}, Carry: true, Taken: true},
+		},
+		{
+			Name:     "sub",
+			Assembly: "sub r2, r0, r1",
+			Regs:     map[string]int64{"r0": 7, "r1": 3},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 4}},
+		},
+		{
+			Name:     "sub borrows",
+			Assembly: "sub r2, r0, r1",
+			Regs:     map[string]int64{"r0": 3, "r1": 7},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": -4}, Carry: true},
+		},
+		{
+			Name:     "subc subtracts the carry flag",
+			Assembly: "subc r2, r0, r1",
+			Regs:     map[string]int64{"r0": 7, "r1": 3},
+			Carry:    true,
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 3}},
+		},
+		{
+			Name:     "subc borrows by the carry flag",
+			Assembly: "subc r2, r0, r1",
+			Regs:     map[string]int64{"r0": 3, "r1": 3},
+			Carry:    true,
+			Expected: ConformanceState{Regs: map[string]int64{"r2": -1}, Carry: true},
+		},
+		{
+			Name:     "rsub",
+			Assembly: "rsub r2, r0, r1",
+			Regs:     map[string]int64{"r0": 3, "r1": 7},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 4}},
+		},
+		{
+			Name:     "sub branches on unsigned less than",
+			Assembly: "sub r2, r0, r1, ltu, target",
+			Regs:     map[string]int64{"r0": 1, "r1": -1},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 2}, Carry: true, Taken: true},
+		},
+		{
+			Name:     "sub does not branch on signed less than",
+			Assembly: "sub r2, r0, r1, lts, target",
+			Regs:     map[string]int64{"r0": 1, "r1": -1},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 2}, Carry: true},
+		},
+		{
+			Name:     "sub branches on a negative result",
+			Assembly: "sub r2, r0, r1, mi, target",
+			Regs:     map[string]int64{"r0": 3, "r1": 7},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": -4}, Carry: true, Taken: true},
+		},
+		{
+			Name:     "sub branches on equal",
+			Assembly: "sub r2, r0, r1, eq, target",
+			Regs:     map[string]int64{"r0": 9, "r1": 9},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 0}, Zero: true, Taken: true},
+		},
+		{
+			Name:     "sub branches on the extended zero",
+			Assembly: "sub r2, r0, r1, xz, target",
+			Regs:     map[string]int64{"r0": 9, "r1": 9},
+			Zero:     true,
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 0}, Zero: true, Taken: true},
+		},
+		{
+			Name:     "sub does not branch on the extended zero without the zero flag",
+			Assembly: "sub r2, r0, r1, xz, target",
+			Regs:     map[string]int64{"r0": 9, "r1": 9},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 0}, Zero: true},
+		},
+		{
+			Name:     "sub of an immediate sets on the condition",
+			Assembly: "sub r2, r0, 3, eq",
+			Regs:     map[string]int64{"r0": 3, "r2": 9},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 1}, Zero: true},
+		},
+		{
+			Name:     "sub of an immediate clears on the condition",
+			Assembly: "sub r2, r0, 3, eq",
+			Regs:     map[string]int64{"r0": 4, "r2": 9},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 0}},
+		},
+		{
+			Name:     "sub of an immediate to zero",
+			Assembly: "sub zero, r0, 3, eq",
+			Regs:     map[string]int64{"r0": 3},
+			Expected: ConformanceState{Zero: true},
+		},
+		{
+			Name:     "and",
+			Assembly: "and r2, r0, r1",
+			Regs:     map[string]int64{"r0": 0xff00, "r1": 0x0ff0},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 0x0f00}},
+		},
+		{
+			Name:     "and clears the carry flag",
+			Assembly: "and r2, r0, r1",
+			Regs:     map[string]int64{"r0": 0xf0, "r1": 0x0f},
+			Carry:    true,
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 0}, Zero: true},
+		},
+		{
+			Name:     "or",
+			Assembly: "or r2, r0, r1",
+			Regs:     map[string]int64{"r0": 0xf0, "r1": 0x0f},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 0xff}},
+		},
+		{
+			Name:     "xor branches on a negative result",
+			Assembly: "xor r2, r0, r1, mi, target",
+			Regs:     map[string]int64{"r0": -1, "r1": 1},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": -2}, Taken: true},
+		},
+		{
+			Name:     "nor",
+			Assembly: "nor r2, r0, r1",
+			Regs:     map[string]int64{"r0": 0xf0, "r1": 0x0f},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": -0x100}},
+		},
+		{
+			Name:     "lsl",
+			Assembly: "lsl r2, r0, 4",
+			Regs:     map[string]int64{"r0": 0x0f},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 0xf0}},
+		},
+		{
+			Name:     "lsr",
+			Assembly: "lsr r2, r0, r1",
+			Regs:     map[string]int64{"r0": -1, "r1": 28},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 0xf}},
+		},
+		{
+			Name:     "asr",
+			Assembly: "asr r2, r0, 28",
+			Regs:     map[string]int64{"r0": -0x10000000},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": -1}},
+		},
+		{
+			Name:     "rol",
+			Assembly: "rol r2, r0, 4",
+			Regs:     map[string]int64{"r0": -0x10000000},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 0xf}},
+		},
+		{
+			Name:     "mul_ul_ul",
+			Assembly: "mul_ul_ul r2, r0, r1",
+			Regs:     map[string]int64{"r0": 0x1ff, "r1": 0x3},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 0x2fd}},
+		},
+		{
+			Name:     "mul_sl_sl",
+			Assembly: "mul_sl_sl r2, r0, r1",
+			Regs:     map[string]int64{"r0": 0xff, "r1": 0x3},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": -3}},
+		},
+		{
+			Name:     "clz",
+			Assembly: "clz r2, r0",
+			Regs:     map[string]int64{"r0": 0x00ff0000},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 8}},
+		},
+		{
+			Name:     "cao",
+			Assembly: "cao r2, r0",
+			Regs:     map[string]int64{"r0": 0x00ff00ff},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 16}},
+		},
+		{
+			Name:     "extsb",
+			Assembly: "extsb r2, r0",
+			Regs:     map[string]int64{"r0": 0x1280},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": -0x80}},
+		},
+		{
+			Name:     "extuh",
+			Assembly: "extuh r2, r0",
+			Regs:     map[string]int64{"r0": -1},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 0xffff}},
+		},
+		{
+			Name:     "clo",
+			Assembly: "clo r2, r0",
+			Regs:     map[string]int64{"r0": -0x1000000},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 8}},
+		},
+		{
+			Name:     "clz sets on the condition",
+			Assembly: "clz r2, r0, nz",
+			Regs:     map[string]int64{"r0": 1, "r2": 9},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 1}},
+		},
+		{
+			Name:     "clz to zero branches on non-zero",
+			Assembly: "clz zero, r0, nz, target",
+			Regs:     map[string]int64{"r0": 0},
+			Expected: ConformanceState{Taken: true},
+		},
+		{
+			Name:     "cao.u",
+			Assembly: "cao.u d2, r0",
+			Regs:     map[string]int64{"r0": 7},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 0, "r3": 3}},
+		},
+		{
+			Name:     "extsh",
+			Assembly: "extsh r2, r0",
+			Regs:     map[string]int64{"r0": 0x18000},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": -0x8000}},
+		},
+		{
+			Name:     "extsh.s sign-extends to the pair",
+			Assembly: "extsh.s d2, r0",
+			Regs:     map[string]int64{"r0": 0x8000},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": -1, "r3": -0x8000}},
+		},
+		{
+			Name:     "extub",
+			Assembly: "extub r2, r0",
+			Regs:     map[string]int64{"r0": 0x1ff},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 0xff}},
+		},
+		{
+			Name:     "lsl_add",
+			Assembly: "lsl_add r2, r0, r1, 4",
+			Regs:     map[string]int64{"r0": 1, "r1": 1},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 17}},
+		},
+		{
+			Name:     "lsr_add",
+			Assembly: "lsr_add r2, r0, r1, 4",
+			Regs:     map[string]int64{"r0": 1, "r1": 0x100},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 0x11}},
+		},
+		{
+			Name:     "cmpb4",
+			Assembly: "cmpb4 r2, r0, r1",
+			Regs:     map[string]int64{"r0": 0x11223344, "r1": 0x11003344},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 0x01000101}},
+		},
+		{
+			Name:     "move",
+			Assembly: "move r2, -7",
+			Expected: ConformanceState{Regs: map[string]int64{"r2": -7}},
+		},
+		{
+			Name:     "lw",
+			Assembly: "lw r2, r0, 4",
+			Regs:     map[string]int64{"r0": wram},
+			Wram:     map[int64]int64{wram + 4: 0x12345678},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 0x12345678}},
+		},
+		{
+			Name:     "lbs sign-extends",
+			Assembly: "lbs r2, r0, 1",
+			Regs:     map[string]int64{"r0": wram},
+			Wram:     map[int64]int64{wram: 0x8000},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": -0x80}},
+		},
+		{
+			Name:     "lhu zero-extends",
+			Assembly: "lhu r2, r0, 0",
+			Regs:     map[string]int64{"r0": wram},
+			Wram:     map[int64]int64{wram: 0xffff8000},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 0x8000}},
+		},
+		{
+			Name:     "lbu zero-extends",
+			Assembly: "lbu r2, r0, 3",
+			Regs:     map[string]int64{"r0": wram},
+			Wram:     map[int64]int64{wram: -0x80000000},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 0x80}},
+		},
+		{
+			Name:     "lhs sign-extends",
+			Assembly: "lhs r2, r0, 2",
+			Regs:     map[string]int64{"r0": wram},
+			Wram:     map[int64]int64{wram: -0x80000000},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": -0x8000}},
+		},
+		{
+			Name:     "ld",
+			Assembly: "ld d2, r0, 0",
+			Regs:     map[string]int64{"r0": wram},
+			Wram:     map[int64]int64{wram: 2, wram + 4: 1},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 1, "r3": 2}},
+		},
+		{
+			Name:     "lw.u zero-extends to the pair",
+			Assembly: "lw.u d2, r0, 0",
+			Regs:     map[string]int64{"r0": wram},
+			Wram:     map[int64]int64{wram: -2},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 0, "r3": -2}},
+		},
+		{
+			Name:     "sh",
+			Assembly: "sh r0, 2, r1",
+			Regs:     map[string]int64{"r0": wram, "r1": 0x12345678},
+			Wram:     map[int64]int64{wram: 0x11111111},
+			Expected: ConformanceState{Wram: map[int64]int64{wram: 0x56781111}},
+		},
+		{
+			Name:     "sw of an immediate",
+			Assembly: "sw r0, 4, -7",
+			Regs:     map[string]int64{"r0": wram},
+			Expected: ConformanceState{Wram: map[int64]int64{wram + 4: -7}},
+		},
+		{
+			Name:     "sb of an immediate",
+			Assembly: "sb r0, 0, 0x12",
+			Regs:     map[string]int64{"r0": wram},
+			Wram:     map[int64]int64{wram: 0x11111111},
+			Expected: ConformanceState{Wram: map[int64]int64{wram: 0x11111112}},
+		},
+		{
+			Name:     "sw",
+			Assembly: "sw r0, 8, r1",
+			Regs:     map[string]int64{"r0": wram, "r1": -2},
+			Expected: ConformanceState{Wram: map[int64]int64{wram + 8: -2}},
+		},
+		{
+			Name:     "sb",
+			Assembly: "sb r0, 1, r1",
+			Regs:     map[string]int64{"r0": wram, "r1": 0x1234},
+			Wram:     map[int64]int64{wram: 0x11111111},
+			Expected: ConformanceState{Wram: map[int64]int64{wram: 0x11113411}},
+		},
+		{
+			Name:     "sd",
+			Assembly: "sd r0, 0, d2",
+			Regs:     map[string]int64{"r0": wram, "r2": 1, "r3": 2},
+			Expected: ConformanceState{Wram: map[int64]int64{wram: 2, wram + 4: 1}},
+		},
+		{
+			Name:     "jeq branches on equal",
+			Assembly: "jeq r0, r1, target",
+			Regs:     map[string]int64{"r0": 4, "r1": 4},
+			Expected: ConformanceState{Zero: true, Taken: true},
+		},
+		{
+			Name:     "jneq does not branch on equal",
+			Assembly: "jneq r0, r1, target",
+			Regs:     map[string]int64{"r0": 4, "r1": 4},
+			Expected: ConformanceState{Zero: true},
+		},
+		{
+			Name:     "jltu branches on unsigned less than",
+			Assembly: "jltu r0, 256, target",
+			Regs:     map[string]int64{"r0": 255},
+			Expected: ConformanceState{Carry: true, Taken: true},
+		},
+		{
+			Name:     "jges does not branch on signed less than",
+			Assembly: "jges r0, r1, target",
+			Regs:     map[string]int64{"r0": -1, "r1": 1},
+			Expected: ConformanceState{},
+		},
+		{
+			Name:     "jlts branches on signed less than",
+			Assembly: "jlts r0, r1, target",
+			Regs:     map[string]int64{"r0": -1, "r1": 1},
+			Expected: ConformanceState{Taken: true},
+		},
+		{
+			Name:     "jgtu branches on unsigned greater than",
+			Assembly: "jgtu r0, r1, target",
+			Regs:     map[string]int64{"r0": -1, "r1": 1},
+			Expected: ConformanceState{Taken: true},
+		},
+		{
+			Name:     "jz branches on zero",
+			Assembly: "jz r0, target",
+			Regs:     map[string]int64{"r0": 0},
+			Expected: ConformanceState{Zero: true, Taken: true},
+		},
+		{
+			Name:     "jnz branches on non-zero",
+			Assembly: "jnz r0, target",
+			Regs:     map[string]int64{"r0": 1},
+			Expected: ConformanceState{Taken: true},
+		},
+		{
+			Name:     "jump",
+			Assembly: "jump target",
+			Expected: ConformanceState{Taken: true},
+		},
+		{
+			Name:     "call",
+			Assembly: "call r23, target",
+			Expected: ConformanceState{Regs: map[string]int64{"r23": next_pc}, Taken: true},
+		},
+		{
+			Name:     "time reads the performance counter and leaves the flags",
+			Assembly: "time r2",
+			Regs:     map[string]int64{"r2": 9},
+			Zero:     true,
+			Carry:    true,
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 0}, Zero: true, Carry: true},
+		},
+		{
+			Name:     "time branches on true",
+			Assembly: "time r2, true, target",
+			Regs:     map[string]int64{"r2": 9},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 0}, Taken: true},
+		},
+		{
+			Name:     "time_cfg reads the performance counter and leaves the flags",
+			Assembly: "time_cfg r2, r0",
+			Regs:     map[string]int64{"r0": 7, "r2": 9},
+			Zero:     true,
+			Carry:    true,
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 0}, Zero: true, Carry: true},
+		},
+		{
+			Name:     "time_cfg to zero",
+			Assembly: "time_cfg zero, r0",
+			Regs:     map[string]int64{"r0": 3},
+			Expected: ConformanceState{},
+		},
+		{
+			Name:     "time_cfg branches on true",
+			Assembly: "time_cfg r2, r0, true, target",
+			Regs:     map[string]int64{"r0": 3, "r2": 9},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 0}, Taken: true},
+		},
+		{
+			Name:     "add.s sign-extends to the pair",
+			Assembly: "add.s d2, r0, r1",
+			Regs:     map[string]int64{"r0": -5, "r1": 1},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": -1, "r3": -4}},
+		},
+		{
+			Name:     "add.u zero-extends to the pair",
+			Assembly: "add.u d2, r0, r1",
+			Regs:     map[string]int64{"r0": -5, "r1": 1},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 0, "r3": -4}},
+		},
+		{
+			Name:     "add.s with an immediate sets the pair on the condition",
+			Assembly: "add.s d2, r0, 5, z",
+			Regs:     map[string]int64{"r0": -5, "r2": 9, "r3": 9},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 0, "r3": 1}, Zero: true, Carry: true},
+		},
+		{
+			Name:     "sub.u sets the pair on the condition",
+			Assembly: "sub.u d2, r0, r1, ltu",
+			Regs:     map[string]int64{"r0": 1, "r1": 2, "r2": 9, "r3": 9},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 0, "r3": 1}, Carry: true},
+		},
+		{
+			Name:     "sub.s from an immediate clears the pair on the condition",
+			Assembly: "sub.s d2, 5, r0, z",
+			Regs:     map[string]int64{"r0": 4, "r2": 9, "r3": 9},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 0, "r3": 0}},
+		},
+		{
+			Name:     "sub.s of an immediate sets the pair on the condition",
+			Assembly: "sub.s d2, r0, 3, eq",
+			Regs:     map[string]int64{"r0": 3, "r2": 9, "r3": 9},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 0, "r3": 1}, Zero: true},
+		},
+		{
+			Name:     "and.s branches on zero",
+			Assembly: "and.s d2, r0, r1, z, target",
+			Regs:     map[string]int64{"r0": 0xf0, "r1": 0x0f, "r2": 9, "r3": 9},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 0, "r3": 0}, Zero: true, Taken: true},
+		},
+		{
+			Name:     "sub.s branches on negative",
+			Assembly: "sub.s d2, r0, r1, mi, target",
+			Regs:     map[string]int64{"r0": 1, "r1": 2},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": -1, "r3": -1}, Carry: true, Taken: true},
+		},
+		{
+			Name:     "clz.u",
+			Assembly: "clz.u d2, r0",
+			Regs:     map[string]int64{"r0": 1},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 0, "r3": 31}},
+		},
+		{
+			Name:     "extsb.s branches on negative",
+			Assembly: "extsb.s d2, r0, mi, target",
+			Regs:     map[string]int64{"r0": 0x80},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": -1, "r3": -0x80}, Taken: true},
+		},
+		{
+			Name:     "lsl_add.u",
+			Assembly: "lsl_add.u d2, r0, r1, 4",
+			Regs:     map[string]int64{"r0": 1, "r1": 1},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 0, "r3": 17}},
+		},
+		{
+			Name:     "lw.s sign-extends to the pair",
+			Assembly: "lw.s d2, r0, 4",
+			Regs:     map[string]int64{"r0": wram},
+			Wram:     map[int64]int64{wram + 4: -2},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": -1, "r3": -2}},
+		},
+		{
+			Name:     "lbs.u zero-extends the signed byte to the pair",
+			Assembly: "lbs.u d2, r0, 0",
+			Regs:     map[string]int64{"r0": wram},
+			Wram:     map[int64]int64{wram: 0x80},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 0, "r3": -0x80}},
+		},
+		{
+			Name:     "time.s",
+			Assembly: "time.s d2",
+			Regs:     map[string]int64{"r2": 9, "r3": 9},
+			Expected: ConformanceState{Regs: map[string]int64{"r2": 0, "r3": 0}},
+		},
+		{
+			Name:     "time_cfg to zero branches on true",
+			Assembly: "time_cfg zero, r0, true, target",
+			Regs:     map[string]int64{"r0": 3},
+			Expected: ConformanceState{Taken: true},
+		},
+	}
+}
@@ -171,3 +171,42 @@ func (this *CycleRule) CalculateExtraCycles(instruction_ *instruction.Instructio
 
 	return int64(even_counter/2 + odd_counter/2)
 }
+
+func (this *CycleRule) Checkpoint(checkpoint_writer *misc.CheckpointWriter) {
+	this.input_q.Checkpoint(checkpoint_writer)
+	this.wait_q.Checkpoint(checkpoint_writer)
+	this.ready_q.Checkpoint(checkpoint_writer)
+
+	checkpoint_writer.WriteInt(int64(len(this.scoreboard)))
+	for instruction_, thread := range this.scoreboard {
+		checkpoint_writer.WriteObject(instruction_)
+		checkpoint_writer.WriteInt(int64(thread.ThreadId()))
+	}
+
+	for _, reg_set := range this.reg_sets {
+		reg_set.Checkpoint(checkpoint_writer)
+	}
+
+	this.stat_factory.Checkpoint(checkpoint_writer)
+}
+
+func (this *CycleRule) Restore(checkpoint_reader *misc.CheckpointReader, threads []*Thread) {
+	this.input_q.Restore(checkpoint_reader)
+	this.wait_q.Restore(checkpoint_reader)
+	this.ready_q.Restore(checkpoint_reader)
+
+	this.scoreboard = make(map[*instruction.Instruction]*Thread, 0)
+	num_instructions := int(checkpoint_reader.ReadInt())
+	for i := 0; i < num_instructions; i++ {
+		instruction_, _ := checkpoint_reader.ReadObject(new(instruction.Instruction)).(*instruction.Instruction)
+		thread_id := int(checkpoint_reader.ReadInt())
+
+		this.scoreboard[instruction_] = threads[thread_id]
+	}
+
+	for _, reg_set := range this.reg_sets {
+		reg_set.Restore(checkpoint_reader)
+	}
+
+	this.stat_factory.Restore(checkpoint_reader)
+}
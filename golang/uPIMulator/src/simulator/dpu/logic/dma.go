@@ -17,6 +17,9 @@ type Dma struct {
 
 	input_q *dram.DmaCommandQ
 	ready_q *dram.DmaCommandQ
+
+	tracer    *misc.Tracer
+	sanitizer *misc.Sanitizer
 }
 
 func (this *Dma) Init() {
@@ -35,6 +38,9 @@ func (this *Dma) Init() {
 
 	this.ready_q = new(dram.DmaCommandQ)
 	this.ready_q.Init(max_num_tasklets, 0)
+
+	this.tracer = nil
+	this.sanitizer = nil
 }
 
 func (this *Dma) Fini() {
@@ -78,6 +84,24 @@ func (this *Dma) ConnectMemoryController(memory_controller *dram.MemoryControlle
 	this.memory_controller = memory_controller
 }
 
+func (this *Dma) ConnectTracer(tracer *misc.Tracer) {
+	if this.tracer != nil {
+		err := errors.New("tracer is already set")
+		panic(err)
+	}
+
+	this.tracer = tracer
+}
+
+func (this *Dma) ConnectSanitizer(sanitizer *misc.Sanitizer) {
+	if this.sanitizer != nil {
+		err := errors.New("sanitizer is already set")
+		panic(err)
+	}
+
+	this.sanitizer = sanitizer
+}
+
 func (this *Dma) IsEmpty() bool {
 	return this.input_q.IsEmpty() && this.ready_q.IsEmpty()
 }
@@ -126,6 +150,10 @@ func (this *Dma) TransferToWram(address int64, byte_stream *encoding.ByteStream)
 		value := byte_stream.Get(int(i))
 		this.operand_collector.Sb(address+i, int64(value))
 	}
+
+	if this.sanitizer != nil {
+		this.sanitizer.InitializeWram(address, byte_stream.Size())
+	}
 }
 
 func (this *Dma) TransferFromMram(address int64, size int64) *encoding.ByteStream {
@@ -135,6 +163,10 @@ func (this *Dma) TransferFromMram(address int64, size int64) *encoding.ByteStrea
 
 func (this *Dma) TransferToMram(address int64, byte_stream *encoding.ByteStream) {
 	this.memory_controller.Write(address, byte_stream.Size(), byte_stream)
+
+	if this.sanitizer != nil {
+		this.sanitizer.InitializeMram(address, byte_stream.Size())
+	}
 }
 
 func (this *Dma) TransferFromWramToMram(
@@ -173,6 +205,23 @@ func (this *Dma) TransferFromMramToWram(
 	this.Push(dma_command)
 }
 
+func (this *Dma) TransferFromMramToIram(
+	iram_address int64,
+	mram_address int64,
+	size int64,
+	instruction_ *instruction.Instruction,
+) {
+	if !this.CanPush() {
+		err := errors.New("DMA cannot be pushed")
+		panic(err)
+	}
+
+	dma_command := new(dram.DmaCommand)
+	dma_command.InitReadFromMramToIram(iram_address, mram_address, size, instruction_)
+
+	this.Push(dma_command)
+}
+
 func (this *Dma) CanPush() bool {
 	return this.input_q.CanPush(1)
 }
@@ -184,6 +233,27 @@ func (this *Dma) Push(dma_command *dram.DmaCommand) {
 	}
 
 	this.input_q.Push(dma_command)
+
+	if this.tracer != nil {
+		args := map[string]interface{}{
+			"mram_address": dma_command.MramAddress(),
+			"size":         dma_command.Size(),
+		}
+
+		var name string
+		if dma_command.HasIramAddress() {
+			name = "ldmai"
+			args["iram_address"] = dma_command.IramAddress()
+		} else if dma_command.MemoryOperation() == dram.READ {
+			name = "ldma"
+			args["wram_address"] = dma_command.WramAddress()
+		} else {
+			name = "sdma"
+			args["wram_address"] = dma_command.WramAddress()
+		}
+
+		this.tracer.BeginAsync(dma_command, name, "dma", args)
+	}
 }
 
 func (this *Dma) CanPop() bool {
@@ -196,7 +266,43 @@ func (this *Dma) Pop() *dram.DmaCommand {
 		panic(err)
 	}
 
-	return this.ready_q.Pop()
+	dma_command := this.ready_q.Pop()
+
+	if this.tracer != nil {
+		this.tracer.EndAsync(dma_command, "dma")
+	}
+
+	return dma_command
+}
+
+// Drain completes every pending DMA command without going through the memory controller.
+func (this *Dma) Drain() {
+	for this.input_q.CanPop(1) {
+		dma_command := this.input_q.Pop()
+
+		mram_address := dma_command.MramAddress()
+		size := dma_command.Size()
+
+		if dma_command.HasIramAddress() {
+			byte_stream := this.TransferFromMram(mram_address, size)
+			this.iram.Write(dma_command.IramAddress(), byte_stream)
+		} else if dma_command.MemoryOperation() == dram.READ {
+			byte_stream := this.TransferFromMram(mram_address, size)
+			this.TransferToWram(dma_command.WramAddress(), byte_stream)
+		} else {
+			// the open row would otherwise be written back over the transferred bytes
+			this.memory_controller.Flush()
+
+			byte_stream := dma_command.ByteStream(mram_address, size)
+			this.TransferToMram(mram_address, byte_stream)
+		}
+
+		this.memory_controller.Account(dma_command)
+
+		if this.tracer != nil {
+			this.tracer.EndAsync(dma_command, "dma")
+		}
+	}
 }
 
 func (this *Dma) Cycle() {
@@ -208,6 +314,10 @@ func (this *Dma) ServiceInputQ() {
 	if this.input_q.CanPop(1) && this.memory_controller.CanPush() {
 		dma_command := this.input_q.Pop()
 		this.memory_controller.Push(dma_command)
+
+		if this.tracer != nil {
+			this.tracer.StepAsync(dma_command, "memory_controller", "dma")
+		}
 	}
 }
 
@@ -216,7 +326,18 @@ func (this *Dma) ServiceReadyQ() {
 		dma_command := this.memory_controller.Pop()
 		this.ready_q.Push(dma_command)
 
-		if dma_command.MemoryOperation() == dram.READ {
+		if this.tracer != nil {
+			this.tracer.StepAsync(dma_command, "ready", "dma")
+		}
+
+		if dma_command.HasIramAddress() {
+			iram_address := dma_command.IramAddress()
+			mram_address := dma_command.MramAddress()
+			size := dma_command.Size()
+			byte_stream := dma_command.ByteStream(mram_address, size)
+
+			this.iram.Write(iram_address, byte_stream)
+		} else if dma_command.MemoryOperation() == dram.READ {
 			wram_address := dma_command.WramAddress()
 			mram_address := dma_command.MramAddress()
 			size := dma_command.Size()
@@ -226,3 +347,13 @@ func (this *Dma) ServiceReadyQ() {
 		}
 	}
 }
+
+func (this *Dma) Checkpoint(checkpoint_writer *misc.CheckpointWriter) {
+	this.input_q.Checkpoint(checkpoint_writer)
+	this.ready_q.Checkpoint(checkpoint_writer)
+}
+
+func (this *Dma) Restore(checkpoint_reader *misc.CheckpointReader) {
+	this.input_q.Restore(checkpoint_reader)
+	this.ready_q.Restore(checkpoint_reader)
+}
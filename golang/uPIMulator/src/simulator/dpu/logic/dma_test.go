@@ -0,0 +1,148 @@
+package logic
+
+import (
+	"testing"
+	"uPIMulator/src/abi/encoding"
+	"uPIMulator/src/linker/kernel/instruction"
+	"uPIMulator/src/misc"
+	"uPIMulator/src/simulator/dpu/dram"
+)
+
+// TestLdmai loads an overlay of two instructions from the MRAM into the IRAM right after the ldmai
+// and checks that both are fetched from there once the DMA has completed. The size of ldmai is
+// encoded as that of ldma, so an immediate of 2 loads three blocks of the minimum access granularity,
+// which are the two instructions.
+func TestLdmai(t *testing.T) {
+	runner := new(ConformanceRunner)
+	runner.Init()
+	runner.command_line_parser.AddOption(misc.INT, "wordline_size", "1024", "")
+	runner.command_line_parser.AddOption(misc.INT, "t_rcd", "32", "")
+	runner.command_line_parser.AddOption(misc.INT, "t_ras", "78", "")
+	runner.command_line_parser.AddOption(misc.INT, "t_rp", "32", "")
+	runner.command_line_parser.AddOption(misc.INT, "t_cl", "32", "")
+	runner.command_line_parser.AddOption(misc.INT, "t_bl", "8", "")
+
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	iram_data_size := int64(config_loader.IramDataWidth() / 8)
+
+	overlay := new(encoding.ByteStream)
+	overlay.Init()
+	for _, assembly := range []string{"add r2, r0, r1", "sub r3, r0, r1"} {
+		byte_stream := runner.Assemble(t, assembly).IramByteStream()
+		for i := 0; i < int(iram_data_size); i++ {
+			overlay.Append(byte_stream.Get(i))
+		}
+	}
+
+	runner.Reset(runner.Assemble(t, "ldmai r0, r1, 2"))
+
+	mram := new(dram.Mram)
+	mram.Init(runner.command_line_parser)
+
+	memory_controller := new(dram.MemoryController)
+	memory_controller.Init(0, 0, 0, runner.command_line_parser)
+	memory_controller.ConnectMram(mram)
+	memory_controller.Write(mram.Address(), overlay.Size(), overlay)
+
+	dma := new(Dma)
+	dma.Init()
+	dma.ConnectIram(runner.iram)
+	dma.ConnectOperandCollector(runner.operand_collector)
+	dma.ConnectMemoryController(memory_controller)
+	runner.logic.ConnectDma(dma)
+
+	iram_address := runner.iram.Address() + iram_data_size
+
+	runner.thread.RegFile().WriteGpReg(runner.GpRegDescriptor(t, "r0"), iram_address)
+	runner.thread.RegFile().WriteGpReg(runner.GpRegDescriptor(t, "r1"), mram.Address())
+
+	instruction_ := runner.iram.Read(runner.thread.RegFile().ReadPcReg())
+	runner.logic.scoreboard[instruction_] = runner.thread
+	runner.logic.ExecuteInstruction(instruction_)
+
+	if runner.iram.Read(iram_address).OpCode() == instruction.ADD {
+		t.Fatalf("IRAM is written before the DMA has completed")
+	}
+
+	num_cycles := 0
+	for ; !dma.CanPop(); num_cycles++ {
+		if num_cycles > 1000 {
+			t.Fatalf("DMA has not completed in %d cycles", num_cycles)
+		}
+
+		dma.Cycle()
+		memory_controller.Cycle()
+	}
+
+	if dma_command := dma.Pop(); dma_command.Instruction() != instruction_ {
+		t.Fatalf("DMA command is not of the ldmai")
+	} else if dma_command.Size() != overlay.Size() {
+		t.Fatalf("ldmai transfers %d bytes, expected %d", dma_command.Size(), overlay.Size())
+	}
+
+	expected := []string{"add, rrr, r2, r0, r1", "sub, rrr, r3, r0, r1"}
+	for i, instruction_ := range expected {
+		if loaded := runner.iram.Read(iram_address + int64(i)*iram_data_size); loaded.Stringify() != instruction_ {
+			t.Fatalf("IRAM holds (%s), expected (%s)", loaded.Stringify(), instruction_)
+		}
+	}
+}
+
+// TestLdmaiFault issues ldmai out of the IRAM and the MRAM, and of a size that is not a whole number
+// of instructions, which faults the DPU instead of reaching the DMA.
+func TestLdmaiFault(t *testing.T) {
+	runner := new(ConformanceRunner)
+	runner.Init()
+
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	iram_address := config_loader.IramOffset()
+	mram_address := config_loader.MramOffset()
+	mram_end_address := config_loader.MramOffset() + config_loader.MramSize()
+
+	// an immediate of 2 loads two whole instructions and one of 0 loads part of an instruction
+	fault_cases := []struct {
+		name     string
+		assembly string
+		regs     map[string]int64
+	}{
+		{"ldmai below the IRAM", "ldmai r0, r1, 2", map[string]int64{"r0": 0, "r1": mram_address}},
+		{
+			"ldmai unaligned in the IRAM",
+			"ldmai r0, r1, 2",
+			map[string]int64{"r0": iram_address + 1, "r1": mram_address},
+		},
+		{"ldmai past the MRAM", "ldmai r0, r1, 2", map[string]int64{"r0": iram_address, "r1": mram_end_address}},
+		{
+			"ldmai of part of an instruction",
+			"ldmai r0, r1, 0",
+			map[string]int64{"r0": iram_address, "r1": mram_address},
+		},
+	}
+
+	for _, fault_case := range fault_cases {
+		runner.Reset(runner.Assemble(t, fault_case.assembly))
+
+		for name, value := range fault_case.regs {
+			runner.thread.RegFile().WriteGpReg(runner.GpRegDescriptor(t, name), value)
+		}
+
+		// a DMA instruction moves the PC of its thread past itself as it is issued
+		instruction_ := runner.iram.Read(runner.thread.RegFile().ReadPcReg())
+		runner.logic.scoreboard[instruction_] = runner.thread
+		runner.thread.RegFile().IncrementPcReg()
+		runner.logic.ExecuteInstruction(instruction_)
+
+		reg_file := runner.thread.RegFile()
+		if runner.thread.ThreadState() != ZOMBIE {
+			t.Fatalf("%s: tasklet is %s, expected ZOMBIE", fault_case.name, runner.thread.StringifyThreadState())
+		} else if !reg_file.ReadExceptionReg(instruction.DMA_FAULT) {
+			t.Fatalf("%s: DMA fault is not set", fault_case.name)
+		} else if reg_file.ReadPcReg() != runner.iram.Address() {
+			t.Fatalf("%s: PC is 0x%x, expected the faulting instruction", fault_case.name, reg_file.ReadPcReg())
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package logic
+
+import (
+	"testing"
+	"uPIMulator/src/misc"
+)
+
+func TestFault(t *testing.T) {
+	runner := new(ConformanceRunner)
+	runner.Init()
+
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	wram_end_address := config_loader.WramOffset() + config_loader.WramSize()
+
+	fault_cases := []struct {
+		name       string
+		assembly   string
+		regs       map[string]int64
+		exceptions string
+	}{
+		{"fault of a failed assert", "fault 3", nil, "assert, fault 3"},
+		{"fault of a full heap", "fault 1", nil, "heap_full, fault 1"},
+		{"fault of a code of the program", "fault 42", nil, "fault 42"},
+		{
+			"load out of the WRAM",
+			"lw r2, r0, 0",
+			map[string]int64{"r0": config_loader.WramOffset() - 4},
+			"memory_fault",
+		},
+		{"store out of the WRAM", "sw r0, 0, r1", map[string]int64{"r0": wram_end_address}, "memory_fault"},
+	}
+
+	for _, fault_case := range fault_cases {
+		conformance_case := &ConformanceCase{
+			Name:     fault_case.name,
+			Assembly: fault_case.assembly,
+			Regs:     fault_case.regs,
+		}
+
+		if recovered := runner.Execute(t, conformance_case); recovered != nil {
+			t.Fatalf("%s: raises %v", fault_case.name, recovered)
+		}
+
+		reg_file := runner.thread.RegFile()
+		if runner.thread.ThreadState() != ZOMBIE {
+			t.Fatalf("%s: tasklet is %s, expected ZOMBIE", fault_case.name, runner.thread.StringifyThreadState())
+		} else if exceptions := reg_file.StringifyExceptions(); exceptions != fault_case.exceptions {
+			t.Fatalf("%s: exceptions are (%s), expected (%s)", fault_case.name, exceptions, fault_case.exceptions)
+		} else if reg_file.ReadPcReg() != runner.iram.Address() {
+			t.Fatalf("%s: PC is 0x%x, expected the faulting instruction", fault_case.name, reg_file.ReadPcReg())
+		}
+	}
+}
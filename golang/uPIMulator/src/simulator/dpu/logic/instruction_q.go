@@ -3,6 +3,7 @@ package logic
 import (
 	"errors"
 	"uPIMulator/src/linker/kernel/instruction"
+	"uPIMulator/src/misc"
 )
 
 type InstructionQ struct {
@@ -124,3 +125,30 @@ func (this *InstructionQ) Cycle() {
 		this.cycles[0] -= 1
 	}
 }
+
+func (this *InstructionQ) Skip(num_cycles int64) {
+	if !this.IsEmpty() {
+		this.cycles[0] -= num_cycles
+	}
+}
+
+func (this *InstructionQ) Checkpoint(checkpoint_writer *misc.CheckpointWriter) {
+	checkpoint_writer.WriteInt(int64(len(this.instructions)))
+	for i, instruction_ := range this.instructions {
+		checkpoint_writer.WriteObject(instruction_)
+		checkpoint_writer.WriteInt(this.cycles[i])
+	}
+}
+
+func (this *InstructionQ) Restore(checkpoint_reader *misc.CheckpointReader) {
+	this.instructions = make([]*instruction.Instruction, 0)
+	this.cycles = make([]int64, 0)
+
+	num_instructions := int(checkpoint_reader.ReadInt())
+	for i := 0; i < num_instructions; i++ {
+		instruction_, _ := checkpoint_reader.ReadObject(new(instruction.Instruction)).(*instruction.Instruction)
+
+		this.instructions = append(this.instructions, instruction_)
+		this.cycles = append(this.cycles, checkpoint_reader.ReadInt())
+	}
+}
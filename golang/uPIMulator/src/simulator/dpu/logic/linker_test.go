@@ -0,0 +1,52 @@
+package logic
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestLink links each instruction through the runner and checks the instruction that is decoded
+// back from the IRAM, in which the label target stands for its address.
+func TestLink(t *testing.T) {
+	runner := new(ConformanceRunner)
+	runner.Init()
+
+	link_cases := []struct {
+		assembly string
+		expected string
+	}{
+		{"add r2, r0, r1", "add, rrr, r2, r0, r1"},
+		{"add.s d2, r0, r1", "add, s_rrr, d2, r0, r1"},
+		{"add.u d2, r0, r1", "add, u_rrr, d2, r0, r1"},
+		{"add.s d2, r0, r1, z", "add, s_rrrc, d2, r0, r1, z"},
+		{"add.s d2, r0, r1, z, target", "add, s_rrrci, d2, r0, r1, z, target"},
+		{"add.s d2, r0, 5, z", "add, s_rric, d2, r0, 5, z"},
+		{"add.u d2, r0, 5, false", "add, u_rrif, d2, r0, 5, false"},
+		{"add zero, r0, 5, false", "add, zrif, r0, 5, false"},
+		{"sub.s d2, 5, r0, z", "sub, s_rirc, d2, 5, r0, z"},
+		{"sub zero, -128, r0, xz", "sub, zirc, -128, r0, xz"},
+		{"subc zero, 127, r0, nz, target", "subc, zirci, 127, r0, nz, target"},
+		{"extsb.s d2, r0, z", "extsb, s_rrc, d2, r0, z"},
+		{"time.s d2", "time, s_r, d2"},
+		{"lsl_add.u d2, r0, r1, 4", "lsl_add, u_rrri, d2, r0, r1, 4"},
+		{"lsl_add.s d2, r0, r1, 4, true, target", "lsl_add, s_rrrici, d2, r0, r1, 4, true, target"},
+	}
+
+	for _, link_case := range link_cases {
+		t.Run(link_case.assembly, func(t *testing.T) {
+			defer func() {
+				if recovered := recover(); recovered != nil {
+					t.Fatalf("%s raises %v", link_case.assembly, recovered)
+				}
+			}()
+
+			runner.Reset(runner.Assemble(t, link_case.assembly))
+
+			expected := strings.ReplaceAll(link_case.expected, "target", strconv.FormatInt(runner.TargetPc(), 10))
+			if linked := runner.iram.Read(runner.iram.Address()).Stringify(); linked != expected {
+				t.Fatalf("%s is linked to (%s), expected (%s)", link_case.assembly, linked, expected)
+			}
+		})
+	}
+}
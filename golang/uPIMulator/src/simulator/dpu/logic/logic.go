@@ -24,6 +24,7 @@ type Logic struct {
 	verbose int
 
 	min_access_granularity int64
+	mram_data_width        int
 
 	thread_scheduler  *ThreadScheduler
 	atomic            *sram.Atomic
@@ -36,8 +37,15 @@ type Logic struct {
 	pipeline   *Pipeline
 	cycle_rule *CycleRule
 
-	alu    *Alu
-	wait_q *InstructionQ
+	alu          *Alu
+	perf_counter *PerfCounter
+	wait_q       *InstructionQ
+
+	profiler            *misc.Profiler
+	call_graph_profiler *misc.CallGraphProfiler
+	race_detector       *misc.RaceDetector
+	sanitizer           *misc.Sanitizer
+	progress_tracker    *misc.ProgressTracker
 
 	stat_factory *misc.StatFactory
 }
@@ -88,12 +96,23 @@ func (this *Logic) Init(
 	this.alu = new(Alu)
 	this.alu.Init()
 
+	this.perf_counter = new(PerfCounter)
+	this.perf_counter.Init()
+
 	config_loader := new(misc.ConfigLoader)
 	config_loader.Init()
 
 	this.wait_q = new(InstructionQ)
 	this.wait_q.Init(config_loader.MaxNumTasklets(), 0)
 
+	this.mram_data_width = config_loader.MramDataWidth()
+
+	this.profiler = nil
+	this.call_graph_profiler = nil
+	this.race_detector = nil
+	this.sanitizer = nil
+	this.progress_tracker = nil
+
 	name := fmt.Sprintf("Logic[%d_%d_%d]", channel_id, rank_id, dpu_id)
 	this.stat_factory = new(misc.StatFactory)
 	this.stat_factory.Init(name)
@@ -104,6 +123,7 @@ func (this *Logic) Fini() {
 	this.cycle_rule.Fini()
 
 	this.alu.Fini()
+	this.perf_counter.Fini()
 	this.wait_q.Fini()
 }
 
@@ -152,10 +172,59 @@ func (this *Logic) ConnectDma(dma *Dma) {
 	this.dma = dma
 }
 
+func (this *Logic) ConnectProfiler(profiler *misc.Profiler) {
+	if this.profiler != nil {
+		err := errors.New("profiler is already set")
+		panic(err)
+	}
+
+	this.profiler = profiler
+}
+
+func (this *Logic) ConnectCallGraphProfiler(call_graph_profiler *misc.CallGraphProfiler) {
+	if this.call_graph_profiler != nil {
+		err := errors.New("call graph profiler is already set")
+		panic(err)
+	}
+
+	this.call_graph_profiler = call_graph_profiler
+}
+
+func (this *Logic) ConnectRaceDetector(race_detector *misc.RaceDetector) {
+	if this.race_detector != nil {
+		err := errors.New("race detector is already set")
+		panic(err)
+	}
+
+	this.race_detector = race_detector
+}
+
+func (this *Logic) ConnectSanitizer(sanitizer *misc.Sanitizer) {
+	if this.sanitizer != nil {
+		err := errors.New("sanitizer is already set")
+		panic(err)
+	}
+
+	this.sanitizer = sanitizer
+}
+
+func (this *Logic) ConnectProgressTracker(progress_tracker *misc.ProgressTracker) {
+	if this.progress_tracker != nil {
+		err := errors.New("progress tracker is already set")
+		panic(err)
+	}
+
+	this.progress_tracker = progress_tracker
+}
+
 func (this *Logic) CycleRule() *CycleRule {
 	return this.cycle_rule
 }
 
+func (this *Logic) PerfCounter() *PerfCounter {
+	return this.perf_counter
+}
+
 func (this *Logic) StatFactory() *misc.StatFactory {
 	return this.stat_factory
 }
@@ -164,6 +233,39 @@ func (this *Logic) IsEmpty() bool {
 	return this.pipeline.IsEmpty() && this.cycle_rule.IsEmpty() && this.wait_q.IsEmpty()
 }
 
+func (this *Logic) Checkpoint(checkpoint_writer *misc.CheckpointWriter) {
+	checkpoint_writer.WriteInt(int64(len(this.scoreboard)))
+	for instruction_, thread := range this.scoreboard {
+		checkpoint_writer.WriteObject(instruction_)
+		checkpoint_writer.WriteInt(int64(thread.ThreadId()))
+	}
+
+	this.pipeline.Checkpoint(checkpoint_writer)
+	this.cycle_rule.Checkpoint(checkpoint_writer)
+	this.perf_counter.Checkpoint(checkpoint_writer)
+	this.wait_q.Checkpoint(checkpoint_writer)
+
+	this.stat_factory.Checkpoint(checkpoint_writer)
+}
+
+func (this *Logic) Restore(checkpoint_reader *misc.CheckpointReader, threads []*Thread) {
+	this.scoreboard = make(map[*instruction.Instruction]*Thread, 0)
+	num_instructions := int(checkpoint_reader.ReadInt())
+	for i := 0; i < num_instructions; i++ {
+		instruction_, _ := checkpoint_reader.ReadObject(new(instruction.Instruction)).(*instruction.Instruction)
+		thread_id := int(checkpoint_reader.ReadInt())
+
+		this.scoreboard[instruction_] = threads[thread_id]
+	}
+
+	this.pipeline.Restore(checkpoint_reader)
+	this.cycle_rule.Restore(checkpoint_reader, threads)
+	this.perf_counter.Restore(checkpoint_reader)
+	this.wait_q.Restore(checkpoint_reader)
+
+	this.stat_factory.Restore(checkpoint_reader)
+}
+
 func (this *Logic) Cycle() {
 	this.ServiceThreadScheduler()
 	this.ServicePipeline()
@@ -176,9 +278,102 @@ func (this *Logic) Cycle() {
 
 	this.wait_q.Cycle()
 
+	this.perf_counter.Cycle(1)
+
 	this.stat_factory.Increment("logic_cycle", 1)
 }
 
+// Drain cycles the logic without issuing new instructions so that the in-flight ones can retire.
+func (this *Logic) Drain() {
+	this.ServicePipeline()
+	this.ServiceCycleRule()
+	this.ServiceLogic()
+	this.ServiceDma()
+
+	this.pipeline.Cycle()
+	this.cycle_rule.Cycle()
+
+	this.wait_q.Cycle()
+
+	this.perf_counter.Cycle(1)
+}
+
+func (this *Logic) IsStalled() bool {
+	return this.thread_scheduler.NumIssuableThreads() == 0 && this.pipeline.IsEmpty() &&
+		this.cycle_rule.IsEmpty() &&
+		this.dma.IsEmpty()
+}
+
+func (this *Logic) Skip(num_cycles int64) {
+	if this.pipeline.CanPush() && this.cycle_rule.CanPush() && this.wait_q.CanPush(1) {
+		this.thread_scheduler.Skip(num_cycles)
+	} else {
+		this.stat_factory.Increment("backpressure", num_cycles)
+	}
+	this.stat_factory.Increment("active_tasklets_0", num_cycles)
+
+	this.Profile(nil, num_cycles)
+
+	// an empty pipeline only shifts nil bubbles, so it reaches its steady state
+	// within a pipeline depth and stays there for the rest of the skipped cycles.
+	for i := int64(0); i < num_cycles && i <= int64(this.pipeline.NumPipelineStages()); i++ {
+		this.ServicePipeline()
+		this.pipeline.Cycle()
+	}
+
+	this.wait_q.Skip(num_cycles)
+
+	this.perf_counter.Cycle(num_cycles)
+
+	this.stat_factory.Increment("logic_cycle", num_cycles)
+}
+
+// The functional mode executes one instruction of every runnable thread in round-robin order,
+// bypassing the pipeline and the cycle rule, and completes DMA instructions immediately.
+func (this *Logic) Step() {
+	threads := this.thread_scheduler.Threads()
+
+	for _, thread := range threads {
+		if thread.ThreadState() == RUNNABLE {
+			pc := thread.RegFile().ReadPcReg()
+			instruction_ := this.iram.Read(pc)
+
+			this.scoreboard[instruction_] = thread
+
+			if this.call_graph_profiler != nil {
+				this.call_graph_profiler.Issue(thread.ThreadId(), pc)
+			}
+			if this.progress_tracker != nil {
+				this.progress_tracker.Issue(thread.ThreadId(), pc)
+			}
+
+			if instruction_.Suffix() != instruction.DMA_RRI {
+				this.ExecuteInstruction(instruction_)
+			} else {
+				thread.RegFile().IncrementPcReg()
+				this.ExecuteInstruction(instruction_)
+				this.dma.Drain()
+
+				if this.progress_tracker != nil {
+					this.progress_tracker.Progress()
+				}
+			}
+
+			delete(this.scoreboard, instruction_)
+
+			if this.profiler != nil {
+				this.profiler.Issue(pc)
+			}
+
+			this.perf_counter.Issue()
+
+			this.stat_factory.Increment("num_instructions", 1)
+		}
+	}
+
+	this.perf_counter.Cycle(1)
+}
+
 func (this *Logic) ServiceThreadScheduler() {
 	if this.pipeline.CanPush() && this.cycle_rule.CanPush() && this.wait_q.CanPush(1) {
 		thread := this.thread_scheduler.Schedule()
@@ -187,6 +382,16 @@ func (this *Logic) ServiceThreadScheduler() {
 			pc := thread.RegFile().ReadPcReg()
 			instruction_ := this.iram.Read(pc)
 
+			if this.profiler != nil {
+				this.profiler.Issue(pc)
+			}
+			if this.call_graph_profiler != nil {
+				this.call_graph_profiler.Issue(thread.ThreadId(), pc)
+			}
+			if this.progress_tracker != nil {
+				this.progress_tracker.Issue(thread.ThreadId(), pc)
+			}
+
 			this.scoreboard[instruction_] = thread
 
 			this.pipeline.Push(instruction_)
@@ -199,14 +404,57 @@ func (this *Logic) ServiceThreadScheduler() {
 				this.wait_q.Push(instruction_)
 			}
 
+			this.perf_counter.Issue()
+
 			this.stat_factory.Increment("num_instructions", 1)
 		}
 
 		active_tasklets := fmt.Sprintf("active_tasklets_%d", this.thread_scheduler.NumIssuableThreads())
 		this.stat_factory.Increment(active_tasklets, 1)
+
+		this.Profile(thread, 1)
 	} else {
 		this.stat_factory.Increment("backpressure", 1)
 		this.stat_factory.Increment("active_tasklets_0", 1)
+
+		this.Profile(nil, 1)
+	}
+}
+
+// Profile attributes the cycles to the PCs of the tasklets other than the issued one, as stall
+// cycles if they are runnable and as DMA wait cycles if they are blocked, and to the call stacks
+// of the tasklets that have been booted.
+func (this *Logic) Profile(issued_thread *Thread, num_cycles int64) {
+	if this.profiler != nil {
+		config_loader := new(misc.ConfigLoader)
+		config_loader.Init()
+
+		iram_data_size := int64(config_loader.IramDataWidth() / 8)
+
+		for _, thread := range this.thread_scheduler.Threads() {
+			if thread == issued_thread {
+				continue
+			}
+
+			pc := thread.RegFile().ReadPcReg()
+
+			if thread.ThreadState() == RUNNABLE {
+				this.profiler.Stall(pc, num_cycles)
+			} else if thread.ThreadState() == BLOCK {
+				// a blocked tasklet has already moved its PC past the DMA instruction
+				this.profiler.WaitDma(pc-iram_data_size, num_cycles)
+			}
+		}
+	}
+
+	if this.call_graph_profiler != nil {
+		for _, thread := range this.thread_scheduler.Threads() {
+			if thread.ThreadState() == EMBRYO || thread.ThreadState() == ZOMBIE {
+				this.call_graph_profiler.Exit(thread.ThreadId())
+			} else {
+				this.call_graph_profiler.Tick(thread.ThreadId(), num_cycles)
+			}
+		}
 	}
 }
 
@@ -227,6 +475,9 @@ func (this *Logic) ServiceCycleRule() {
 
 		if instruction_.Suffix() != instruction.DMA_RRI {
 			delete(this.scoreboard, instruction_)
+		} else if this.scoreboard[instruction_].ThreadState() == ZOMBIE {
+			// a fault has stopped the DPU before the DMA instruction has reached the DMA
+			this.Unwait(instruction_)
 		} else {
 			this.ExecuteInstruction(instruction_)
 		}
@@ -247,7 +498,14 @@ func (this *Logic) ServiceDma() {
 			if dma_command.Instruction() == instruction_ {
 				thread := this.scoreboard[instruction_]
 
-				this.thread_scheduler.Awake(thread.ThreadId())
+				// a fault has stopped the tasklet while it has been waiting for the DMA
+				if thread.ThreadState() == BLOCK {
+					this.thread_scheduler.Awake(thread.ThreadId())
+
+					if this.progress_tracker != nil {
+						this.progress_tracker.Progress()
+					}
+				}
 
 				this.wait_q.Remove(i)
 				delete(this.scoreboard, instruction_)
@@ -267,6 +525,8 @@ func (this *Logic) ServiceDma() {
 func (this *Logic) ExecuteInstruction(instruction_ *instruction.Instruction) {
 	thread := this.scoreboard[instruction_]
 
+	defer this.Recover(instruction_, thread, this.InstructionPc(instruction_))
+
 	unique_dpu_id := this.channel_id*this.num_ranks_per_channel*this.num_dpus_per_rank + this.rank_id*this.num_dpus_per_rank + this.dpu_id
 
 	if this.verbose >= 1 {
@@ -405,7 +665,8 @@ func (this *Logic) ExecuteInstruction(instruction_ *instruction.Instruction) {
 	} else if suffix == instruction.DMA_RRI {
 		this.ExecuteDmaRri(instruction_)
 	} else {
-		err := errors.New("suffix is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "suffix is not valid")
 		panic(err)
 	}
 
@@ -431,7 +692,8 @@ func (this *Logic) ExecuteRici(instruction_ *instruction.Instruction) {
 	} else if _, is_boot_rici_op_code := instruction_.BootRiciOpCodes()[op_code]; is_boot_rici_op_code {
 		this.ExecuteBootRici(instruction_)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 }
@@ -450,11 +712,20 @@ func (this *Logic) ExecuteAcquireRici(instruction_ *instruction.Instruction) {
 	ra := thread.RegFile().ReadSrcReg(instruction_.Ra(), word.UNSIGNED)
 	imm := instruction_.Imm().Value()
 
+	this.AccessAtomic(instruction_, ra+imm)
+
 	atomic_address := this.alu.AtomicAddressHash(ra, imm)
 
 	can_acquire := this.atomic.CanAcquire(atomic_address)
 	if can_acquire {
 		this.atomic.Acquire(atomic_address, thread.ThreadId())
+
+		if this.race_detector != nil {
+			this.race_detector.Acquire(thread.ThreadId(), atomic_address)
+		}
+		if this.progress_tracker != nil {
+			this.progress_tracker.Progress()
+		}
 	}
 
 	thread.RegFile().ClearConditions()
@@ -491,11 +762,23 @@ func (this *Logic) ExecuteReleaseRici(instruction_ *instruction.Instruction) {
 	ra := thread.RegFile().ReadSrcReg(instruction_.Ra(), word.UNSIGNED)
 	imm := instruction_.Imm().Value()
 
+	this.AccessAtomic(instruction_, ra+imm)
+
 	atomic_address := this.alu.AtomicAddressHash(ra, imm)
 
 	can_release := this.atomic.CanRelease(atomic_address, thread.ThreadId())
 	if can_release {
+		// releasing a lock that is not held does not change the lock state
+		is_held := !this.atomic.CanAcquire(atomic_address)
+
 		this.atomic.Release(atomic_address, thread.ThreadId())
+
+		if this.race_detector != nil {
+			this.race_detector.Release(thread.ThreadId(), atomic_address)
+		}
+		if this.progress_tracker != nil && is_held {
+			this.progress_tracker.Progress()
+		}
 	}
 
 	thread.RegFile().ClearConditions()
@@ -540,6 +823,8 @@ func (this *Logic) ExecuteBootRici(instruction_ *instruction.Instruction) {
 	if op_code == instruction.BOOT {
 		can_boot := this.thread_scheduler.Boot(thread_id)
 		if can_boot {
+			this.DetectSignal(thread, thread_id)
+
 			this.SetBootCc(instruction_, ra, 0)
 			this.SetFlags(instruction_, 0, false)
 		} else {
@@ -549,6 +834,8 @@ func (this *Logic) ExecuteBootRici(instruction_ *instruction.Instruction) {
 	} else if op_code == instruction.RESUME {
 		can_resume := this.thread_scheduler.Awake(thread_id)
 		if can_resume {
+			this.DetectSignal(thread, thread_id)
+
 			this.SetBootCc(instruction_, ra, 0)
 			this.SetFlags(instruction_, 0, false)
 		} else {
@@ -556,7 +843,8 @@ func (this *Logic) ExecuteBootRici(instruction_ *instruction.Instruction) {
 			this.SetFlags(instruction_, 1, false)
 		}
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -584,7 +872,8 @@ func (this *Logic) ExecuteRri(instruction_ *instruction.Instruction) {
 	} else if _, is_call_rri_op_code := instruction_.CallRriOpCodes()[op_code]; is_call_rri_op_code {
 		this.ExecuteCallRri(instruction_)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 }
@@ -622,7 +911,8 @@ func (this *Logic) ExecuteAddRri(instruction_ *instruction.Instruction) {
 		result = this.alu.Xor(ra, imm)
 		carry = false
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -673,7 +963,8 @@ func (this *Logic) ExecuteAsrRri(instruction_ *instruction.Instruction) {
 	} else if op_code == instruction.ROR {
 		result = this.alu.Ror(ra, imm)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -719,6 +1010,10 @@ func (this *Logic) ExecuteCallRri(instruction_ *instruction.Instruction) {
 
 	thread.RegFile().WritePcReg(result)
 
+	if this.call_graph_profiler != nil {
+		this.call_graph_profiler.Call(thread.ThreadId(), result, pc+iram_data_size)
+	}
+
 	this.SetFlags(instruction_, result, carry)
 }
 
@@ -739,7 +1034,8 @@ func (this *Logic) ExecuteRric(instruction_ *instruction.Instruction) {
 	} else if _, is_sub_rric_op_code := instruction_.SubRricOpCodes()[op_code]; is_sub_rric_op_code {
 		this.ExecuteSubRric(instruction_)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 }
@@ -795,7 +1091,8 @@ func (this *Logic) ExecuteAddRric(instruction_ *instruction.Instruction) {
 		result = this.alu.Hash(ra, imm)
 		carry = false
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -853,7 +1150,8 @@ func (this *Logic) ExecuteAsrRric(instruction_ *instruction.Instruction) {
 	} else if op_code == instruction.ROR {
 		result = this.alu.Ror(ra, imm)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -890,13 +1188,14 @@ func (this *Logic) ExecuteSubRric(instruction_ *instruction.Instruction) {
 	var overflow bool
 
 	op_code := instruction_.OpCode()
-	if op_code == instruction.ASR {
+	if op_code == instruction.SUB {
 		result, carry, overflow = this.alu.Sub(ra, imm)
 	} else if op_code == instruction.SUBC {
 		carry_flag := thread.RegFile().ReadFlagReg(instruction.CARRY)
 		result, carry, overflow = this.alu.Subc(ra, imm, carry_flag)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -933,7 +1232,8 @@ func (this *Logic) ExecuteRrici(instruction_ *instruction.Instruction) {
 	} else if _, is_sub_rrici_op_code := instruction_.SubRriciOpCodes()[op_code]; is_sub_rrici_op_code {
 		this.ExecuteSubRrici(instruction_)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 }
@@ -963,7 +1263,8 @@ func (this *Logic) ExecuteAddRrici(instruction_ *instruction.Instruction) {
 		carry_flag := thread.RegFile().ReadFlagReg(instruction.CARRY)
 		result, carry, overflow = this.alu.Addc(ra, imm, carry_flag)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -1017,7 +1318,8 @@ func (this *Logic) ExecuteAndRrici(instruction_ *instruction.Instruction) {
 	} else if op_code == instruction.HASH {
 		result = this.alu.Hash(ra, imm)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -1075,7 +1377,8 @@ func (this *Logic) ExecuteAsrRrici(instruction_ *instruction.Instruction) {
 	} else if op_code == instruction.ROR {
 		result = this.alu.Ror(ra, imm)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -1118,7 +1421,8 @@ func (this *Logic) ExecuteSubRrici(instruction_ *instruction.Instruction) {
 		carry_flag := thread.RegFile().ReadFlagReg(instruction.CARRY)
 		result, carry, overflow = this.alu.Subc(ra, imm, carry_flag)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -1192,7 +1496,8 @@ func (this *Logic) ExecuteRrif(instruction_ *instruction.Instruction) {
 		result = this.alu.Hash(ra, imm)
 		carry = false
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -1338,7 +1643,8 @@ func (this *Logic) ExecuteRrr(instruction_ *instruction.Instruction) {
 	} else if op_code == instruction.CALL {
 		result, carry, _ = this.alu.Add(ra, rb)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -1378,7 +1684,8 @@ func (this *Logic) ExecuteRrrc(instruction_ *instruction.Instruction) {
 	} else if _, is_sub_rrrc_op_code := instruction_.SubRrrcOpCodes()[op_code]; is_sub_rrrc_op_code {
 		this.ExecuteSubRrrc(instruction_)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 }
@@ -1508,7 +1815,8 @@ func (this *Logic) ExecuteAddRrrc(instruction_ *instruction.Instruction) {
 	} else if op_code == instruction.CALL {
 		result, carry, _ = this.alu.Add(ra, rb)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -1550,7 +1858,8 @@ func (this *Logic) ExecuteRsubRrrc(instruction_ *instruction.Instruction) {
 		carry_flag := thread.RegFile().ReadFlagReg(instruction.CARRY)
 		result, carry, _ = this.alu.Subc(rb, ra, carry_flag)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -1593,7 +1902,8 @@ func (this *Logic) ExecuteSubRrrc(instruction_ *instruction.Instruction) {
 		carry_flag := thread.RegFile().ReadFlagReg(instruction.CARRY)
 		result, carry, overflow = this.alu.Subc(ra, rb, carry_flag)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -1632,7 +1942,8 @@ func (this *Logic) ExecuteRrrci(instruction_ *instruction.Instruction) {
 	} else if _, is_rsub_rrrci_op_code := instruction_.RsubRrrciOpCodes()[op_code]; is_rsub_rrrci_op_code {
 		this.ExecuteRsubRrrci(instruction_)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 }
@@ -1657,12 +1968,13 @@ func (this *Logic) ExecuteAddRrrci(instruction_ *instruction.Instruction) {
 
 	op_code := instruction_.OpCode()
 	if op_code == instruction.ADD {
-		result, carry, _ = this.alu.Add(ra, rb)
+		result, carry, overflow = this.alu.Add(ra, rb)
 	} else if op_code == instruction.ADDC {
 		carry_flag := thread.RegFile().ReadFlagReg(instruction.CARRY)
-		result, carry, _ = this.alu.Addc(ra, rb, carry_flag)
+		result, carry, overflow = this.alu.Addc(ra, rb, carry_flag)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -1716,7 +2028,8 @@ func (this *Logic) ExecuteAndRrrci(instruction_ *instruction.Instruction) {
 	} else if op_code == instruction.HASH {
 		result = this.alu.Hash(ra, rb)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -1776,7 +2089,8 @@ func (this *Logic) ExecuteAsrRrrci(instruction_ *instruction.Instruction) {
 	} else if op_code == instruction.ROR {
 		result = this.alu.Ror(ra, rb)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -1836,7 +2150,8 @@ func (this *Logic) ExecuteMulRrrci(instruction_ *instruction.Instruction) {
 	} else if op_code == instruction.MUL_UL_UL {
 		result = this.alu.MulUlUl(ra, rb)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -1884,7 +2199,8 @@ func (this *Logic) ExecuteRsubRrrci(instruction_ *instruction.Instruction) {
 		carry_flag := thread.RegFile().ReadFlagReg(instruction.CARRY)
 		result, carry, overflow = this.alu.Subc(ra, rb, carry_flag)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -1919,7 +2235,8 @@ func (this *Logic) ExecuteZri(instruction_ *instruction.Instruction) {
 	} else if _, is_call_rri_op_code := instruction_.CallRriOpCodes()[op_code]; is_call_rri_op_code {
 		this.ExecuteCallZri(instruction_)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 }
@@ -1957,7 +2274,8 @@ func (this *Logic) ExecuteAddZri(instruction_ *instruction.Instruction) {
 		result = this.alu.Xor(ra, imm)
 		carry = false
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -2007,7 +2325,8 @@ func (this *Logic) ExecuteAsrZri(instruction_ *instruction.Instruction) {
 	} else if op_code == instruction.ROR {
 		result = this.alu.Ror(ra, imm)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -2048,6 +2367,10 @@ func (this *Logic) ExecuteCallZri(instruction_ *instruction.Instruction) {
 	thread.RegFile().ClearConditions()
 	thread.RegFile().WritePcReg(result)
 
+	if this.call_graph_profiler != nil {
+		this.call_graph_profiler.TailCall(thread.ThreadId(), result)
+	}
+
 	this.SetFlags(instruction_, result, carry)
 }
 
@@ -2068,7 +2391,8 @@ func (this *Logic) ExecuteZric(instruction_ *instruction.Instruction) {
 	} else if _, is_sub_rric_op_code := instruction_.SubRricOpCodes()[op_code]; is_sub_rric_op_code {
 		this.ExecuteSubZric(instruction_)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 }
@@ -2124,7 +2448,8 @@ func (this *Logic) ExecuteAddZric(instruction_ *instruction.Instruction) {
 		result = this.alu.Hash(ra, imm)
 		carry = false
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -2176,7 +2501,8 @@ func (this *Logic) ExecuteAsrZric(instruction_ *instruction.Instruction) {
 	} else if op_code == instruction.ROR {
 		result = this.alu.Ror(ra, imm)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -2207,13 +2533,14 @@ func (this *Logic) ExecuteSubZric(instruction_ *instruction.Instruction) {
 	var overflow bool
 
 	op_code := instruction_.OpCode()
-	if op_code == instruction.ASR {
+	if op_code == instruction.SUB {
 		result, carry, overflow = this.alu.Sub(ra, imm)
 	} else if op_code == instruction.SUBC {
 		carry_flag := thread.RegFile().ReadFlagReg(instruction.CARRY)
 		result, carry, overflow = this.alu.Subc(ra, imm, carry_flag)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -2244,7 +2571,8 @@ func (this *Logic) ExecuteZrici(instruction_ *instruction.Instruction) {
 	} else if _, is_sub_rrici_op_code := instruction_.SubRriciOpCodes()[op_code]; is_sub_rrici_op_code {
 		this.ExecuteSubZrici(instruction_)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 }
@@ -2274,7 +2602,8 @@ func (this *Logic) ExecuteAddZrici(instruction_ *instruction.Instruction) {
 		carry_flag := thread.RegFile().ReadFlagReg(instruction.CARRY)
 		result, carry, overflow = this.alu.Addc(ra, imm, carry_flag)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -2326,7 +2655,8 @@ func (this *Logic) ExecuteAndZrici(instruction_ *instruction.Instruction) {
 	} else if op_code == instruction.HASH {
 		result = this.alu.Hash(ra, imm)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -2382,7 +2712,8 @@ func (this *Logic) ExecuteAsrZrici(instruction_ *instruction.Instruction) {
 	} else if op_code == instruction.ROR {
 		result = this.alu.Ror(ra, imm)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -2423,7 +2754,8 @@ func (this *Logic) ExecuteSubZrici(instruction_ *instruction.Instruction) {
 		carry_flag := thread.RegFile().ReadFlagReg(instruction.CARRY)
 		result, carry, overflow = this.alu.Subc(ra, imm, carry_flag)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -2495,7 +2827,8 @@ func (this *Logic) ExecuteZrif(instruction_ *instruction.Instruction) {
 		result = this.alu.Hash(ra, imm)
 		carry = false
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -2640,7 +2973,8 @@ func (this *Logic) ExecuteZrr(instruction_ *instruction.Instruction) {
 	} else if op_code == instruction.CALL {
 		result, carry, _ = this.alu.Add(ra, rb)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -2672,7 +3006,8 @@ func (this *Logic) ExecuteZrrc(instruction_ *instruction.Instruction) {
 	} else if _, is_sub_rrrc_op_code := instruction_.SubRrrcOpCodes()[op_code]; is_sub_rrrc_op_code {
 		this.ExecuteSubZrrc(instruction_)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 }
@@ -2802,7 +3137,8 @@ func (this *Logic) ExecuteAddZrrc(instruction_ *instruction.Instruction) {
 	} else if op_code == instruction.CALL {
 		result, carry, _ = this.alu.Add(ra, rb)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -2838,7 +3174,8 @@ func (this *Logic) ExecuteRsubZrrc(instruction_ *instruction.Instruction) {
 		carry_flag := thread.RegFile().ReadFlagReg(instruction.CARRY)
 		result, carry, _ = this.alu.Subc(rb, ra, carry_flag)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -2875,7 +3212,8 @@ func (this *Logic) ExecuteSubZrrc(instruction_ *instruction.Instruction) {
 		carry_flag := thread.RegFile().ReadFlagReg(instruction.CARRY)
 		result, carry, overflow = this.alu.Subc(ra, rb, carry_flag)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -2908,7 +3246,8 @@ func (this *Logic) ExecuteZrrci(instruction_ *instruction.Instruction) {
 	} else if _, is_rsub_rrrci_op_code := instruction_.RsubRrrciOpCodes()[op_code]; is_rsub_rrrci_op_code {
 		this.ExecuteRsubZrrci(instruction_)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 }
@@ -2933,12 +3272,13 @@ func (this *Logic) ExecuteAddZrrci(instruction_ *instruction.Instruction) {
 
 	op_code := instruction_.OpCode()
 	if op_code == instruction.ADD {
-		result, carry, _ = this.alu.Add(ra, rb)
+		result, carry, overflow = this.alu.Add(ra, rb)
 	} else if op_code == instruction.ADDC {
 		carry_flag := thread.RegFile().ReadFlagReg(instruction.CARRY)
-		result, carry, _ = this.alu.Addc(ra, rb, carry_flag)
+		result, carry, overflow = this.alu.Addc(ra, rb, carry_flag)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -2990,7 +3330,8 @@ func (this *Logic) ExecuteAndZrrci(instruction_ *instruction.Instruction) {
 	} else if op_code == instruction.HASH {
 		result = this.alu.Hash(ra, rb)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -3048,7 +3389,8 @@ func (this *Logic) ExecuteAsrZrrci(instruction_ *instruction.Instruction) {
 	} else if op_code == instruction.ROR {
 		result = this.alu.Ror(ra, rb)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -3106,7 +3448,8 @@ func (this *Logic) ExecuteMulZrrci(instruction_ *instruction.Instruction) {
 	} else if op_code == instruction.MUL_UL_UL {
 		result = this.alu.MulUlUl(ra, rb)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -3152,7 +3495,8 @@ func (this *Logic) ExecuteRsubZrrci(instruction_ *instruction.Instruction) {
 		carry_flag := thread.RegFile().ReadFlagReg(instruction.CARRY)
 		result, carry, overflow = this.alu.Subc(ra, rb, carry_flag)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -3183,7 +3527,8 @@ func (this *Logic) ExecuteSRri(instruction_ *instruction.Instruction) {
 	} else if _, is_asr_rri_op_code := instruction_.AsrRriOpCodes()[op_code]; is_asr_rri_op_code {
 		this.ExecuteAsrSRri(instruction_)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 }
@@ -3221,7 +3566,8 @@ func (this *Logic) ExecuteAddSRri(instruction_ *instruction.Instruction) {
 		result = this.alu.Xor(ra, imm)
 		carry = false
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -3286,7 +3632,8 @@ func (this *Logic) ExecuteAsrSRri(instruction_ *instruction.Instruction) {
 	} else if op_code == instruction.ROR {
 		result = this.alu.Ror(ra, imm)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -3311,40 +3658,34 @@ func (this *Logic) ExecuteAsrSRri(instruction_ *instruction.Instruction) {
 }
 
 func (this *Logic) ExecuteSRric(instruction_ *instruction.Instruction) {
-	err := errors.New("ExecuteSRric is not yet implemented")
-	panic(err)
-}
-
-func (this *Logic) ExecuteSRrici(instruction_ *instruction.Instruction) {
-	if _, found := instruction_.RriciOpCodes()[instruction_.OpCode()]; !found {
-		err := errors.New("op code is not a valid RRICI op code")
+	if _, found := instruction_.RricOpCodes()[instruction_.OpCode()]; !found {
+		err := errors.New("op code is not a valid RRIC op code")
 		panic(err)
-	} else if instruction_.Suffix() != instruction.S_RRICI && instruction_.Suffix() != instruction.U_RRICI {
-		err := errors.New("suffix is not S_RRICI nor U_RRICI")
+	} else if instruction_.Suffix() != instruction.S_RRIC && instruction_.Suffix() != instruction.U_RRIC {
+		err := errors.New("suffix is not S_RRIC nor U_RRIC")
 		panic(err)
 	}
 
 	op_code := instruction_.OpCode()
-	if _, is_add_rrici_op_code := instruction_.AddRriciOpCodes()[op_code]; is_add_rrici_op_code {
-		this.ExecuteAddSRrici(instruction_)
-	} else if _, is_and_rrici_op_code := instruction_.AndRriciOpCodes()[op_code]; is_and_rrici_op_code {
-		this.ExecuteAndSRrici(instruction_)
-	} else if _, is_asr_rrici_op_code := instruction_.AsrRriciOpCodes()[op_code]; is_asr_rrici_op_code {
-		this.ExecuteAsrSRrici(instruction_)
-	} else if _, is_sub_rrici_op_code := instruction_.SubRriciOpCodes()[op_code]; is_sub_rrici_op_code {
-		this.ExecuteSubSRrici(instruction_)
+	if _, is_add_rric_op_code := instruction_.AddRricOpCodes()[op_code]; is_add_rric_op_code {
+		this.ExecuteAddSRric(instruction_)
+	} else if _, is_asrc_rric_op_code := instruction_.AsrRricOpCodes()[op_code]; is_asrc_rric_op_code {
+		this.ExecuteAsrSRric(instruction_)
+	} else if _, is_sub_rric_op_code := instruction_.SubRricOpCodes()[op_code]; is_sub_rric_op_code {
+		this.ExecuteSubSRric(instruction_)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 }
 
-func (this *Logic) ExecuteAddSRrici(instruction_ *instruction.Instruction) {
-	if _, found := instruction_.AddRriciOpCodes()[instruction_.OpCode()]; !found {
-		err := errors.New("op code is not a valid add RRICI op code")
+func (this *Logic) ExecuteAddSRric(instruction_ *instruction.Instruction) {
+	if _, found := instruction_.AddRricOpCodes()[instruction_.OpCode()]; !found {
+		err := errors.New("op code is not a valid add RRIC op code")
 		panic(err)
-	} else if instruction_.Suffix() != instruction.S_RRICI && instruction_.Suffix() != instruction.U_RRICI {
-		err := errors.New("suffix is not S_RRICI nor U_RRICI")
+	} else if instruction_.Suffix() != instruction.S_RRIC && instruction_.Suffix() != instruction.U_RRIC {
+		err := errors.New("suffix is not S_RRIC nor U_RRIC")
 		panic(err)
 	}
 
@@ -3355,107 +3696,310 @@ func (this *Logic) ExecuteAddSRrici(instruction_ *instruction.Instruction) {
 
 	var result int64
 	var carry bool
-	var overflow bool
 
 	op_code := instruction_.OpCode()
 	if op_code == instruction.ADD {
-		result, carry, overflow = this.alu.Add(ra, imm)
+		result, carry, _ = this.alu.Add(ra, imm)
 	} else if op_code == instruction.ADDC {
 		carry_flag := thread.RegFile().ReadFlagReg(instruction.CARRY)
-		result, carry, overflow = this.alu.Addc(ra, imm, carry_flag)
-	} else {
-		err := errors.New("op code is not valid")
-		panic(err)
-	}
-
-	thread.RegFile().ClearConditions()
-	this.SetAddNzCc(instruction_, ra, result, carry, overflow)
-
-	var even int64
-	var odd int64
-	if instruction_.Suffix() == instruction.S_RRICI {
-		even, odd = this.alu.SignedExtension(result)
-	} else if instruction_.Suffix() == instruction.U_RRICI {
-		even, odd = this.alu.UnsignedExtension(result)
-	} else {
-		err := errors.New("suffix is not S_RRICI nor U_RRICI")
-		panic(err)
-	}
-
-	thread.RegFile().WritePairReg(instruction_.Dc(), even, odd)
-
-	if thread.RegFile().ReadConditionReg(instruction_.Condition()) {
-		thread.RegFile().WritePcReg(instruction_.Pc().Value())
-	} else {
-		thread.RegFile().IncrementPcReg()
-	}
-
-	this.SetFlags(instruction_, result, carry)
-}
-
-func (this *Logic) ExecuteAndSRrici(instruction_ *instruction.Instruction) {
-	if _, found := instruction_.AndRriciOpCodes()[instruction_.OpCode()]; !found {
-		err := errors.New("op code is not a valid and RRICI op code")
-		panic(err)
-	} else if instruction_.Suffix() != instruction.S_RRICI && instruction_.Suffix() != instruction.U_RRICI {
-		err := errors.New("suffix is not S_RRICI nor U_RRICI")
-		panic(err)
-	}
-
-	thread := this.scoreboard[instruction_]
-
-	ra := thread.RegFile().ReadSrcReg(instruction_.Ra(), word.SIGNED)
-	imm := instruction_.Imm().Value()
-
-	var result int64
-
-	op_code := instruction_.OpCode()
-	if op_code == instruction.AND {
+		result, carry, _ = this.alu.Addc(ra, imm, carry_flag)
+	} else if op_code == instruction.AND {
 		result = this.alu.And(ra, imm)
+		carry = false
 	} else if op_code == instruction.ANDN {
 		result = this.alu.Andn(ra, imm)
+		carry = false
 	} else if op_code == instruction.NAND {
 		result = this.alu.Nand(ra, imm)
+		carry = false
 	} else if op_code == instruction.NOR {
 		result = this.alu.Nor(ra, imm)
+		carry = false
 	} else if op_code == instruction.NXOR {
 		result = this.alu.Nxor(ra, imm)
+		carry = false
 	} else if op_code == instruction.OR {
 		result = this.alu.Or(ra, imm)
+		carry = false
 	} else if op_code == instruction.ORN {
 		result = this.alu.Orn(ra, imm)
+		carry = false
 	} else if op_code == instruction.XOR {
 		result = this.alu.Xor(ra, imm)
+		carry = false
 	} else if op_code == instruction.HASH {
 		result = this.alu.Hash(ra, imm)
+		carry = false
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
 	thread.RegFile().ClearConditions()
-	this.SetLogNzCc(instruction_, ra, result)
-
-	var even int64
-	var odd int64
-	if instruction_.Suffix() == instruction.S_RRICI {
-		even, odd = this.alu.SignedExtension(result)
-	} else if instruction_.Suffix() == instruction.U_RRICI {
-		even, odd = this.alu.UnsignedExtension(result)
-	} else {
-		err := errors.New("suffix is not S_RRICI nor U_RRICI")
-		panic(err)
-	}
-
-	thread.RegFile().WritePairReg(instruction_.Dc(), even, odd)
+	this.SetLogSetCc(instruction_, result)
 
+	// the odd register of the pair holds the low word, which the condition sets
 	if thread.RegFile().ReadConditionReg(instruction_.Condition()) {
-		thread.RegFile().WritePcReg(instruction_.Pc().Value())
+		thread.RegFile().WritePairReg(instruction_.Dc(), 0, 1)
 	} else {
-		thread.RegFile().IncrementPcReg()
+		thread.RegFile().WritePairReg(instruction_.Dc(), 0, 0)
 	}
 
-	this.SetFlags(instruction_, result, false)
+	thread.RegFile().IncrementPcReg()
+
+	this.SetFlags(instruction_, result, carry)
+}
+
+func (this *Logic) ExecuteAsrSRric(instruction_ *instruction.Instruction) {
+	if _, found := instruction_.AsrRricOpCodes()[instruction_.OpCode()]; !found {
+		err := errors.New("op code is not a valid asr RRIC op code")
+		panic(err)
+	} else if instruction_.Suffix() != instruction.S_RRIC && instruction_.Suffix() != instruction.U_RRIC {
+		err := errors.New("suffix is not S_RRIC nor U_RRIC")
+		panic(err)
+	}
+
+	thread := this.scoreboard[instruction_]
+
+	ra := thread.RegFile().ReadSrcReg(instruction_.Ra(), word.SIGNED)
+	imm := instruction_.Imm().Value()
+
+	var result int64
+
+	op_code := instruction_.OpCode()
+	if op_code == instruction.ASR {
+		result = this.alu.Asr(ra, imm)
+	} else if op_code == instruction.LSL {
+		result = this.alu.Lsl(ra, imm)
+	} else if op_code == instruction.LSL1 {
+		result = this.alu.Lsl1(ra, imm)
+	} else if op_code == instruction.LSL1X {
+		result = this.alu.Lsl1x(ra, imm)
+	} else if op_code == instruction.LSLX {
+		result = this.alu.Lslx(ra, imm)
+	} else if op_code == instruction.LSR {
+		result = this.alu.Lsr(ra, imm)
+	} else if op_code == instruction.LSR1 {
+		result = this.alu.Lsr1(ra, imm)
+	} else if op_code == instruction.LSR1X {
+		result = this.alu.Lsr1x(ra, imm)
+	} else if op_code == instruction.LSRX {
+		result = this.alu.Lsrx(ra, imm)
+	} else if op_code == instruction.ROL {
+		result = this.alu.Rol(ra, imm)
+	} else if op_code == instruction.ROR {
+		result = this.alu.Ror(ra, imm)
+	} else {
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
+		panic(err)
+	}
+
+	thread.RegFile().ClearConditions()
+	this.SetLogSetCc(instruction_, result)
+
+	// the odd register of the pair holds the low word, which the condition sets
+	if thread.RegFile().ReadConditionReg(instruction_.Condition()) {
+		thread.RegFile().WritePairReg(instruction_.Dc(), 0, 1)
+	} else {
+		thread.RegFile().WritePairReg(instruction_.Dc(), 0, 0)
+	}
+
+	thread.RegFile().IncrementPcReg()
+
+	this.SetFlags(instruction_, result, false)
+}
+
+func (this *Logic) ExecuteSubSRric(instruction_ *instruction.Instruction) {
+	if _, found := instruction_.SubRricOpCodes()[instruction_.OpCode()]; !found {
+		err := errors.New("op code is not a valid sub RRIC op code")
+		panic(err)
+	} else if instruction_.Suffix() != instruction.S_RRIC && instruction_.Suffix() != instruction.U_RRIC {
+		err := errors.New("suffix is not S_RRIC nor U_RRIC")
+		panic(err)
+	}
+
+	thread := this.scoreboard[instruction_]
+
+	ra := thread.RegFile().ReadSrcReg(instruction_.Ra(), word.SIGNED)
+	imm := instruction_.Imm().Value()
+
+	var result int64
+	var carry bool
+	var overflow bool
+
+	op_code := instruction_.OpCode()
+	if op_code == instruction.SUB {
+		result, carry, overflow = this.alu.Sub(ra, imm)
+	} else if op_code == instruction.SUBC {
+		carry_flag := thread.RegFile().ReadFlagReg(instruction.CARRY)
+		result, carry, overflow = this.alu.Subc(ra, imm, carry_flag)
+	} else {
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
+		panic(err)
+	}
+
+	thread.RegFile().ClearConditions()
+	this.SetExtSubSetCc(instruction_, ra, imm, result, carry, overflow)
+
+	// the odd register of the pair holds the low word, which the condition sets
+	if thread.RegFile().ReadConditionReg(instruction_.Condition()) {
+		thread.RegFile().WritePairReg(instruction_.Dc(), 0, 1)
+	} else {
+		thread.RegFile().WritePairReg(instruction_.Dc(), 0, 0)
+	}
+
+	thread.RegFile().IncrementPcReg()
+
+	this.SetFlags(instruction_, result, carry)
+}
+
+func (this *Logic) ExecuteSRrici(instruction_ *instruction.Instruction) {
+	if _, found := instruction_.RriciOpCodes()[instruction_.OpCode()]; !found {
+		err := errors.New("op code is not a valid RRICI op code")
+		panic(err)
+	} else if instruction_.Suffix() != instruction.S_RRICI && instruction_.Suffix() != instruction.U_RRICI {
+		err := errors.New("suffix is not S_RRICI nor U_RRICI")
+		panic(err)
+	}
+
+	op_code := instruction_.OpCode()
+	if _, is_add_rrici_op_code := instruction_.AddRriciOpCodes()[op_code]; is_add_rrici_op_code {
+		this.ExecuteAddSRrici(instruction_)
+	} else if _, is_and_rrici_op_code := instruction_.AndRriciOpCodes()[op_code]; is_and_rrici_op_code {
+		this.ExecuteAndSRrici(instruction_)
+	} else if _, is_asr_rrici_op_code := instruction_.AsrRriciOpCodes()[op_code]; is_asr_rrici_op_code {
+		this.ExecuteAsrSRrici(instruction_)
+	} else if _, is_sub_rrici_op_code := instruction_.SubRriciOpCodes()[op_code]; is_sub_rrici_op_code {
+		this.ExecuteSubSRrici(instruction_)
+	} else {
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
+		panic(err)
+	}
+}
+
+func (this *Logic) ExecuteAddSRrici(instruction_ *instruction.Instruction) {
+	if _, found := instruction_.AddRriciOpCodes()[instruction_.OpCode()]; !found {
+		err := errors.New("op code is not a valid add RRICI op code")
+		panic(err)
+	} else if instruction_.Suffix() != instruction.S_RRICI && instruction_.Suffix() != instruction.U_RRICI {
+		err := errors.New("suffix is not S_RRICI nor U_RRICI")
+		panic(err)
+	}
+
+	thread := this.scoreboard[instruction_]
+
+	ra := thread.RegFile().ReadSrcReg(instruction_.Ra(), word.SIGNED)
+	imm := instruction_.Imm().Value()
+
+	var result int64
+	var carry bool
+	var overflow bool
+
+	op_code := instruction_.OpCode()
+	if op_code == instruction.ADD {
+		result, carry, overflow = this.alu.Add(ra, imm)
+	} else if op_code == instruction.ADDC {
+		carry_flag := thread.RegFile().ReadFlagReg(instruction.CARRY)
+		result, carry, overflow = this.alu.Addc(ra, imm, carry_flag)
+	} else {
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
+		panic(err)
+	}
+
+	thread.RegFile().ClearConditions()
+	this.SetAddNzCc(instruction_, ra, result, carry, overflow)
+
+	var even int64
+	var odd int64
+	if instruction_.Suffix() == instruction.S_RRICI {
+		even, odd = this.alu.SignedExtension(result)
+	} else if instruction_.Suffix() == instruction.U_RRICI {
+		even, odd = this.alu.UnsignedExtension(result)
+	} else {
+		err := errors.New("suffix is not S_RRICI nor U_RRICI")
+		panic(err)
+	}
+
+	thread.RegFile().WritePairReg(instruction_.Dc(), even, odd)
+
+	if thread.RegFile().ReadConditionReg(instruction_.Condition()) {
+		thread.RegFile().WritePcReg(instruction_.Pc().Value())
+	} else {
+		thread.RegFile().IncrementPcReg()
+	}
+
+	this.SetFlags(instruction_, result, carry)
+}
+
+func (this *Logic) ExecuteAndSRrici(instruction_ *instruction.Instruction) {
+	if _, found := instruction_.AndRriciOpCodes()[instruction_.OpCode()]; !found {
+		err := errors.New("op code is not a valid and RRICI op code")
+		panic(err)
+	} else if instruction_.Suffix() != instruction.S_RRICI && instruction_.Suffix() != instruction.U_RRICI {
+		err := errors.New("suffix is not S_RRICI nor U_RRICI")
+		panic(err)
+	}
+
+	thread := this.scoreboard[instruction_]
+
+	ra := thread.RegFile().ReadSrcReg(instruction_.Ra(), word.SIGNED)
+	imm := instruction_.Imm().Value()
+
+	var result int64
+
+	op_code := instruction_.OpCode()
+	if op_code == instruction.AND {
+		result = this.alu.And(ra, imm)
+	} else if op_code == instruction.ANDN {
+		result = this.alu.Andn(ra, imm)
+	} else if op_code == instruction.NAND {
+		result = this.alu.Nand(ra, imm)
+	} else if op_code == instruction.NOR {
+		result = this.alu.Nor(ra, imm)
+	} else if op_code == instruction.NXOR {
+		result = this.alu.Nxor(ra, imm)
+	} else if op_code == instruction.OR {
+		result = this.alu.Or(ra, imm)
+	} else if op_code == instruction.ORN {
+		result = this.alu.Orn(ra, imm)
+	} else if op_code == instruction.XOR {
+		result = this.alu.Xor(ra, imm)
+	} else if op_code == instruction.HASH {
+		result = this.alu.Hash(ra, imm)
+	} else {
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
+		panic(err)
+	}
+
+	thread.RegFile().ClearConditions()
+	this.SetLogNzCc(instruction_, ra, result)
+
+	var even int64
+	var odd int64
+	if instruction_.Suffix() == instruction.S_RRICI {
+		even, odd = this.alu.SignedExtension(result)
+	} else if instruction_.Suffix() == instruction.U_RRICI {
+		even, odd = this.alu.UnsignedExtension(result)
+	} else {
+		err := errors.New("suffix is not S_RRICI nor U_RRICI")
+		panic(err)
+	}
+
+	thread.RegFile().WritePairReg(instruction_.Dc(), even, odd)
+
+	if thread.RegFile().ReadConditionReg(instruction_.Condition()) {
+		thread.RegFile().WritePcReg(instruction_.Pc().Value())
+	} else {
+		thread.RegFile().IncrementPcReg()
+	}
+
+	this.SetFlags(instruction_, result, false)
 }
 
 func (this *Logic) ExecuteAsrSRrici(instruction_ *instruction.Instruction) {
@@ -3498,7 +4042,8 @@ func (this *Logic) ExecuteAsrSRrici(instruction_ *instruction.Instruction) {
 	} else if op_code == instruction.ROR {
 		result = this.alu.Ror(ra, imm)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -3512,11 +4057,1087 @@ func (this *Logic) ExecuteAsrSRrici(instruction_ *instruction.Instruction) {
 	} else if instruction_.Suffix() == instruction.U_RRICI {
 		even, odd = this.alu.UnsignedExtension(result)
 	} else {
-		err := errors.New("suffix is not S_RRICI nor U_RRICI")
+		err := errors.New("suffix is not S_RRICI nor U_RRICI")
+		panic(err)
+	}
+
+	thread.RegFile().WritePairReg(instruction_.Dc(), even, odd)
+
+	if thread.RegFile().ReadConditionReg(instruction_.Condition()) {
+		thread.RegFile().WritePcReg(instruction_.Pc().Value())
+	} else {
+		thread.RegFile().IncrementPcReg()
+	}
+
+	this.SetFlags(instruction_, result, false)
+}
+
+func (this *Logic) ExecuteSubSRrici(instruction_ *instruction.Instruction) {
+	if _, found := instruction_.SubRriciOpCodes()[instruction_.OpCode()]; !found {
+		err := errors.New("op code is not a valid sub RRICI op code")
+		panic(err)
+	} else if instruction_.Suffix() != instruction.S_RRICI && instruction_.Suffix() != instruction.U_RRICI {
+		err := errors.New("suffix is not S_RRICI nor U_RRICI")
+		panic(err)
+	}
+
+	thread := this.scoreboard[instruction_]
+
+	ra := thread.RegFile().ReadSrcReg(instruction_.Ra(), word.SIGNED)
+	imm := instruction_.Imm().Value()
+
+	var result int64
+	var carry bool
+	var overflow bool
+
+	op_code := instruction_.OpCode()
+	if op_code == instruction.SUB {
+		result, carry, overflow = this.alu.Sub(ra, imm)
+	} else if op_code == instruction.SUBC {
+		carry_flag := thread.RegFile().ReadFlagReg(instruction.CARRY)
+		result, carry, overflow = this.alu.Subc(ra, imm, carry_flag)
+	} else {
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
+		panic(err)
+	}
+
+	thread.RegFile().ClearConditions()
+	this.SetSubNzCc(instruction_, ra, imm, result, carry, overflow)
+
+	var even int64
+	var odd int64
+	if instruction_.Suffix() == instruction.S_RRICI {
+		even, odd = this.alu.SignedExtension(result)
+	} else if instruction_.Suffix() == instruction.U_RRICI {
+		even, odd = this.alu.UnsignedExtension(result)
+	} else {
+		err := errors.New("suffix is not S_RRI nor U_RRI")
+		panic(err)
+	}
+
+	thread.RegFile().WritePairReg(instruction_.Dc(), even, odd)
+
+	if thread.RegFile().ReadConditionReg(instruction_.Condition()) {
+		thread.RegFile().WritePcReg(instruction_.Pc().Value())
+	} else {
+		thread.RegFile().IncrementPcReg()
+	}
+
+	this.SetFlags(instruction_, result, carry)
+}
+
+func (this *Logic) ExecuteSRrif(instruction_ *instruction.Instruction) {
+	if _, found := instruction_.RrifOpCodes()[instruction_.OpCode()]; !found {
+		err := errors.New("op code is not a valid RRIF op code")
+		panic(err)
+	} else if instruction_.Suffix() != instruction.S_RRIF && instruction_.Suffix() != instruction.U_RRIF {
+		err := errors.New("suffix is not S_RRIF nor U_RRIF")
+		panic(err)
+	}
+
+	thread := this.scoreboard[instruction_]
+
+	ra := thread.RegFile().ReadSrcReg(instruction_.Ra(), word.SIGNED)
+	imm := instruction_.Imm().Value()
+
+	var result int64
+	var carry bool
+
+	op_code := instruction_.OpCode()
+	if op_code == instruction.ADD {
+		result, carry, _ = this.alu.Add(ra, imm)
+	} else if op_code == instruction.ADDC {
+		carry_flag := thread.RegFile().ReadFlagReg(instruction.CARRY)
+		result, carry, _ = this.alu.Addc(ra, imm, carry_flag)
+	} else if op_code == instruction.AND {
+		result = this.alu.And(ra, imm)
+		carry = false
+	} else if op_code == instruction.ANDN {
+		result = this.alu.Andn(ra, imm)
+		carry = false
+	} else if op_code == instruction.NAND {
+		result = this.alu.Nand(ra, imm)
+		carry = false
+	} else if op_code == instruction.NOR {
+		result = this.alu.Nor(ra, imm)
+		carry = false
+	} else if op_code == instruction.NXOR {
+		result = this.alu.Nxor(ra, imm)
+		carry = false
+	} else if op_code == instruction.OR {
+		result = this.alu.Or(ra, imm)
+		carry = false
+	} else if op_code == instruction.ORN {
+		result = this.alu.Orn(ra, imm)
+		carry = false
+	} else if op_code == instruction.SUB {
+		result, carry, _ = this.alu.Sub(ra, imm)
+	} else if op_code == instruction.SUBC {
+		carry_flag := thread.RegFile().ReadFlagReg(instruction.CARRY)
+		result, carry, _ = this.alu.Subc(ra, imm, carry_flag)
+	} else if op_code == instruction.XOR {
+		result = this.alu.Xor(ra, imm)
+		carry = false
+	} else if op_code == instruction.HASH {
+		result = this.alu.Hash(ra, imm)
+		carry = false
+	} else {
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
+		panic(err)
+	}
+
+	thread.RegFile().ClearConditions()
+
+	var even int64
+	var odd int64
+	if instruction_.Suffix() == instruction.S_RRIF {
+		even, odd = this.alu.SignedExtension(result)
+	} else if instruction_.Suffix() == instruction.U_RRIF {
+		even, odd = this.alu.UnsignedExtension(result)
+	} else {
+		err := errors.New("suffix is not S_RRIF nor U_RRIF")
+		panic(err)
+	}
+
+	thread.RegFile().WritePairReg(instruction_.Dc(), even, odd)
+
+	thread.RegFile().IncrementPcReg()
+
+	this.SetFlags(instruction_, result, carry)
+}
+
+func (this *Logic) ExecuteSRrr(instruction_ *instruction.Instruction) {
+	if _, found := instruction_.RrrOpCodes()[instruction_.OpCode()]; !found {
+		err := errors.New("op code is not a valid RRR op code")
+		panic(err)
+	} else if instruction_.Suffix() != instruction.S_RRR && instruction_.Suffix() != instruction.U_RRR {
+		err := errors.New("suffix is not S_RRR nor U_RRR")
+		panic(err)
+	}
+
+	thread := this.scoreboard[instruction_]
+
+	ra := thread.RegFile().ReadSrcReg(instruction_.Ra(), word.SIGNED)
+	rb := thread.RegFile().ReadSrcReg(instruction_.Rb(), word.SIGNED)
+
+	var result int64
+	var carry bool
+
+	op_code := instruction_.OpCode()
+	if op_code == instruction.ADD {
+		result, carry, _ = this.alu.Add(ra, rb)
+	} else if op_code == instruction.ADDC {
+		carry_flag := thread.RegFile().ReadFlagReg(instruction.CARRY)
+		result, carry, _ = this.alu.Addc(ra, rb, carry_flag)
+	} else if op_code == instruction.AND {
+		result = this.alu.And(ra, rb)
+		carry = false
+	} else if op_code == instruction.ANDN {
+		result = this.alu.Andn(ra, rb)
+		carry = false
+	} else if op_code == instruction.ASR {
+		result = this.alu.Asr(ra, rb)
+		carry = false
+	} else if op_code == instruction.CMPB4 {
+		result = this.alu.Cmpb4(ra, rb)
+		carry = false
+	} else if op_code == instruction.LSL {
+		result = this.alu.Lsl(ra, rb)
+		carry = false
+	} else if op_code == instruction.LSL1 {
+		result = this.alu.Lsl1(ra, rb)
+		carry = false
+	} else if op_code == instruction.LSL1X {
+		result = this.alu.Lsl1x(ra, rb)
+		carry = false
+	} else if op_code == instruction.LSLX {
+		result = this.alu.Lslx(ra, rb)
+		carry = false
+	} else if op_code == instruction.LSR {
+		result = this.alu.Lsr(ra, rb)
+		carry = false
+	} else if op_code == instruction.LSR1 {
+		result = this.alu.Lsr1(ra, rb)
+		carry = false
+	} else if op_code == instruction.LSR1X {
+		result = this.alu.Lsr1x(ra, rb)
+		carry = false
+	} else if op_code == instruction.LSRX {
+		result = this.alu.Lsrx(ra, rb)
+		carry = false
+	} else if op_code == instruction.ROL {
+		result = this.alu.Rol(ra, rb)
+		carry = false
+	} else if op_code == instruction.ROR {
+		result = this.alu.Ror(ra, rb)
+		carry = false
+	} else if op_code == instruction.MUL_SH_SH {
+		result = this.alu.MulShSh(ra, rb)
+		carry = false
+	} else if op_code == instruction.MUL_SH_SL {
+		result = this.alu.MulShSl(ra, rb)
+		carry = false
+	} else if op_code == instruction.MUL_SH_UH {
+		result = this.alu.MulShUh(ra, rb)
+		carry = false
+	} else if op_code == instruction.MUL_SH_UL {
+		result = this.alu.MulShUl(ra, rb)
+		carry = false
+	} else if op_code == instruction.MUL_SL_SH {
+		result = this.alu.MulSlSh(ra, rb)
+		carry = false
+	} else if op_code == instruction.MUL_SL_SL {
+		result = this.alu.MulSlSl(ra, rb)
+		carry = false
+	} else if op_code == instruction.MUL_SL_UH {
+		result = this.alu.MulSlUh(ra, rb)
+		carry = false
+	} else if op_code == instruction.MUL_SL_UL {
+		result = this.alu.MulSlUl(ra, rb)
+		carry = false
+	} else if op_code == instruction.MUL_UH_UH {
+		result = this.alu.MulUhUh(ra, rb)
+		carry = false
+	} else if op_code == instruction.MUL_UH_UL {
+		result = this.alu.MulUhUl(ra, rb)
+		carry = false
+	} else if op_code == instruction.MUL_UL_UH {
+		result = this.alu.MulUlUh(ra, rb)
+		carry = false
+	} else if op_code == instruction.MUL_UL_UL {
+		result = this.alu.MulUlUl(ra, rb)
+		carry = false
+	} else if op_code == instruction.NAND {
+		result = this.alu.Nand(ra, rb)
+		carry = false
+	} else if op_code == instruction.NOR {
+		result = this.alu.Nor(ra, rb)
+		carry = false
+	} else if op_code == instruction.NXOR {
+		result = this.alu.Nxor(ra, rb)
+		carry = false
+	} else if op_code == instruction.OR {
+		result = this.alu.Or(ra, rb)
+		carry = false
+	} else if op_code == instruction.ORN {
+		result = this.alu.Orn(ra, rb)
+		carry = false
+	} else if op_code == instruction.RSUB {
+		result, carry, _ = this.alu.Sub(rb, ra)
+	} else if op_code == instruction.RSUBC {
+		carry_flag := thread.RegFile().ReadFlagReg(instruction.CARRY)
+		result, carry, _ = this.alu.Subc(rb, ra, carry_flag)
+	} else if op_code == instruction.SUB {
+		result, carry, _ = this.alu.Sub(ra, rb)
+	} else if op_code == instruction.SUBC {
+		carry_flag := thread.RegFile().ReadFlagReg(instruction.CARRY)
+		result, carry, _ = this.alu.Subc(ra, rb, carry_flag)
+	} else if op_code == instruction.XOR {
+		result = this.alu.Xor(ra, rb)
+		carry = false
+	} else if op_code == instruction.HASH {
+		result = this.alu.Hash(ra, rb)
+		carry = false
+	} else {
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
+		panic(err)
+	}
+
+	thread.RegFile().ClearConditions()
+
+	var even int64
+	var odd int64
+	if instruction_.Suffix() == instruction.S_RRR {
+		even, odd = this.alu.SignedExtension(result)
+	} else if instruction_.Suffix() == instruction.U_RRR {
+		even, odd = this.alu.UnsignedExtension(result)
+	} else {
+		err := errors.New("suffix is not S_RRR nor U_RRR")
+		panic(err)
+	}
+
+	thread.RegFile().WritePairReg(instruction_.Dc(), even, odd)
+
+	thread.RegFile().IncrementPcReg()
+
+	this.SetFlags(instruction_, result, carry)
+}
+
+func (this *Logic) ExecuteSRrrc(instruction_ *instruction.Instruction) {
+	if _, found := instruction_.RrrcOpCodes()[instruction_.OpCode()]; !found {
+		err := errors.New("op code is not a valid RRRC op code")
+		panic(err)
+	} else if instruction_.Suffix() != instruction.S_RRRC && instruction_.Suffix() != instruction.U_RRRC {
+		err := errors.New("suffix is not S_RRRC nor U_RRRC")
+		panic(err)
+	}
+
+	op_code := instruction_.OpCode()
+	if _, is_add_rrrc_op_code := instruction_.AddRrrcOpCodes()[op_code]; is_add_rrrc_op_code {
+		this.ExecuteAddSRrrc(instruction_)
+	} else if _, is_rsub_rrrc_op_code := instruction_.RsubRrrcOpCodes()[op_code]; is_rsub_rrrc_op_code {
+		this.ExecuteRsubSRrrc(instruction_)
+	} else if _, is_sub_rrrc_op_code := instruction_.SubRrrcOpCodes()[op_code]; is_sub_rrrc_op_code {
+		this.ExecuteSubSRrrc(instruction_)
+	} else {
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
+		panic(err)
+	}
+}
+
+func (this *Logic) ExecuteAddSRrrc(instruction_ *instruction.Instruction) {
+	if _, found := instruction_.AddRrrcOpCodes()[instruction_.OpCode()]; !found {
+		err := errors.New("op code is not a valid add RRRC op code")
+		panic(err)
+	} else if instruction_.Suffix() != instruction.S_RRRC && instruction_.Suffix() != instruction.U_RRRC {
+		err := errors.New("suffix is not S_RRRC nor U_RRRC")
+		panic(err)
+	}
+
+	thread := this.scoreboard[instruction_]
+
+	ra := thread.RegFile().ReadSrcReg(instruction_.Ra(), word.SIGNED)
+	rb := thread.RegFile().ReadSrcReg(instruction_.Rb(), word.SIGNED)
+
+	var result int64
+	var carry bool
+
+	op_code := instruction_.OpCode()
+	if op_code == instruction.ADD {
+		result, carry, _ = this.alu.Add(ra, rb)
+	} else if op_code == instruction.ADDC {
+		carry_flag := thread.RegFile().ReadFlagReg(instruction.CARRY)
+		result, carry, _ = this.alu.Addc(ra, rb, carry_flag)
+	} else if op_code == instruction.AND {
+		result = this.alu.And(ra, rb)
+		carry = false
+	} else if op_code == instruction.ANDN {
+		result = this.alu.Andn(ra, rb)
+		carry = false
+	} else if op_code == instruction.ASR {
+		result = this.alu.Asr(ra, rb)
+		carry = false
+	} else if op_code == instruction.CMPB4 {
+		result = this.alu.Cmpb4(ra, rb)
+		carry = false
+	} else if op_code == instruction.LSL {
+		result = this.alu.Lsl(ra, rb)
+		carry = false
+	} else if op_code == instruction.LSL1 {
+		result = this.alu.Lsl1(ra, rb)
+		carry = false
+	} else if op_code == instruction.LSL1X {
+		result = this.alu.Lsl1x(ra, rb)
+		carry = false
+	} else if op_code == instruction.LSLX {
+		result = this.alu.Lslx(ra, rb)
+		carry = false
+	} else if op_code == instruction.LSR {
+		result = this.alu.Lsr(ra, rb)
+		carry = false
+	} else if op_code == instruction.LSR1 {
+		result = this.alu.Lsr1(ra, rb)
+		carry = false
+	} else if op_code == instruction.LSR1X {
+		result = this.alu.Lsr1x(ra, rb)
+		carry = false
+	} else if op_code == instruction.LSRX {
+		result = this.alu.Lsrx(ra, rb)
+		carry = false
+	} else if op_code == instruction.ROL {
+		result = this.alu.Rol(ra, rb)
+		carry = false
+	} else if op_code == instruction.ROR {
+		result = this.alu.Ror(ra, rb)
+		carry = false
+	} else if op_code == instruction.MUL_SH_SH {
+		result = this.alu.MulShSh(ra, rb)
+		carry = false
+	} else if op_code == instruction.MUL_SH_SL {
+		result = this.alu.MulShSl(ra, rb)
+		carry = false
+	} else if op_code == instruction.MUL_SH_UH {
+		result = this.alu.MulShUh(ra, rb)
+		carry = false
+	} else if op_code == instruction.MUL_SH_UL {
+		result = this.alu.MulShUl(ra, rb)
+		carry = false
+	} else if op_code == instruction.MUL_SL_SH {
+		result = this.alu.MulSlSh(ra, rb)
+		carry = false
+	} else if op_code == instruction.MUL_SL_SL {
+		result = this.alu.MulSlSl(ra, rb)
+		carry = false
+	} else if op_code == instruction.MUL_SL_UH {
+		result = this.alu.MulSlUh(ra, rb)
+		carry = false
+	} else if op_code == instruction.MUL_SL_UL {
+		result = this.alu.MulSlUl(ra, rb)
+		carry = false
+	} else if op_code == instruction.MUL_UH_UH {
+		result = this.alu.MulUhUh(ra, rb)
+		carry = false
+	} else if op_code == instruction.MUL_UH_UL {
+		result = this.alu.MulUhUl(ra, rb)
+		carry = false
+	} else if op_code == instruction.MUL_UL_UH {
+		result = this.alu.MulUlUh(ra, rb)
+		carry = false
+	} else if op_code == instruction.MUL_UL_UL {
+		result = this.alu.MulUlUl(ra, rb)
+		carry = false
+	} else if op_code == instruction.NAND {
+		result = this.alu.Nand(ra, rb)
+		carry = false
+	} else if op_code == instruction.NOR {
+		result = this.alu.Nor(ra, rb)
+		carry = false
+	} else if op_code == instruction.NXOR {
+		result = this.alu.Nxor(ra, rb)
+		carry = false
+	} else if op_code == instruction.OR {
+		result = this.alu.Or(ra, rb)
+		carry = false
+	} else if op_code == instruction.ORN {
+		result = this.alu.Orn(ra, rb)
+		carry = false
+	} else if op_code == instruction.XOR {
+		result = this.alu.Xor(ra, rb)
+		carry = false
+	} else if op_code == instruction.HASH {
+		result = this.alu.Hash(ra, rb)
+		carry = false
+	} else if op_code == instruction.CALL {
+		result, carry, _ = this.alu.Add(ra, rb)
+	} else {
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
+		panic(err)
+	}
+
+	thread.RegFile().ClearConditions()
+	this.SetLogSetCc(instruction_, result)
+
+	// the odd register of the pair holds the low word, which the condition sets
+	if thread.RegFile().ReadConditionReg(instruction_.Condition()) {
+		thread.RegFile().WritePairReg(instruction_.Dc(), 0, 1)
+	} else {
+		thread.RegFile().WritePairReg(instruction_.Dc(), 0, 0)
+	}
+
+	thread.RegFile().IncrementPcReg()
+
+	this.SetFlags(instruction_, result, carry)
+}
+
+func (this *Logic) ExecuteRsubSRrrc(instruction_ *instruction.Instruction) {
+	if _, found := instruction_.RsubRrrcOpCodes()[instruction_.OpCode()]; !found {
+		err := errors.New("op code is not a valid rsub RRRC op code")
+		panic(err)
+	} else if instruction_.Suffix() != instruction.S_RRRC && instruction_.Suffix() != instruction.U_RRRC {
+		err := errors.New("suffix is not S_RRRC nor U_RRRC")
+		panic(err)
+	}
+
+	thread := this.scoreboard[instruction_]
+
+	ra := thread.RegFile().ReadSrcReg(instruction_.Ra(), word.SIGNED)
+	rb := thread.RegFile().ReadSrcReg(instruction_.Rb(), word.SIGNED)
+
+	var result int64
+	var carry bool
+
+	op_code := instruction_.OpCode()
+	if op_code == instruction.RSUB {
+		result, carry, _ = this.alu.Sub(rb, ra)
+	} else if op_code == instruction.RSUBC {
+		carry_flag := thread.RegFile().ReadFlagReg(instruction.CARRY)
+		result, carry, _ = this.alu.Subc(rb, ra, carry_flag)
+	} else {
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
+		panic(err)
+	}
+
+	thread.RegFile().ClearConditions()
+	this.SetSubSetCc(instruction_, ra, rb, result)
+
+	// the odd register of the pair holds the low word, which the condition sets
+	if thread.RegFile().ReadConditionReg(instruction_.Condition()) {
+		thread.RegFile().WritePairReg(instruction_.Dc(), 0, 1)
+	} else {
+		thread.RegFile().WritePairReg(instruction_.Dc(), 0, 0)
+	}
+
+	thread.RegFile().IncrementPcReg()
+
+	this.SetFlags(instruction_, result, carry)
+}
+
+func (this *Logic) ExecuteSubSRrrc(instruction_ *instruction.Instruction) {
+	if _, found := instruction_.SubRrrcOpCodes()[instruction_.OpCode()]; !found {
+		err := errors.New("op code is not a valid sub RRRC op code")
+		panic(err)
+	} else if instruction_.Suffix() != instruction.S_RRRC && instruction_.Suffix() != instruction.U_RRRC {
+		err := errors.New("suffix is not S_RRRC nor U_RRRC")
+		panic(err)
+	}
+
+	thread := this.scoreboard[instruction_]
+
+	ra := thread.RegFile().ReadSrcReg(instruction_.Ra(), word.SIGNED)
+	rb := thread.RegFile().ReadSrcReg(instruction_.Rb(), word.SIGNED)
+
+	var result int64
+	var carry bool
+	var overflow bool
+
+	op_code := instruction_.OpCode()
+	if op_code == instruction.SUB {
+		result, carry, overflow = this.alu.Sub(ra, rb)
+	} else if op_code == instruction.SUBC {
+		carry_flag := thread.RegFile().ReadFlagReg(instruction.CARRY)
+		result, carry, overflow = this.alu.Subc(ra, rb, carry_flag)
+	} else {
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
+		panic(err)
+	}
+
+	thread.RegFile().ClearConditions()
+	this.SetExtSubSetCc(instruction_, ra, rb, result, carry, overflow)
+
+	// the odd register of the pair holds the low word, which the condition sets
+	if thread.RegFile().ReadConditionReg(instruction_.Condition()) {
+		thread.RegFile().WritePairReg(instruction_.Dc(), 0, 1)
+	} else {
+		thread.RegFile().WritePairReg(instruction_.Dc(), 0, 0)
+	}
+
+	thread.RegFile().IncrementPcReg()
+
+	this.SetFlags(instruction_, result, carry)
+}
+
+func (this *Logic) ExecuteSRrrci(instruction_ *instruction.Instruction) {
+	if _, found := instruction_.RrrciOpCodes()[instruction_.OpCode()]; !found {
+		err := errors.New("op code is not a valid RRRCI op code")
+		panic(err)
+	} else if instruction_.Suffix() != instruction.S_RRRCI && instruction_.Suffix() != instruction.U_RRRCI {
+		err := errors.New("suffix is not S_RRRCI nor U_RRRCI")
+		panic(err)
+	}
+
+	op_code := instruction_.OpCode()
+	if _, is_add_rrrci_op_code := instruction_.AddRrrciOpCodes()[op_code]; is_add_rrrci_op_code {
+		this.ExecuteAddSRrrci(instruction_)
+	} else if _, is_and_rrrci_op_code := instruction_.AndRrrciOpCodes()[op_code]; is_and_rrrci_op_code {
+		this.ExecuteAndSRrrci(instruction_)
+	} else if _, is_asr_rrrci_op_code := instruction_.AsrRrrciOpCodes()[op_code]; is_asr_rrrci_op_code {
+		this.ExecuteAsrSRrrci(instruction_)
+	} else if _, is_mul_rrrci_op_code := instruction_.MulRrrciOpCodes()[op_code]; is_mul_rrrci_op_code {
+		this.ExecuteMulSRrrci(instruction_)
+	} else if _, is_rsub_rrrci_op_code := instruction_.RsubRrrciOpCodes()[op_code]; is_rsub_rrrci_op_code {
+		this.ExecuteRsubSRrrci(instruction_)
+	} else {
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
+		panic(err)
+	}
+}
+
+func (this *Logic) ExecuteAddSRrrci(instruction_ *instruction.Instruction) {
+	if _, found := instruction_.AddRrrciOpCodes()[instruction_.OpCode()]; !found {
+		err := errors.New("op code is not a valid add RRRCI op code")
+		panic(err)
+	} else if instruction_.Suffix() != instruction.S_RRRCI && instruction_.Suffix() != instruction.U_RRRCI {
+		err := errors.New("suffix is not S_RRRCI nor U_RRRCI")
+		panic(err)
+	}
+
+	thread := this.scoreboard[instruction_]
+
+	ra := thread.RegFile().ReadSrcReg(instruction_.Ra(), word.SIGNED)
+	rb := thread.RegFile().ReadSrcReg(instruction_.Rb(), word.SIGNED)
+
+	var result int64
+	var carry bool
+	var overflow bool
+
+	op_code := instruction_.OpCode()
+	if op_code == instruction.ADD {
+		result, carry, overflow = this.alu.Add(ra, rb)
+	} else if op_code == instruction.ADDC {
+		carry_flag := thread.RegFile().ReadFlagReg(instruction.CARRY)
+		result, carry, overflow = this.alu.Addc(ra, rb, carry_flag)
+	} else {
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
+		panic(err)
+	}
+
+	thread.RegFile().ClearConditions()
+	this.SetAddNzCc(instruction_, ra, result, carry, overflow)
+
+	var even int64
+	var odd int64
+	if instruction_.Suffix() == instruction.S_RRRCI {
+		even, odd = this.alu.SignedExtension(result)
+	} else if instruction_.Suffix() == instruction.U_RRRCI {
+		even, odd = this.alu.UnsignedExtension(result)
+	} else {
+		err := errors.New("suffix is not S_RRRCI nor U_RRRCI")
+		panic(err)
+	}
+
+	thread.RegFile().WritePairReg(instruction_.Dc(), even, odd)
+
+	if thread.RegFile().ReadConditionReg(instruction_.Condition()) {
+		thread.RegFile().WritePcReg(instruction_.Pc().Value())
+	} else {
+		thread.RegFile().IncrementPcReg()
+	}
+
+	this.SetFlags(instruction_, result, carry)
+}
+
+func (this *Logic) ExecuteAndSRrrci(instruction_ *instruction.Instruction) {
+	if _, found := instruction_.AndRrrciOpCodes()[instruction_.OpCode()]; !found {
+		err := errors.New("op code is not a valid and RRRCI op code")
+		panic(err)
+	} else if instruction_.Suffix() != instruction.S_RRRCI && instruction_.Suffix() != instruction.U_RRRCI {
+		err := errors.New("suffix is not S_RRRCI nor U_RRRCI")
+		panic(err)
+	}
+
+	thread := this.scoreboard[instruction_]
+
+	ra := thread.RegFile().ReadSrcReg(instruction_.Ra(), word.SIGNED)
+	rb := thread.RegFile().ReadSrcReg(instruction_.Rb(), word.SIGNED)
+
+	var result int64
+
+	op_code := instruction_.OpCode()
+	if op_code == instruction.AND {
+		result = this.alu.And(ra, rb)
+	} else if op_code == instruction.ANDN {
+		result = this.alu.Andn(ra, rb)
+	} else if op_code == instruction.NAND {
+		result = this.alu.Nand(ra, rb)
+	} else if op_code == instruction.NOR {
+		result = this.alu.Nor(ra, rb)
+	} else if op_code == instruction.NXOR {
+		result = this.alu.Nxor(ra, rb)
+	} else if op_code == instruction.OR {
+		result = this.alu.Or(ra, rb)
+	} else if op_code == instruction.ORN {
+		result = this.alu.Orn(ra, rb)
+	} else if op_code == instruction.XOR {
+		result = this.alu.Xor(ra, rb)
+	} else if op_code == instruction.HASH {
+		result = this.alu.Hash(ra, rb)
+	} else {
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
+		panic(err)
+	}
+
+	thread.RegFile().ClearConditions()
+	this.SetLogNzCc(instruction_, ra, result)
+
+	var even int64
+	var odd int64
+	if instruction_.Suffix() == instruction.S_RRRCI {
+		even, odd = this.alu.SignedExtension(result)
+	} else if instruction_.Suffix() == instruction.U_RRRCI {
+		even, odd = this.alu.UnsignedExtension(result)
+	} else {
+		err := errors.New("suffix is not S_RRRCI nor U_RRRCI")
+		panic(err)
+	}
+
+	thread.RegFile().WritePairReg(instruction_.Dc(), even, odd)
+
+	if thread.RegFile().ReadConditionReg(instruction_.Condition()) {
+		thread.RegFile().WritePcReg(instruction_.Pc().Value())
+	} else {
+		thread.RegFile().IncrementPcReg()
+	}
+
+	this.SetFlags(instruction_, result, false)
+}
+
+func (this *Logic) ExecuteAsrSRrrci(instruction_ *instruction.Instruction) {
+	if _, found := instruction_.AsrRrrciOpCodes()[instruction_.OpCode()]; !found {
+		err := errors.New("op code is not a valid asr RRRCI op code")
+		panic(err)
+	} else if instruction_.Suffix() != instruction.S_RRRCI && instruction_.Suffix() != instruction.U_RRRCI {
+		err := errors.New("suffix is not S_RRRCI nor U_RRRCI")
+		panic(err)
+	}
+
+	thread := this.scoreboard[instruction_]
+
+	ra := thread.RegFile().ReadSrcReg(instruction_.Ra(), word.SIGNED)
+	rb := thread.RegFile().ReadSrcReg(instruction_.Rb(), word.SIGNED)
+
+	var result int64
+
+	op_code := instruction_.OpCode()
+	if op_code == instruction.ASR {
+		result = this.alu.Asr(ra, rb)
+	} else if op_code == instruction.CMPB4 {
+		result = this.alu.Cmpb4(ra, rb)
+	} else if op_code == instruction.LSL {
+		result = this.alu.Lsl(ra, rb)
+	} else if op_code == instruction.LSL1 {
+		result = this.alu.Lsl1(ra, rb)
+	} else if op_code == instruction.LSL1X {
+		result = this.alu.Lsl1x(ra, rb)
+	} else if op_code == instruction.LSLX {
+		result = this.alu.Lslx(ra, rb)
+	} else if op_code == instruction.LSR {
+		result = this.alu.Lsr(ra, rb)
+	} else if op_code == instruction.LSR1 {
+		result = this.alu.Lsr1(ra, rb)
+	} else if op_code == instruction.LSR1X {
+		result = this.alu.Lsr1x(ra, rb)
+	} else if op_code == instruction.LSRX {
+		result = this.alu.Lsrx(ra, rb)
+	} else if op_code == instruction.ROL {
+		result = this.alu.Rol(ra, rb)
+	} else if op_code == instruction.ROR {
+		result = this.alu.Ror(ra, rb)
+	} else {
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
+		panic(err)
+	}
+
+	thread.RegFile().ClearConditions()
+	this.SetLogNzCc(instruction_, ra, result)
+
+	var even int64
+	var odd int64
+	if instruction_.Suffix() == instruction.S_RRRCI {
+		even, odd = this.alu.SignedExtension(result)
+	} else if instruction_.Suffix() == instruction.U_RRRCI {
+		even, odd = this.alu.UnsignedExtension(result)
+	} else {
+		err := errors.New("suffix is not S_RRRCI nor U_RRRCI")
+		panic(err)
+	}
+
+	thread.RegFile().WritePairReg(instruction_.Dc(), even, odd)
+
+	if thread.RegFile().ReadConditionReg(instruction_.Condition()) {
+		thread.RegFile().WritePcReg(instruction_.Pc().Value())
+	} else {
+		thread.RegFile().IncrementPcReg()
+	}
+
+	this.SetFlags(instruction_, result, false)
+}
+
+func (this *Logic) ExecuteMulSRrrci(instruction_ *instruction.Instruction) {
+	if _, found := instruction_.MulRrrciOpCodes()[instruction_.OpCode()]; !found {
+		err := errors.New("op code is not a valid mul RRRCI op code")
+		panic(err)
+	} else if instruction_.Suffix() != instruction.S_RRRCI && instruction_.Suffix() != instruction.U_RRRCI {
+		err := errors.New("suffix is not S_RRRCI nor U_RRRCI")
+		panic(err)
+	}
+
+	thread := this.scoreboard[instruction_]
+
+	ra := thread.RegFile().ReadSrcReg(instruction_.Ra(), word.SIGNED)
+	rb := thread.RegFile().ReadSrcReg(instruction_.Rb(), word.SIGNED)
+
+	var result int64
+
+	op_code := instruction_.OpCode()
+	if op_code == instruction.MUL_SH_SH {
+		result = this.alu.MulShSh(ra, rb)
+	} else if op_code == instruction.MUL_SH_SL {
+		result = this.alu.MulShSl(ra, rb)
+	} else if op_code == instruction.MUL_SH_UH {
+		result = this.alu.MulShUh(ra, rb)
+	} else if op_code == instruction.MUL_SH_UL {
+		result = this.alu.MulShUl(ra, rb)
+	} else if op_code == instruction.MUL_SL_SH {
+		result = this.alu.MulSlSh(ra, rb)
+	} else if op_code == instruction.MUL_SL_SL {
+		result = this.alu.MulSlSl(ra, rb)
+	} else if op_code == instruction.MUL_SL_UH {
+		result = this.alu.MulSlUh(ra, rb)
+	} else if op_code == instruction.MUL_SL_UL {
+		result = this.alu.MulSlUl(ra, rb)
+	} else if op_code == instruction.MUL_UH_UH {
+		result = this.alu.MulUhUh(ra, rb)
+	} else if op_code == instruction.MUL_UH_UL {
+		result = this.alu.MulUhUl(ra, rb)
+	} else if op_code == instruction.MUL_UL_UH {
+		result = this.alu.MulUlUh(ra, rb)
+	} else if op_code == instruction.MUL_UL_UL {
+		result = this.alu.MulUlUl(ra, rb)
+	} else {
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
+		panic(err)
+	}
+
+	thread.RegFile().ClearConditions()
+	this.SetMulNzCc(instruction_, ra, result)
+
+	var even int64
+	var odd int64
+	if instruction_.Suffix() == instruction.S_RRRCI {
+		even, odd = this.alu.SignedExtension(result)
+	} else if instruction_.Suffix() == instruction.U_RRRCI {
+		even, odd = this.alu.UnsignedExtension(result)
+	} else {
+		err := errors.New("suffix is not S_RRRCI nor U_RRRCI")
+		panic(err)
+	}
+
+	thread.RegFile().WritePairReg(instruction_.Dc(), even, odd)
+
+	if thread.RegFile().ReadConditionReg(instruction_.Condition()) {
+		thread.RegFile().WritePcReg(instruction_.Pc().Value())
+	} else {
+		thread.RegFile().IncrementPcReg()
+	}
+
+	this.SetFlags(instruction_, result, false)
+}
+
+func (this *Logic) ExecuteRsubSRrrci(instruction_ *instruction.Instruction) {
+	if _, found := instruction_.RsubRrrciOpCodes()[instruction_.OpCode()]; !found {
+		err := errors.New("op code is not a valid rsub RRRCI op code")
+		panic(err)
+	} else if instruction_.Suffix() != instruction.S_RRRCI && instruction_.Suffix() != instruction.U_RRRCI {
+		err := errors.New("suffix is not S_RRRCI nor U_RRRCI")
+		panic(err)
+	}
+
+	thread := this.scoreboard[instruction_]
+
+	ra := thread.RegFile().ReadSrcReg(instruction_.Ra(), word.SIGNED)
+	rb := thread.RegFile().ReadSrcReg(instruction_.Rb(), word.SIGNED)
+
+	var result int64
+	var carry bool
+	var overflow bool
+
+	op_code := instruction_.OpCode()
+	if op_code == instruction.RSUB {
+		result, carry, overflow = this.alu.Sub(rb, ra)
+	} else if op_code == instruction.RSUBC {
+		carry_flag := thread.RegFile().ReadFlagReg(instruction.CARRY)
+		result, carry, overflow = this.alu.Subc(rb, ra, carry_flag)
+	} else if op_code == instruction.SUB {
+		result, carry, overflow = this.alu.Sub(ra, rb)
+	} else if op_code == instruction.SUBC {
+		carry_flag := thread.RegFile().ReadFlagReg(instruction.CARRY)
+		result, carry, overflow = this.alu.Subc(ra, rb, carry_flag)
+	} else {
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
+		panic(err)
+	}
+
+	thread.RegFile().ClearConditions()
+	this.SetSubNzCc(instruction_, ra, rb, result, carry, overflow)
+
+	var even int64
+	var odd int64
+	if instruction_.Suffix() == instruction.S_RRRCI {
+		even, odd = this.alu.SignedExtension(result)
+	} else if instruction_.Suffix() == instruction.U_RRRCI {
+		even, odd = this.alu.UnsignedExtension(result)
+	} else {
+		err := errors.New("suffix is not S_RRRCI nor U_RRRCI")
+		panic(err)
+	}
+
+	thread.RegFile().WritePairReg(instruction_.Dc(), even, odd)
+
+	if thread.RegFile().ReadConditionReg(instruction_.Condition()) {
+		thread.RegFile().WritePcReg(instruction_.Pc().Value())
+	} else {
+		thread.RegFile().IncrementPcReg()
+	}
+
+	this.SetFlags(instruction_, result, carry)
+}
+
+func (this *Logic) ExecuteRr(instruction_ *instruction.Instruction) {
+	if _, found := instruction_.RrOpCodes()[instruction_.OpCode()]; !found {
+		err := errors.New("op code is not a valid RR op code")
+		panic(err)
+	} else if instruction_.Suffix() != instruction.RR {
+		err := errors.New("suffix is not RR")
+		panic(err)
+	}
+
+	thread := this.scoreboard[instruction_]
+
+	ra := thread.RegFile().ReadSrcReg(instruction_.Ra(), word.SIGNED)
+
+	var result int64
+
+	op_code := instruction_.OpCode()
+	if op_code == instruction.CAO {
+		result = this.alu.Cao(ra)
+	} else if op_code == instruction.CLO {
+		result = this.alu.Clo(ra)
+	} else if op_code == instruction.CLS {
+		result = this.alu.Cls(ra)
+	} else if op_code == instruction.CLZ {
+		result = this.alu.Clz(ra)
+	} else if op_code == instruction.EXTSB {
+		result = this.alu.Extsb(ra)
+	} else if op_code == instruction.EXTSH {
+		result = this.alu.Extsh(ra)
+	} else if op_code == instruction.EXTUB {
+		result = this.alu.Extub(ra)
+	} else if op_code == instruction.EXTUH {
+		result = this.alu.Extuh(ra)
+	} else if op_code == instruction.SATS {
+		result = this.alu.Sats(ra)
+	} else if op_code == instruction.TIME_CFG {
+		result = this.perf_counter.Config(ra)
+	} else {
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
+		panic(err)
+	}
+
+	thread.RegFile().ClearConditions()
+	thread.RegFile().WriteGpReg(instruction_.Rc(), result)
+	thread.RegFile().IncrementPcReg()
+
+	// time_cfg leaves the flags as they are
+	if op_code != instruction.TIME_CFG {
+		this.SetFlags(instruction_, result, false)
+	}
+}
+
+func (this *Logic) ExecuteRrc(instruction_ *instruction.Instruction) {
+	if _, found := instruction_.RrcOpCodes()[instruction_.OpCode()]; !found {
+		err := errors.New("op code is not a valid RRC op code")
+		panic(err)
+	} else if instruction_.Suffix() != instruction.RRC {
+		err := errors.New("suffix is not RRC")
+		panic(err)
+	}
+
+	thread := this.scoreboard[instruction_]
+
+	ra := thread.RegFile().ReadSrcReg(instruction_.Ra(), word.SIGNED)
+
+	var result int64
+
+	op_code := instruction_.OpCode()
+	if op_code == instruction.CAO {
+		result = this.alu.Cao(ra)
+	} else if op_code == instruction.CLO {
+		result = this.alu.Clo(ra)
+	} else if op_code == instruction.CLS {
+		result = this.alu.Cls(ra)
+	} else if op_code == instruction.CLZ {
+		result = this.alu.Clz(ra)
+	} else if op_code == instruction.EXTSB {
+		result = this.alu.Extsb(ra)
+	} else if op_code == instruction.EXTSH {
+		result = this.alu.Extsh(ra)
+	} else if op_code == instruction.EXTUB {
+		result = this.alu.Extub(ra)
+	} else if op_code == instruction.EXTUH {
+		result = this.alu.Extuh(ra)
+	} else if op_code == instruction.SATS {
+		result = this.alu.Sats(ra)
+	} else {
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
+		panic(err)
+	}
+
+	thread.RegFile().ClearConditions()
+	this.SetLogSetCc(instruction_, result)
+
+	if thread.RegFile().ReadConditionReg(instruction_.Condition()) {
+		thread.RegFile().WriteGpReg(instruction_.Rc(), 1)
+	} else {
+		thread.RegFile().WriteGpReg(instruction_.Rc(), 0)
+	}
+
+	thread.RegFile().IncrementPcReg()
+
+	this.SetFlags(instruction_, result, false)
+}
+
+func (this *Logic) ExecuteRrci(instruction_ *instruction.Instruction) {
+	if _, found := instruction_.RrciOpCodes()[instruction_.OpCode()]; !found {
+		err := errors.New("op code is not a valid RRCI op code")
+		panic(err)
+	} else if instruction_.Suffix() != instruction.RRCI {
+		err := errors.New("suffix is not RRCI")
+		panic(err)
+	}
+
+	op_code := instruction_.OpCode()
+	if _, is_cao_rrci_op_code := instruction_.CaoRrciOpCodes()[op_code]; is_cao_rrci_op_code {
+		this.ExecuteCaoRrci(instruction_)
+	} else if _, is_extsb_rrci_op_code := instruction_.ExtsbRrciOpCodes()[op_code]; is_extsb_rrci_op_code {
+		this.ExecuteExtsbRrci(instruction_)
+	} else if _, is_time_cfg_rrci_op_code := instruction_.TimeCfgRrciOpCodes()[op_code]; is_time_cfg_rrci_op_code {
+		this.ExecuteTimeCfgRrci(instruction_)
+	} else {
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
+		panic(err)
+	}
+}
+
+func (this *Logic) ExecuteCaoRrci(instruction_ *instruction.Instruction) {
+	if _, found := instruction_.CaoRrciOpCodes()[instruction_.OpCode()]; !found {
+		err := errors.New("op code is not a valid cao RRCI op code")
+		panic(err)
+	} else if instruction_.Suffix() != instruction.RRCI {
+		err := errors.New("suffix is not RRCI")
+		panic(err)
+	}
+
+	thread := this.scoreboard[instruction_]
+
+	ra := thread.RegFile().ReadSrcReg(instruction_.Ra(), word.SIGNED)
+
+	var result int64
+
+	op_code := instruction_.OpCode()
+	if op_code == instruction.CAO {
+		result = this.alu.Cao(ra)
+	} else if op_code == instruction.CLO {
+		result = this.alu.Clo(ra)
+	} else if op_code == instruction.CLS {
+		result = this.alu.Cls(ra)
+	} else if op_code == instruction.CLZ {
+		result = this.alu.Clz(ra)
+	} else {
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
-	thread.RegFile().WritePairReg(instruction_.Dc(), even, odd)
+	thread.RegFile().ClearConditions()
+	this.SetCountNzCc(instruction_, ra, result)
+
+	thread.RegFile().WriteGpReg(instruction_.Rc(), result)
 
 	if thread.RegFile().ReadConditionReg(instruction_.Condition()) {
 		thread.RegFile().WritePcReg(instruction_.Pc().Value())
@@ -3527,50 +5148,42 @@ func (this *Logic) ExecuteAsrSRrici(instruction_ *instruction.Instruction) {
 	this.SetFlags(instruction_, result, false)
 }
 
-func (this *Logic) ExecuteSubSRrici(instruction_ *instruction.Instruction) {
-	if _, found := instruction_.SubRriciOpCodes()[instruction_.OpCode()]; !found {
-		err := errors.New("op code is not a valid sub RRICI op code")
+func (this *Logic) ExecuteExtsbRrci(instruction_ *instruction.Instruction) {
+	if _, found := instruction_.ExtsbRrciOpCodes()[instruction_.OpCode()]; !found {
+		err := errors.New("op code is not a valid extsb RRCI op code")
 		panic(err)
-	} else if instruction_.Suffix() != instruction.S_RRICI && instruction_.Suffix() != instruction.U_RRICI {
-		err := errors.New("suffix is not S_RRICI nor U_RRICI")
+	} else if instruction_.Suffix() != instruction.RRCI {
+		err := errors.New("suffix is not RRCI")
 		panic(err)
 	}
 
 	thread := this.scoreboard[instruction_]
 
 	ra := thread.RegFile().ReadSrcReg(instruction_.Ra(), word.SIGNED)
-	imm := instruction_.Imm().Value()
 
 	var result int64
-	var carry bool
-	var overflow bool
 
 	op_code := instruction_.OpCode()
-	if op_code == instruction.ASR {
-		result, carry, overflow = this.alu.Sub(ra, imm)
-	} else if op_code == instruction.SUBC {
-		carry_flag := thread.RegFile().ReadFlagReg(instruction.CARRY)
-		result, carry, overflow = this.alu.Subc(ra, imm, carry_flag)
+	if op_code == instruction.EXTSB {
+		result = this.alu.Extsb(ra)
+	} else if op_code == instruction.EXTSH {
+		result = this.alu.Extsh(ra)
+	} else if op_code == instruction.EXTUB {
+		result = this.alu.Extub(ra)
+	} else if op_code == instruction.EXTUH {
+		result = this.alu.Extuh(ra)
+	} else if op_code == instruction.SATS {
+		result = this.alu.Sats(ra)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
 	thread.RegFile().ClearConditions()
-	this.SetSubNzCc(instruction_, ra, imm, result, carry, overflow)
-
-	var even int64
-	var odd int64
-	if instruction_.Suffix() == instruction.S_RRICI {
-		even, odd = this.alu.SignedExtension(result)
-	} else if instruction_.Suffix() == instruction.U_RRICI {
-		even, odd = this.alu.UnsignedExtension(result)
-	} else {
-		err := errors.New("suffix is not S_RRI nor U_RRI")
-		panic(err)
-	}
+	this.SetLogNzCc(instruction_, ra, result)
 
-	thread.RegFile().WritePairReg(instruction_.Dc(), even, odd)
+	thread.RegFile().WriteGpReg(instruction_.Rc(), result)
 
 	if thread.RegFile().ReadConditionReg(instruction_.Condition()) {
 		thread.RegFile().WritePcReg(instruction_.Pc().Value())
@@ -3578,110 +5191,40 @@ func (this *Logic) ExecuteSubSRrici(instruction_ *instruction.Instruction) {
 		thread.RegFile().IncrementPcReg()
 	}
 
-	this.SetFlags(instruction_, result, carry)
+	this.SetFlags(instruction_, result, false)
 }
 
-func (this *Logic) ExecuteSRrif(instruction_ *instruction.Instruction) {
-	if _, found := instruction_.RrifOpCodes()[instruction_.OpCode()]; !found {
-		err := errors.New("op code is not a valid RRIF op code")
+func (this *Logic) ExecuteTimeCfgRrci(instruction_ *instruction.Instruction) {
+	if _, found := instruction_.TimeCfgRrciOpCodes()[instruction_.OpCode()]; !found {
+		err := errors.New("op code is not a valid time_cfg RRCI op code")
 		panic(err)
-	} else if instruction_.Suffix() != instruction.S_RRIF && instruction_.Suffix() != instruction.U_RRIF {
-		err := errors.New("suffix is not S_RRIF nor U_RRIF")
+	} else if instruction_.Suffix() != instruction.RRCI {
+		err := errors.New("suffix is not RRCI")
 		panic(err)
 	}
 
 	thread := this.scoreboard[instruction_]
 
 	ra := thread.RegFile().ReadSrcReg(instruction_.Ra(), word.SIGNED)
-	imm := instruction_.Imm().Value()
 
-	var result int64
-	var carry bool
-
-	op_code := instruction_.OpCode()
-	if op_code == instruction.ADD {
-		result, carry, _ = this.alu.Add(ra, imm)
-	} else if op_code == instruction.ADDC {
-		carry_flag := thread.RegFile().ReadFlagReg(instruction.CARRY)
-		result, carry, _ = this.alu.Addc(ra, imm, carry_flag)
-	} else if op_code == instruction.AND {
-		result = this.alu.And(ra, imm)
-		carry = false
-	} else if op_code == instruction.ANDN {
-		result = this.alu.Andn(ra, imm)
-		carry = false
-	} else if op_code == instruction.NAND {
-		result = this.alu.Nand(ra, imm)
-		carry = false
-	} else if op_code == instruction.NOR {
-		result = this.alu.Nor(ra, imm)
-		carry = false
-	} else if op_code == instruction.NXOR {
-		result = this.alu.Nxor(ra, imm)
-		carry = false
-	} else if op_code == instruction.OR {
-		result = this.alu.Or(ra, imm)
-		carry = false
-	} else if op_code == instruction.ORN {
-		result = this.alu.Orn(ra, imm)
-		carry = false
-	} else if op_code == instruction.SUB {
-		result, carry, _ = this.alu.Sub(ra, imm)
-	} else if op_code == instruction.SUBC {
-		carry_flag := thread.RegFile().ReadFlagReg(instruction.CARRY)
-		result, carry, _ = this.alu.Subc(ra, imm, carry_flag)
-	} else if op_code == instruction.XOR {
-		result = this.alu.Xor(ra, imm)
-		carry = false
-	} else if op_code == instruction.HASH {
-		result = this.alu.Hash(ra, imm)
-		carry = false
-	} else {
-		err := errors.New("op code is not valid")
-		panic(err)
-	}
+	result := this.perf_counter.Config(ra)
 
 	thread.RegFile().ClearConditions()
+	thread.RegFile().WriteGpReg(instruction_.Rc(), result)
 
-	var even int64
-	var odd int64
-	if instruction_.Suffix() == instruction.S_RRIF {
-		even, odd = this.alu.SignedExtension(result)
-	} else if instruction_.Suffix() == instruction.U_RRIF {
-		even, odd = this.alu.UnsignedExtension(result)
+	if thread.RegFile().ReadConditionReg(instruction_.Condition()) {
+		thread.RegFile().WritePcReg(instruction_.Pc().Value())
 	} else {
-		err := errors.New("suffix is not S_RRIF nor U_RRIF")
-		panic(err)
+		thread.RegFile().IncrementPcReg()
 	}
-
-	thread.RegFile().WritePairReg(instruction_.Dc(), even, odd)
-
-	thread.RegFile().IncrementPcReg()
-
-	this.SetFlags(instruction_, result, carry)
-}
-
-func (this *Logic) ExecuteSRrr(instruction_ *instruction.Instruction) {
-	err := errors.New("ExecuteSRrr is not yet implemented")
-	panic(err)
-}
-
-func (this *Logic) ExecuteSRrrc(instruction_ *instruction.Instruction) {
-	err := errors.New("ExecuteSRrrc is not yet implemented")
-	panic(err)
-}
-
-func (this *Logic) ExecuteSRrrci(instruction_ *instruction.Instruction) {
-	err := errors.New("ExecuteSRrrci is not yet implemented")
-	panic(err)
 }
 
-func (this *Logic) ExecuteRr(instruction_ *instruction.Instruction) {
+func (this *Logic) ExecuteZr(instruction_ *instruction.Instruction) {
 	if _, found := instruction_.RrOpCodes()[instruction_.OpCode()]; !found {
 		err := errors.New("op code is not a valid RR op code")
 		panic(err)
-	} else if instruction_.Suffix() != instruction.RR {
-		err := errors.New("suffix is not RR")
+	} else if instruction_.Suffix() != instruction.ZR {
+		err := errors.New("suffix is not ZR")
 		panic(err)
 	}
 
@@ -3711,25 +5254,27 @@ func (this *Logic) ExecuteRr(instruction_ *instruction.Instruction) {
 	} else if op_code == instruction.SATS {
 		result = this.alu.Sats(ra)
 	} else if op_code == instruction.TIME_CFG {
-		err := errors.New("TimeCfg is not yet implemented")
-		panic(err)
+		result = this.perf_counter.Config(ra)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
 	thread.RegFile().ClearConditions()
-	thread.RegFile().WriteGpReg(instruction_.Rc(), result)
 	thread.RegFile().IncrementPcReg()
 
-	this.SetFlags(instruction_, result, false)
+	// time_cfg leaves the flags as they are
+	if op_code != instruction.TIME_CFG {
+		this.SetFlags(instruction_, result, false)
+	}
 }
 
-func (this *Logic) ExecuteRrc(instruction_ *instruction.Instruction) {
+func (this *Logic) ExecuteZrc(instruction_ *instruction.Instruction) {
 	if _, found := instruction_.RrcOpCodes()[instruction_.OpCode()]; !found {
 		err := errors.New("op code is not a valid RRC op code")
 		panic(err)
-	} else if instruction_.Suffix() != instruction.RRC {
+	} else if instruction_.Suffix() != instruction.ZRC {
 		err := errors.New("suffix is not RRC")
 		panic(err)
 	}
@@ -3759,56 +5304,49 @@ func (this *Logic) ExecuteRrc(instruction_ *instruction.Instruction) {
 		result = this.alu.Extuh(ra)
 	} else if op_code == instruction.SATS {
 		result = this.alu.Sats(ra)
-	} else if op_code == instruction.TIME_CFG {
-		err := errors.New("TimeCfg is not yet implemented")
-		panic(err)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
 	thread.RegFile().ClearConditions()
 	this.SetLogSetCc(instruction_, result)
 
-	if thread.RegFile().ReadConditionReg(instruction_.Condition()) {
-		thread.RegFile().WriteGpReg(instruction_.Rc(), 1)
-	} else {
-		thread.RegFile().WriteGpReg(instruction_.Rc(), 0)
-	}
-
 	thread.RegFile().IncrementPcReg()
 
 	this.SetFlags(instruction_, result, false)
 }
 
-func (this *Logic) ExecuteRrci(instruction_ *instruction.Instruction) {
+func (this *Logic) ExecuteZrci(instruction_ *instruction.Instruction) {
 	if _, found := instruction_.RrciOpCodes()[instruction_.OpCode()]; !found {
 		err := errors.New("op code is not a valid RRCI op code")
 		panic(err)
-	} else if instruction_.Suffix() != instruction.RRCI {
-		err := errors.New("suffix is not RRCI")
+	} else if instruction_.Suffix() != instruction.ZRCI {
+		err := errors.New("suffix is not ZRCI")
 		panic(err)
 	}
 
 	op_code := instruction_.OpCode()
 	if _, is_cao_rrci_op_code := instruction_.CaoRrciOpCodes()[op_code]; is_cao_rrci_op_code {
-		this.ExecuteCaoRrci(instruction_)
+		this.ExecuteCaoZrci(instruction_)
 	} else if _, is_extsb_rrci_op_code := instruction_.ExtsbRrciOpCodes()[op_code]; is_extsb_rrci_op_code {
-		this.ExecuteExtsbRrci(instruction_)
+		this.ExecuteExtsbZrci(instruction_)
 	} else if _, is_time_cfg_rrci_op_code := instruction_.TimeCfgRrciOpCodes()[op_code]; is_time_cfg_rrci_op_code {
-		this.ExecuteTimeCfgRrci(instruction_)
+		this.ExecuteTimeCfgZrci(instruction_)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 }
 
-func (this *Logic) ExecuteCaoRrci(instruction_ *instruction.Instruction) {
+func (this *Logic) ExecuteCaoZrci(instruction_ *instruction.Instruction) {
 	if _, found := instruction_.CaoRrciOpCodes()[instruction_.OpCode()]; !found {
 		err := errors.New("op code is not a valid cao RRCI op code")
 		panic(err)
-	} else if instruction_.Suffix() != instruction.RRCI {
-		err := errors.New("suffix is not RRCI")
+	} else if instruction_.Suffix() != instruction.ZRCI {
+		err := errors.New("suffix is not ZRCI")
 		panic(err)
 	}
 
@@ -3828,15 +5366,14 @@ func (this *Logic) ExecuteCaoRrci(instruction_ *instruction.Instruction) {
 	} else if op_code == instruction.CLZ {
 		result = this.alu.Clz(ra)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
 	thread.RegFile().ClearConditions()
 	this.SetCountNzCc(instruction_, ra, result)
 
-	thread.RegFile().WriteGpReg(instruction_.Rc(), result)
-
 	if thread.RegFile().ReadConditionReg(instruction_.Condition()) {
 		thread.RegFile().WritePcReg(instruction_.Pc().Value())
 	} else {
@@ -3846,12 +5383,12 @@ func (this *Logic) ExecuteCaoRrci(instruction_ *instruction.Instruction) {
 	this.SetFlags(instruction_, result, false)
 }
 
-func (this *Logic) ExecuteExtsbRrci(instruction_ *instruction.Instruction) {
+func (this *Logic) ExecuteExtsbZrci(instruction_ *instruction.Instruction) {
 	if _, found := instruction_.ExtsbRrciOpCodes()[instruction_.OpCode()]; !found {
 		err := errors.New("op code is not a valid extsb RRCI op code")
 		panic(err)
-	} else if instruction_.Suffix() != instruction.RRCI {
-		err := errors.New("suffix is not RRCI")
+	} else if instruction_.Suffix() != instruction.ZRCI {
+		err := errors.New("suffix is not ZRCI")
 		panic(err)
 	}
 
@@ -3873,15 +5410,14 @@ func (this *Logic) ExecuteExtsbRrci(instruction_ *instruction.Instruction) {
 	} else if op_code == instruction.SATS {
 		result = this.alu.Sats(ra)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
 	thread.RegFile().ClearConditions()
 	this.SetLogNzCc(instruction_, ra, result)
 
-	thread.RegFile().WriteGpReg(instruction_.Rc(), result)
-
 	if thread.RegFile().ReadConditionReg(instruction_.Condition()) {
 		thread.RegFile().WritePcReg(instruction_.Pc().Value())
 	} else {
@@ -3891,17 +5427,36 @@ func (this *Logic) ExecuteExtsbRrci(instruction_ *instruction.Instruction) {
 	this.SetFlags(instruction_, result, false)
 }
 
-func (this *Logic) ExecuteTimeCfgRrci(instruction_ *instruction.Instruction) {
-	err := errors.New("ExecuteTimeCfgRrci is not yet implemented")
-	panic(err)
+func (this *Logic) ExecuteTimeCfgZrci(instruction_ *instruction.Instruction) {
+	if _, found := instruction_.TimeCfgRrciOpCodes()[instruction_.OpCode()]; !found {
+		err := errors.New("op code is not a valid time_cfg RRCI op code")
+		panic(err)
+	} else if instruction_.Suffix() != instruction.ZRCI {
+		err := errors.New("suffix is not ZRCI")
+		panic(err)
+	}
+
+	thread := this.scoreboard[instruction_]
+
+	ra := thread.RegFile().ReadSrcReg(instruction_.Ra(), word.SIGNED)
+
+	this.perf_counter.Config(ra)
+
+	thread.RegFile().ClearConditions()
+
+	if thread.RegFile().ReadConditionReg(instruction_.Condition()) {
+		thread.RegFile().WritePcReg(instruction_.Pc().Value())
+	} else {
+		thread.RegFile().IncrementPcReg()
+	}
 }
 
-func (this *Logic) ExecuteZr(instruction_ *instruction.Instruction) {
+func (this *Logic) ExecuteSRr(instruction_ *instruction.Instruction) {
 	if _, found := instruction_.RrOpCodes()[instruction_.OpCode()]; !found {
 		err := errors.New("op code is not a valid RR op code")
 		panic(err)
-	} else if instruction_.Suffix() != instruction.ZR {
-		err := errors.New("suffix is not ZR")
+	} else if instruction_.Suffix() != instruction.S_RR && instruction_.Suffix() != instruction.U_RR {
+		err := errors.New("suffix is not S_RR nor U_RR")
 		panic(err)
 	}
 
@@ -3931,25 +5486,42 @@ func (this *Logic) ExecuteZr(instruction_ *instruction.Instruction) {
 	} else if op_code == instruction.SATS {
 		result = this.alu.Sats(ra)
 	} else if op_code == instruction.TIME_CFG {
-		err := errors.New("TimeCfg is not yet implemented")
-		panic(err)
+		result = this.perf_counter.Config(ra)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
 	thread.RegFile().ClearConditions()
+
+	var even int64
+	var odd int64
+	if instruction_.Suffix() == instruction.S_RR {
+		even, odd = this.alu.SignedExtension(result)
+	} else if instruction_.Suffix() == instruction.U_RR {
+		even, odd = this.alu.UnsignedExtension(result)
+	} else {
+		err := errors.New("suffix is not S_RR nor U_RR")
+		panic(err)
+	}
+
+	thread.RegFile().WritePairReg(instruction_.Dc(), even, odd)
+
 	thread.RegFile().IncrementPcReg()
 
-	this.SetFlags(instruction_, result, false)
+	// time_cfg leaves the flags as they are
+	if op_code != instruction.TIME_CFG {
+		this.SetFlags(instruction_, result, false)
+	}
 }
 
-func (this *Logic) ExecuteZrc(instruction_ *instruction.Instruction) {
+func (this *Logic) ExecuteSRrc(instruction_ *instruction.Instruction) {
 	if _, found := instruction_.RrcOpCodes()[instruction_.OpCode()]; !found {
 		err := errors.New("op code is not a valid RRC op code")
 		panic(err)
-	} else if instruction_.Suffix() != instruction.ZRC {
-		err := errors.New("suffix is not RRC")
+	} else if instruction_.Suffix() != instruction.S_RRC && instruction_.Suffix() != instruction.U_RRC {
+		err := errors.New("suffix is not S_RRC nor U_RRC")
 		panic(err)
 	}
 
@@ -3978,50 +5550,56 @@ func (this *Logic) ExecuteZrc(instruction_ *instruction.Instruction) {
 		result = this.alu.Extuh(ra)
 	} else if op_code == instruction.SATS {
 		result = this.alu.Sats(ra)
-	} else if op_code == instruction.TIME_CFG {
-		err := errors.New("TimeCfg is not yet implemented")
-		panic(err)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
 	thread.RegFile().ClearConditions()
 	this.SetLogSetCc(instruction_, result)
 
+	// the odd register of the pair holds the low word, which the condition sets
+	if thread.RegFile().ReadConditionReg(instruction_.Condition()) {
+		thread.RegFile().WritePairReg(instruction_.Dc(), 0, 1)
+	} else {
+		thread.RegFile().WritePairReg(instruction_.Dc(), 0, 0)
+	}
+
 	thread.RegFile().IncrementPcReg()
 
 	this.SetFlags(instruction_, result, false)
 }
 
-func (this *Logic) ExecuteZrci(instruction_ *instruction.Instruction) {
+func (this *Logic) ExecuteSRrci(instruction_ *instruction.Instruction) {
 	if _, found := instruction_.RrciOpCodes()[instruction_.OpCode()]; !found {
 		err := errors.New("op code is not a valid RRCI op code")
 		panic(err)
-	} else if instruction_.Suffix() != instruction.ZRCI {
-		err := errors.New("suffix is not ZRCI")
+	} else if instruction_.Suffix() != instruction.S_RRCI && instruction_.Suffix() != instruction.U_RRCI {
+		err := errors.New("suffix is not S_RRCI nor U_RRCI")
 		panic(err)
 	}
 
 	op_code := instruction_.OpCode()
 	if _, is_cao_rrci_op_code := instruction_.CaoRrciOpCodes()[op_code]; is_cao_rrci_op_code {
-		this.ExecuteCaoZrci(instruction_)
+		this.ExecuteCaoSRrci(instruction_)
 	} else if _, is_extsb_rrci_op_code := instruction_.ExtsbRrciOpCodes()[op_code]; is_extsb_rrci_op_code {
-		this.ExecuteExtsbZrci(instruction_)
+		this.ExecuteExtsbSRrci(instruction_)
 	} else if _, is_time_cfg_rrci_op_code := instruction_.TimeCfgRrciOpCodes()[op_code]; is_time_cfg_rrci_op_code {
-		this.ExecuteTimeCfgZrci(instruction_)
+		this.ExecuteTimeCfgSRrci(instruction_)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 }
 
-func (this *Logic) ExecuteCaoZrci(instruction_ *instruction.Instruction) {
+func (this *Logic) ExecuteCaoSRrci(instruction_ *instruction.Instruction) {
 	if _, found := instruction_.CaoRrciOpCodes()[instruction_.OpCode()]; !found {
 		err := errors.New("op code is not a valid cao RRCI op code")
 		panic(err)
-	} else if instruction_.Suffix() != instruction.ZRCI {
-		err := errors.New("suffix is not ZRCI")
+	} else if instruction_.Suffix() != instruction.S_RRCI && instruction_.Suffix() != instruction.U_RRCI {
+		err := errors.New("suffix is not S_RRCI nor U_RRCI")
 		panic(err)
 	}
 
@@ -4041,13 +5619,27 @@ func (this *Logic) ExecuteCaoZrci(instruction_ *instruction.Instruction) {
 	} else if op_code == instruction.CLZ {
 		result = this.alu.Clz(ra)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
 	thread.RegFile().ClearConditions()
 	this.SetCountNzCc(instruction_, ra, result)
 
+	var even int64
+	var odd int64
+	if instruction_.Suffix() == instruction.S_RRCI {
+		even, odd = this.alu.SignedExtension(result)
+	} else if instruction_.Suffix() == instruction.U_RRCI {
+		even, odd = this.alu.UnsignedExtension(result)
+	} else {
+		err := errors.New("suffix is not S_RRCI nor U_RRCI")
+		panic(err)
+	}
+
+	thread.RegFile().WritePairReg(instruction_.Dc(), even, odd)
+
 	if thread.RegFile().ReadConditionReg(instruction_.Condition()) {
 		thread.RegFile().WritePcReg(instruction_.Pc().Value())
 	} else {
@@ -4057,12 +5649,12 @@ func (this *Logic) ExecuteCaoZrci(instruction_ *instruction.Instruction) {
 	this.SetFlags(instruction_, result, false)
 }
 
-func (this *Logic) ExecuteExtsbZrci(instruction_ *instruction.Instruction) {
+func (this *Logic) ExecuteExtsbSRrci(instruction_ *instruction.Instruction) {
 	if _, found := instruction_.ExtsbRrciOpCodes()[instruction_.OpCode()]; !found {
 		err := errors.New("op code is not a valid extsb RRCI op code")
 		panic(err)
-	} else if instruction_.Suffix() != instruction.ZRCI {
-		err := errors.New("suffix is not ZRCI")
+	} else if instruction_.Suffix() != instruction.S_RRCI && instruction_.Suffix() != instruction.U_RRCI {
+		err := errors.New("suffix is not S_RRCI nor U_RRCI")
 		panic(err)
 	}
 
@@ -4084,13 +5676,27 @@ func (this *Logic) ExecuteExtsbZrci(instruction_ *instruction.Instruction) {
 	} else if op_code == instruction.SATS {
 		result = this.alu.Sats(ra)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
 	thread.RegFile().ClearConditions()
 	this.SetLogNzCc(instruction_, ra, result)
 
+	var even int64
+	var odd int64
+	if instruction_.Suffix() == instruction.S_RRCI {
+		even, odd = this.alu.SignedExtension(result)
+	} else if instruction_.Suffix() == instruction.U_RRCI {
+		even, odd = this.alu.UnsignedExtension(result)
+	} else {
+		err := errors.New("suffix is not S_RRCI nor U_RRCI")
+		panic(err)
+	}
+
+	thread.RegFile().WritePairReg(instruction_.Dc(), even, odd)
+
 	if thread.RegFile().ReadConditionReg(instruction_.Condition()) {
 		thread.RegFile().WritePcReg(instruction_.Pc().Value())
 	} else {
@@ -4100,24 +5706,41 @@ func (this *Logic) ExecuteExtsbZrci(instruction_ *instruction.Instruction) {
 	this.SetFlags(instruction_, result, false)
 }
 
-func (this *Logic) ExecuteTimeCfgZrci(instruction_ *instruction.Instruction) {
-	err := errors.New("ExecuteTimeCfgZrci is not yet implemented")
-	panic(err)
-}
+func (this *Logic) ExecuteTimeCfgSRrci(instruction_ *instruction.Instruction) {
+	if _, found := instruction_.TimeCfgRrciOpCodes()[instruction_.OpCode()]; !found {
+		err := errors.New("op code is not a valid time_cfg RRCI op code")
+		panic(err)
+	} else if instruction_.Suffix() != instruction.S_RRCI && instruction_.Suffix() != instruction.U_RRCI {
+		err := errors.New("suffix is not S_RRCI nor U_RRCI")
+		panic(err)
+	}
 
-func (this *Logic) ExecuteSRr(instruction_ *instruction.Instruction) {
-	err := errors.New("ExecuteSRr is not yet implemented")
-	panic(err)
-}
+	thread := this.scoreboard[instruction_]
 
-func (this *Logic) ExecuteSRrc(instruction_ *instruction.Instruction) {
-	err := errors.New("ExecuteSRrc is not yet implemented")
-	panic(err)
-}
+	ra := thread.RegFile().ReadSrcReg(instruction_.Ra(), word.SIGNED)
 
-func (this *Logic) ExecuteSRrci(instruction_ *instruction.Instruction) {
-	err := errors.New("ExecuteSRrci is not yet implemented")
-	panic(err)
+	result := this.perf_counter.Config(ra)
+
+	thread.RegFile().ClearConditions()
+
+	var even int64
+	var odd int64
+	if instruction_.Suffix() == instruction.S_RRCI {
+		even, odd = this.alu.SignedExtension(result)
+	} else if instruction_.Suffix() == instruction.U_RRCI {
+		even, odd = this.alu.UnsignedExtension(result)
+	} else {
+		err := errors.New("suffix is not S_RRCI nor U_RRCI")
+		panic(err)
+	}
+
+	thread.RegFile().WritePairReg(instruction_.Dc(), even, odd)
+
+	if thread.RegFile().ReadConditionReg(instruction_.Condition()) {
+		thread.RegFile().WritePcReg(instruction_.Pc().Value())
+	} else {
+		thread.RegFile().IncrementPcReg()
+	}
 }
 
 func (this *Logic) ExecuteDrdici(instruction_ *instruction.Instruction) {
@@ -4135,7 +5758,8 @@ func (this *Logic) ExecuteDrdici(instruction_ *instruction.Instruction) {
 	} else if _, is_mul_step_drdici_op_code := instruction_.MulStepDrdiciOpCodes()[op_code]; is_mul_step_drdici_op_code {
 		this.ExecuteMulStepDrdici(instruction_)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 }
@@ -4268,7 +5892,8 @@ func (this *Logic) ExecuteRrri(instruction_ *instruction.Instruction) {
 	} else if op_code == instruction.ROL_ADD {
 		result, carry, _ = this.alu.RolAdd(ra, rb, imm)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -4307,7 +5932,8 @@ func (this *Logic) ExecuteRrrici(instruction_ *instruction.Instruction) {
 	} else if op_code == instruction.ROL_ADD {
 		result, carry, _ = this.alu.RolAdd(ra, rb, imm)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -4353,7 +5979,8 @@ func (this *Logic) ExecuteZrri(instruction_ *instruction.Instruction) {
 	} else if op_code == instruction.ROL_ADD {
 		result, carry, _ = this.alu.RolAdd(ra, rb, imm)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -4391,7 +6018,8 @@ func (this *Logic) ExecuteZrrici(instruction_ *instruction.Instruction) {
 	} else if op_code == instruction.ROL_ADD {
 		result, carry, _ = this.alu.RolAdd(ra, rb, imm)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -4408,13 +6036,114 @@ func (this *Logic) ExecuteZrrici(instruction_ *instruction.Instruction) {
 }
 
 func (this *Logic) ExecuteSRrri(instruction_ *instruction.Instruction) {
-	err := errors.New("ExecuteSRrri is not yet implemented")
-	panic(err)
+	if _, found := instruction_.RrriOpCodes()[instruction_.OpCode()]; !found {
+		err := errors.New("op code is not a valid RRRI op code")
+		panic(err)
+	} else if instruction_.Suffix() != instruction.S_RRRI && instruction_.Suffix() != instruction.U_RRRI {
+		err := errors.New("suffix is not S_RRRI nor U_RRRI")
+		panic(err)
+	}
+
+	thread := this.scoreboard[instruction_]
+
+	ra := thread.RegFile().ReadSrcReg(instruction_.Ra(), word.SIGNED)
+	rb := thread.RegFile().ReadSrcReg(instruction_.Rb(), word.SIGNED)
+	imm := instruction_.Imm().Value()
+
+	var result int64
+	var carry bool
+
+	op_code := instruction_.OpCode()
+	if op_code == instruction.LSL_ADD {
+		result, carry, _ = this.alu.LslAdd(ra, rb, imm)
+	} else if op_code == instruction.LSL_SUB {
+		result, carry, _ = this.alu.LslSub(ra, rb, imm)
+	} else if op_code == instruction.LSR_ADD {
+		result, carry, _ = this.alu.LsrAdd(ra, rb, imm)
+	} else if op_code == instruction.ROL_ADD {
+		result, carry, _ = this.alu.RolAdd(ra, rb, imm)
+	} else {
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
+		panic(err)
+	}
+
+	thread.RegFile().ClearConditions()
+
+	var even int64
+	var odd int64
+	if instruction_.Suffix() == instruction.S_RRRI {
+		even, odd = this.alu.SignedExtension(result)
+	} else if instruction_.Suffix() == instruction.U_RRRI {
+		even, odd = this.alu.UnsignedExtension(result)
+	} else {
+		err := errors.New("suffix is not S_RRRI nor U_RRRI")
+		panic(err)
+	}
+
+	thread.RegFile().WritePairReg(instruction_.Dc(), even, odd)
+
+	thread.RegFile().IncrementPcReg()
+
+	this.SetFlags(instruction_, result, carry)
 }
 
 func (this *Logic) ExecuteSRrrici(instruction_ *instruction.Instruction) {
-	err := errors.New("ExecuteSRrrici is not yet implemented")
-	panic(err)
+	if _, found := instruction_.RrriciOpCodes()[instruction_.OpCode()]; !found {
+		err := errors.New("op code is not a valid RRRI op code")
+		panic(err)
+	} else if instruction_.Suffix() != instruction.S_RRRICI && instruction_.Suffix() != instruction.U_RRRICI {
+		err := errors.New("suffix is not S_RRRICI nor U_RRRICI")
+		panic(err)
+	}
+
+	thread := this.scoreboard[instruction_]
+
+	ra := thread.RegFile().ReadSrcReg(instruction_.Ra(), word.SIGNED)
+	rb := thread.RegFile().ReadSrcReg(instruction_.Rb(), word.SIGNED)
+	imm := instruction_.Imm().Value()
+
+	var result int64
+	var carry bool
+
+	op_code := instruction_.OpCode()
+	if op_code == instruction.LSL_ADD {
+		result, carry, _ = this.alu.LslAdd(ra, rb, imm)
+	} else if op_code == instruction.LSL_SUB {
+		result, carry, _ = this.alu.LslSub(ra, rb, imm)
+	} else if op_code == instruction.LSR_ADD {
+		result, carry, _ = this.alu.LsrAdd(ra, rb, imm)
+	} else if op_code == instruction.ROL_ADD {
+		result, carry, _ = this.alu.RolAdd(ra, rb, imm)
+	} else {
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
+		panic(err)
+	}
+
+	thread.RegFile().ClearConditions()
+	this.SetDivNzCc(instruction_, ra)
+
+	var even int64
+	var odd int64
+	if instruction_.Suffix() == instruction.S_RRRICI {
+		even, odd = this.alu.SignedExtension(result)
+	} else if instruction_.Suffix() == instruction.U_RRRICI {
+		even, odd = this.alu.UnsignedExtension(result)
+	} else {
+		err := errors.New("suffix is not S_RRRICI nor U_RRRICI")
+		panic(err)
+	}
+
+	thread.RegFile().WritePairReg(instruction_.Dc(), even, odd)
+
+	if thread.RegFile().ReadConditionReg(instruction_.Condition()) {
+		thread.RegFile().WritePcReg(instruction_.Pc().Value())
+	} else {
+		thread.RegFile().IncrementPcReg()
+	}
+
+	this.SetFlags(instruction_, result, carry)
 }
 
 func (this *Logic) ExecuteRir(instruction_ *instruction.Instruction) {
@@ -4441,7 +6170,8 @@ func (this *Logic) ExecuteRir(instruction_ *instruction.Instruction) {
 		carry_flag := thread.RegFile().ReadFlagReg(instruction.CARRY)
 		result, carry, _ = this.alu.Subc(imm, ra, carry_flag)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -4476,7 +6206,8 @@ func (this *Logic) ExecuteRirc(instruction_ *instruction.Instruction) {
 		carry_flag := thread.RegFile().ReadFlagReg(instruction.CARRY)
 		result, carry, _ = this.alu.Subc(imm, ra, carry_flag)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -4519,7 +6250,8 @@ func (this *Logic) ExecuteRirci(instruction_ *instruction.Instruction) {
 		carry_flag := thread.RegFile().ReadFlagReg(instruction.CARRY)
 		result, carry, overflow = this.alu.Subc(imm, ra, carry_flag)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -4561,7 +6293,8 @@ func (this *Logic) ExecuteZir(instruction_ *instruction.Instruction) {
 		carry_flag := thread.RegFile().ReadFlagReg(instruction.CARRY)
 		result, carry, _ = this.alu.Subc(imm, ra, carry_flag)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -4595,7 +6328,8 @@ func (this *Logic) ExecuteZirc(instruction_ *instruction.Instruction) {
 		carry_flag := thread.RegFile().ReadFlagReg(instruction.CARRY)
 		result, carry, _ = this.alu.Subc(imm, ra, carry_flag)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -4632,40 +6366,161 @@ func (this *Logic) ExecuteZirci(instruction_ *instruction.Instruction) {
 		carry_flag := thread.RegFile().ReadFlagReg(instruction.CARRY)
 		result, carry, overflow = this.alu.Subc(imm, ra, carry_flag)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
+		panic(err)
+	}
+
+	thread.RegFile().ClearConditions()
+	this.SetSubNzCc(instruction_, imm, ra, result, carry, overflow)
+
+	if thread.RegFile().ReadConditionReg(instruction_.Condition()) {
+		thread.RegFile().WritePcReg(instruction_.Pc().Value())
+	} else {
+		thread.RegFile().IncrementPcReg()
+	}
+
+	this.SetFlags(instruction_, result, carry)
+}
+
+func (this *Logic) ExecuteSRirc(instruction_ *instruction.Instruction) {
+	if _, found := instruction_.RircOpCodes()[instruction_.OpCode()]; !found {
+		err := errors.New("op code is not a valid RIRC op code")
+		panic(err)
+	} else if instruction_.Suffix() != instruction.S_RIRC && instruction_.Suffix() != instruction.U_RIRC {
+		err := errors.New("suffix is not S_RIRC nor U_RIRC")
+		panic(err)
+	}
+
+	thread := this.scoreboard[instruction_]
+
+	imm := instruction_.Imm().Value()
+	ra := thread.RegFile().ReadSrcReg(instruction_.Ra(), word.SIGNED)
+
+	var result int64
+	var carry bool
+
+	op_code := instruction_.OpCode()
+	if op_code == instruction.SUB {
+		result, carry, _ = this.alu.Sub(imm, ra)
+	} else if op_code == instruction.SUBC {
+		carry_flag := thread.RegFile().ReadFlagReg(instruction.CARRY)
+		result, carry, _ = this.alu.Subc(imm, ra, carry_flag)
+	} else {
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
+		panic(err)
+	}
+
+	thread.RegFile().ClearConditions()
+	this.SetSubSetCc(instruction_, imm, ra, result)
+
+	// the odd register of the pair holds the low word, which the condition sets
+	if thread.RegFile().ReadConditionReg(instruction_.Condition()) {
+		thread.RegFile().WritePairReg(instruction_.Dc(), 0, 1)
+	} else {
+		thread.RegFile().WritePairReg(instruction_.Dc(), 0, 0)
+	}
+
+	thread.RegFile().IncrementPcReg()
+
+	this.SetFlags(instruction_, result, carry)
+}
+
+func (this *Logic) ExecuteSRirci(instruction_ *instruction.Instruction) {
+	if _, found := instruction_.RirciOpCodes()[instruction_.OpCode()]; !found {
+		err := errors.New("op code is not a valid RIRCI op code")
+		panic(err)
+	} else if instruction_.Suffix() != instruction.S_RIRCI && instruction_.Suffix() != instruction.U_RIRCI {
+		err := errors.New("suffix is not S_RIRCI nor U_RIRCI")
+		panic(err)
+	}
+
+	thread := this.scoreboard[instruction_]
+
+	imm := instruction_.Imm().Value()
+	ra := thread.RegFile().ReadSrcReg(instruction_.Ra(), word.SIGNED)
+
+	var result int64
+	var carry bool
+	var overflow bool
+
+	op_code := instruction_.OpCode()
+	if op_code == instruction.SUB {
+		result, carry, overflow = this.alu.Sub(imm, ra)
+	} else if op_code == instruction.SUBC {
+		carry_flag := thread.RegFile().ReadFlagReg(instruction.CARRY)
+		result, carry, overflow = this.alu.Subc(imm, ra, carry_flag)
+	} else {
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
+		panic(err)
+	}
+
+	thread.RegFile().ClearConditions()
+	this.SetSubNzCc(instruction_, imm, ra, result, carry, overflow)
+
+	var even int64
+	var odd int64
+	if instruction_.Suffix() == instruction.S_RIRCI {
+		even, odd = this.alu.SignedExtension(result)
+	} else if instruction_.Suffix() == instruction.U_RIRCI {
+		even, odd = this.alu.UnsignedExtension(result)
+	} else {
+		err := errors.New("suffix is not S_RIRCI nor U_RIRCI")
+		panic(err)
+	}
+
+	thread.RegFile().WritePairReg(instruction_.Dc(), even, odd)
+
+	if thread.RegFile().ReadConditionReg(instruction_.Condition()) {
+		thread.RegFile().WritePcReg(instruction_.Pc().Value())
+	} else {
+		thread.RegFile().IncrementPcReg()
+	}
+
+	this.SetFlags(instruction_, result, carry)
+}
+
+func (this *Logic) ExecuteR(instruction_ *instruction.Instruction) {
+	if _, found := instruction_.ROpCodes()[instruction_.OpCode()]; !found {
+		err := errors.New("op code is not a valid R op code")
+		panic(err)
+	} else if instruction_.Suffix() != instruction.R {
+		err := errors.New("suffix is not R")
 		panic(err)
 	}
 
+	thread := this.scoreboard[instruction_]
+
+	result := this.perf_counter.Read()
+
+	thread.RegFile().ClearConditions()
+	thread.RegFile().WriteGpReg(instruction_.Rc(), result)
+	thread.RegFile().IncrementPcReg()
+}
+
+func (this *Logic) ExecuteRci(instruction_ *instruction.Instruction) {
+	if _, found := instruction_.RciOpCodes()[instruction_.OpCode()]; !found {
+		err := errors.New("op code is not a valid RCI op code")
+		panic(err)
+	} else if instruction_.Suffix() != instruction.RCI {
+		err := errors.New("suffix is not RCI")
+		panic(err)
+	}
+
+	thread := this.scoreboard[instruction_]
+
+	result := this.perf_counter.Read()
+
 	thread.RegFile().ClearConditions()
-	this.SetSubNzCc(instruction_, imm, ra, result, carry, overflow)
+	thread.RegFile().WriteGpReg(instruction_.Rc(), result)
 
 	if thread.RegFile().ReadConditionReg(instruction_.Condition()) {
 		thread.RegFile().WritePcReg(instruction_.Pc().Value())
 	} else {
 		thread.RegFile().IncrementPcReg()
 	}
-
-	this.SetFlags(instruction_, result, carry)
-}
-
-func (this *Logic) ExecuteSRirc(instruction_ *instruction.Instruction) {
-	err := errors.New("ExecuteSRirc is not yet implemented")
-	panic(err)
-}
-
-func (this *Logic) ExecuteSRirci(instruction_ *instruction.Instruction) {
-	err := errors.New("ExecuteSRirci is not yet implemented")
-	panic(err)
-}
-
-func (this *Logic) ExecuteR(instruction_ *instruction.Instruction) {
-	err := errors.New("ExecuteR is not yet implemented")
-	panic(err)
-}
-
-func (this *Logic) ExecuteRci(instruction_ *instruction.Instruction) {
-	err := errors.New("ExecuteRci is not yet implemented")
-	panic(err)
 }
 
 func (this *Logic) ExecuteZ(instruction_ *instruction.Instruction) {
@@ -4684,18 +6539,75 @@ func (this *Logic) ExecuteZ(instruction_ *instruction.Instruction) {
 }
 
 func (this *Logic) ExecuteZci(instruction_ *instruction.Instruction) {
-	err := errors.New("ExecuteZci is not yet implemented")
+	err := new(misc.SimulationError)
+	err.Init(misc.UNSUPPORTED_INSTRUCTION, "ExecuteZci is not yet implemented")
 	panic(err)
 }
 
 func (this *Logic) ExecuteSR(instruction_ *instruction.Instruction) {
-	err := errors.New("ExecuteSR is not yet implemented")
-	panic(err)
+	if _, found := instruction_.ROpCodes()[instruction_.OpCode()]; !found {
+		err := errors.New("op code is not a valid R op code")
+		panic(err)
+	} else if instruction_.Suffix() != instruction.S_R && instruction_.Suffix() != instruction.U_R {
+		err := errors.New("suffix is not S_R nor U_R")
+		panic(err)
+	}
+
+	thread := this.scoreboard[instruction_]
+
+	result := this.perf_counter.Read()
+
+	thread.RegFile().ClearConditions()
+
+	var even int64
+	var odd int64
+	if instruction_.Suffix() == instruction.S_R {
+		even, odd = this.alu.SignedExtension(result)
+	} else if instruction_.Suffix() == instruction.U_R {
+		even, odd = this.alu.UnsignedExtension(result)
+	} else {
+		err := errors.New("suffix is not S_R nor U_R")
+		panic(err)
+	}
+
+	thread.RegFile().WritePairReg(instruction_.Dc(), even, odd)
+
+	thread.RegFile().IncrementPcReg()
 }
 
 func (this *Logic) ExecuteSRci(instruction_ *instruction.Instruction) {
-	err := errors.New("ExecuteSRci is not yet implemented")
-	panic(err)
+	if _, found := instruction_.RciOpCodes()[instruction_.OpCode()]; !found {
+		err := errors.New("op code is not a valid RCI op code")
+		panic(err)
+	} else if instruction_.Suffix() != instruction.S_RCI && instruction_.Suffix() != instruction.U_RCI {
+		err := errors.New("suffix is not S_RCI nor U_RCI")
+		panic(err)
+	}
+
+	thread := this.scoreboard[instruction_]
+
+	result := this.perf_counter.Read()
+
+	thread.RegFile().ClearConditions()
+
+	var even int64
+	var odd int64
+	if instruction_.Suffix() == instruction.S_RCI {
+		even, odd = this.alu.SignedExtension(result)
+	} else if instruction_.Suffix() == instruction.U_RCI {
+		even, odd = this.alu.UnsignedExtension(result)
+	} else {
+		err := errors.New("suffix is not S_RCI nor U_RCI")
+		panic(err)
+	}
+
+	thread.RegFile().WritePairReg(instruction_.Dc(), even, odd)
+
+	if thread.RegFile().ReadConditionReg(instruction_.Condition()) {
+		thread.RegFile().WritePcReg(instruction_.Pc().Value())
+	} else {
+		thread.RegFile().IncrementPcReg()
+	}
 }
 
 func (this *Logic) ExecuteCi(instruction_ *instruction.Instruction) {
@@ -4721,8 +6633,31 @@ func (this *Logic) ExecuteCi(instruction_ *instruction.Instruction) {
 }
 
 func (this *Logic) ExecuteI(instruction_ *instruction.Instruction) {
-	err := errors.New("ExecuteI is not yet implemented")
-	panic(err)
+	if _, found := instruction_.IOpCodes()[instruction_.OpCode()]; !found {
+		err := errors.New("op code is not a valid I op code")
+		panic(err)
+	} else if instruction_.Suffix() != instruction.I {
+		err := errors.New("suffix is not I")
+		panic(err)
+	}
+
+	imm := instruction_.Imm().Value()
+
+	op_code := instruction_.OpCode()
+	if op_code == instruction.FAULT {
+		thread := this.scoreboard[instruction_]
+		thread.RegFile().SetFaultCode(imm)
+
+		if exception, found := this.FaultException(imm); found {
+			this.Fault(instruction_, exception)
+		} else {
+			this.Stop(instruction_)
+		}
+	} else {
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
+		panic(err)
+	}
 }
 
 func (this *Logic) ExecuteDdci(instruction_ *instruction.Instruction) {
@@ -4740,7 +6675,8 @@ func (this *Logic) ExecuteDdci(instruction_ *instruction.Instruction) {
 	} else if _, is_swapd_ddci_op_code := instruction_.SwapdDdciOpCodes()[op_code]; is_swapd_ddci_op_code {
 		this.ExecuteSwapdDdciRri(instruction_)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 }
@@ -4813,6 +6749,10 @@ func (this *Logic) ExecuteErri(instruction_ *instruction.Instruction) {
 
 	address, _, _ := this.alu.Add(ra, off)
 
+	if !this.AccessWram(instruction_, address, this.AccessSize(instruction_.OpCode()), false) {
+		return
+	}
+
 	var result int64
 
 	op_code := instruction_.OpCode()
@@ -4827,7 +6767,8 @@ func (this *Logic) ExecuteErri(instruction_ *instruction.Instruction) {
 	} else if op_code == instruction.LW {
 		result = this.operand_collector.Lw(address)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -4837,8 +6778,60 @@ func (this *Logic) ExecuteErri(instruction_ *instruction.Instruction) {
 }
 
 func (this *Logic) ExecuteSErri(instruction_ *instruction.Instruction) {
-	err := errors.New("ExecuteSErri is not yet implemented")
-	panic(err)
+	if _, found := instruction_.ErriOpCodes()[instruction_.OpCode()]; !found {
+		err := errors.New("op code is not a valid ERRI op code")
+		panic(err)
+	} else if instruction_.Suffix() != instruction.S_ERRI && instruction_.Suffix() != instruction.U_ERRI {
+		err := errors.New("suffix is not S_ERRI nor U_ERRI")
+		panic(err)
+	}
+
+	thread := this.scoreboard[instruction_]
+
+	ra := thread.RegFile().ReadSrcReg(instruction_.Ra(), word.SIGNED)
+	off := instruction_.Off().Value()
+
+	address, _, _ := this.alu.Add(ra, off)
+
+	if !this.AccessWram(instruction_, address, this.AccessSize(instruction_.OpCode()), false) {
+		return
+	}
+
+	var result int64
+
+	op_code := instruction_.OpCode()
+	if op_code == instruction.LBS {
+		result = this.operand_collector.Lbs(address)
+	} else if op_code == instruction.LBU {
+		result = this.operand_collector.Lbu(address)
+	} else if op_code == instruction.LHS {
+		result = this.operand_collector.Lhs(address)
+	} else if op_code == instruction.LHU {
+		result = this.operand_collector.Lhu(address)
+	} else if op_code == instruction.LW {
+		result = this.operand_collector.Lw(address)
+	} else {
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
+		panic(err)
+	}
+
+	thread.RegFile().ClearConditions()
+
+	var even int64
+	var odd int64
+	if instruction_.Suffix() == instruction.S_ERRI {
+		even, odd = this.alu.SignedExtension(result)
+	} else if instruction_.Suffix() == instruction.U_ERRI {
+		even, odd = this.alu.UnsignedExtension(result)
+	} else {
+		err := errors.New("suffix is not S_ERRI nor U_ERRI")
+		panic(err)
+	}
+
+	thread.RegFile().WritePairReg(instruction_.Dc(), even, odd)
+
+	thread.RegFile().IncrementPcReg()
 }
 
 func (this *Logic) ExecuteEdri(instruction_ *instruction.Instruction) {
@@ -4857,6 +6850,10 @@ func (this *Logic) ExecuteEdri(instruction_ *instruction.Instruction) {
 
 	address, _, _ := this.alu.Add(ra, off)
 
+	if !this.AccessWram(instruction_, address, this.AccessSize(instruction_.OpCode()), false) {
+		return
+	}
+
 	var even int64
 	var odd int64
 
@@ -4864,7 +6861,8 @@ func (this *Logic) ExecuteEdri(instruction_ *instruction.Instruction) {
 	if op_code == instruction.LD {
 		even, odd = this.operand_collector.Ld(address)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -4890,6 +6888,10 @@ func (this *Logic) ExecuteErii(instruction_ *instruction.Instruction) {
 
 	address, _, _ := this.alu.Add(ra, off)
 
+	if !this.AccessWram(instruction_, address, this.AccessSize(instruction_.OpCode()), true) {
+		return
+	}
+
 	op_code := instruction_.OpCode()
 	if op_code == instruction.SB {
 		this.operand_collector.Sb(address, imm)
@@ -4910,7 +6912,8 @@ func (this *Logic) ExecuteErii(instruction_ *instruction.Instruction) {
 		even, odd := this.alu.UnsignedExtension(this.alu.Or(int64(thread.ThreadId()), imm))
 		this.operand_collector.Sd(address, even, odd)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -4935,6 +6938,10 @@ func (this *Logic) ExecuteErir(instruction_ *instruction.Instruction) {
 
 	address, _, _ := this.alu.Add(ra, off)
 
+	if !this.AccessWram(instruction_, address, this.AccessSize(instruction_.OpCode()), true) {
+		return
+	}
+
 	config_loader := new(misc.ConfigLoader)
 	config_loader.Init()
 
@@ -4950,7 +6957,8 @@ func (this *Logic) ExecuteErir(instruction_ *instruction.Instruction) {
 	} else if op_code == instruction.SW {
 		this.operand_collector.Sw(address, rb_word.BitSlice(word.UNSIGNED, 0, 32))
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -4975,11 +6983,16 @@ func (this *Logic) ExecuteErid(instruction_ *instruction.Instruction) {
 
 	address, _, _ := this.alu.Add(ra, off)
 
+	if !this.AccessWram(instruction_, address, this.AccessSize(instruction_.OpCode()), true) {
+		return
+	}
+
 	op_code := instruction_.OpCode()
 	if op_code == instruction.SD {
 		this.operand_collector.Sd(address, even, odd)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 
@@ -5004,7 +7017,8 @@ func (this *Logic) ExecuteDmaRri(instruction_ *instruction.Instruction) {
 	} else if _, is_sdma_dma_rri_op_code := instruction_.SdmaDmaRriOpCodes()[op_code]; is_sdma_dma_rri_op_code {
 		this.ExecuteSdmaDmaRri(instruction_)
 	} else {
-		err := errors.New("op code is not valid")
+		err := new(misc.SimulationError)
+		err.Init(misc.UNSUPPORTED_INSTRUCTION, "op code is not valid")
 		panic(err)
 	}
 }
@@ -5039,14 +7053,69 @@ func (this *Logic) ExecuteLdmaDmaRri(instruction_ *instruction.Instruction) {
 
 	size := (1 + this.alu.And(imm+this.alu.And(this.alu.Lsr(ra, 24), 255), 255)) * this.min_access_granularity
 
+	if !this.AccessDma(instruction_, wram_address, mram_address, size, true) {
+		return
+	}
+
 	this.dma.TransferFromMramToWram(wram_address, mram_address, size, instruction_)
 
+	if this.call_graph_profiler != nil {
+		this.call_graph_profiler.Transfer(thread.ThreadId(), size)
+	}
+
 	thread.RegFile().ClearConditions()
 }
 
+// ExecuteLdmaiDmaRri loads the instructions of an overlay from the MRAM into the IRAM. Its size is
+// encoded as that of ldma and sdma, in blocks of the minimum access granularity, and it loads whole
+// instructions from an instruction of the IRAM on, so that no instruction is left partly loaded.
 func (this *Logic) ExecuteLdmaiDmaRri(instruction_ *instruction.Instruction) {
-	err := errors.New("ExecuteLdmaiDmaRri is not yet implemented")
-	panic(err)
+	if _, found := instruction_.LdmaiDmaRriOpCodes()[instruction_.OpCode()]; !found {
+		err := errors.New("op code is not a valid ldmai DMA_RRI op code")
+		panic(err)
+	} else if instruction_.Suffix() != instruction.DMA_RRI {
+		err := errors.New("suffix is not DMA_RRI")
+		panic(err)
+	}
+
+	thread := this.scoreboard[instruction_]
+
+	ra := thread.RegFile().ReadSrcReg(instruction_.Ra(), word.SIGNED)
+	rb := thread.RegFile().ReadSrcReg(instruction_.Rb(), word.SIGNED)
+	imm := instruction_.Imm().Value()
+
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	iram_end_address := config_loader.IramOffset() + config_loader.IramSize()
+	iram_end_address_width := int(math.Floor(math.Log2(float64(iram_end_address))) + 1)
+	iram_mask := this.Pow2(iram_end_address_width) - 1
+	iram_address := this.alu.And(ra, iram_mask)
+
+	mram_end_address := config_loader.MramOffset() + config_loader.MramSize()
+	mram_end_address_width := int(math.Floor(math.Log2(float64(mram_end_address))) + 1)
+	mram_mask := this.Pow2(mram_end_address_width) - 1
+	mram_address := this.alu.And(rb, mram_mask)
+
+	iram_data_size := int64(config_loader.IramDataWidth() / 8)
+	size := (1 + this.alu.And(imm+this.alu.And(this.alu.Lsr(ra, 24), 255), 255)) * this.min_access_granularity
+
+	if iram_address < this.iram.Address() || iram_address+size > this.iram.Address()+this.iram.Size() ||
+		(iram_address-this.iram.Address())%iram_data_size != 0 || size%iram_data_size != 0 {
+		this.Fault(instruction_, instruction.DMA_FAULT)
+		return
+	} else if mram_address < config_loader.MramOffset() || mram_address+size > mram_end_address {
+		this.Fault(instruction_, instruction.DMA_FAULT)
+		return
+	}
+
+	this.dma.TransferFromMramToIram(iram_address, mram_address, size, instruction_)
+
+	if this.call_graph_profiler != nil {
+		this.call_graph_profiler.Transfer(thread.ThreadId(), size)
+	}
+
+	thread.RegFile().ClearConditions()
 }
 
 func (this *Logic) ExecuteSdmaDmaRri(instruction_ *instruction.Instruction) {
@@ -5079,8 +7148,16 @@ func (this *Logic) ExecuteSdmaDmaRri(instruction_ *instruction.Instruction) {
 
 	size := (1 + this.alu.And(imm+this.alu.And(this.alu.Lsr(ra, 24), 255), 255)) * this.min_access_granularity
 
+	if !this.AccessDma(instruction_, wram_address, mram_address, size, false) {
+		return
+	}
+
 	this.dma.TransferFromWramToMram(wram_address, mram_address, size, instruction_)
 
+	if this.call_graph_profiler != nil {
+		this.call_graph_profiler.Transfer(thread.ThreadId(), size)
+	}
+
 	thread.RegFile().ClearConditions()
 }
 
@@ -5127,7 +7204,7 @@ func (this *Logic) SetAddNzCc(
 		thread.RegFile().SetCondition(cc.NOV)
 	}
 
-	if result >= 0 {
+	if !this.IsNegative(result) {
 		thread.RegFile().SetCondition(cc.PL)
 	} else {
 		thread.RegFile().SetCondition(cc.MI)
@@ -5328,7 +7405,7 @@ func (this *Logic) SetExtSubSetCc(
 		thread.RegFile().SetCondition(cc.NOV)
 	}
 
-	if result >= 0 {
+	if !this.IsNegative(result) {
 		thread.RegFile().SetCondition(cc.PL)
 	} else {
 		thread.RegFile().SetCondition(cc.MI)
@@ -5405,11 +7482,11 @@ func (this *Logic) SetExtSubSetCc(
 		thread.RegFile().SetCondition(cc.XGTU)
 	}
 
-	if thread.RegFile().ReadFlagReg(instruction.ZERO) && (result < 0 || overflow) {
+	if thread.RegFile().ReadFlagReg(instruction.ZERO) && (this.IsNegative(result) || overflow) {
 		thread.RegFile().SetCondition(cc.XLES)
 	}
 
-	if !thread.RegFile().ReadFlagReg(instruction.ZERO) && (result >= 0 || overflow) {
+	if !thread.RegFile().ReadFlagReg(instruction.ZERO) && (!this.IsNegative(result) || overflow) {
 		thread.RegFile().SetCondition(cc.XGTS)
 	}
 }
@@ -5439,7 +7516,7 @@ func (this *Logic) SetImmShiftNzCc(
 		thread.RegFile().SetCondition(cc.O)
 	}
 
-	if result >= 0 {
+	if !this.IsNegative(result) {
 		thread.RegFile().SetCondition(cc.PL)
 	} else {
 		thread.RegFile().SetCondition(cc.MI)
@@ -5479,7 +7556,7 @@ func (this *Logic) SetLogNzCc(instruction_ *instruction.Instruction, operand1 in
 		thread.RegFile().SetCondition(cc.XNZ)
 	}
 
-	if result >= 0 {
+	if !this.IsNegative(result) {
 		thread.RegFile().SetCondition(cc.PL)
 	} else {
 		thread.RegFile().SetCondition(cc.MI)
@@ -5582,7 +7659,7 @@ func (this *Logic) SetSubNzCc(
 		thread.RegFile().SetCondition(cc.NOV)
 	}
 
-	if result >= 0 {
+	if !this.IsNegative(result) {
 		thread.RegFile().SetCondition(cc.PL)
 	} else {
 		thread.RegFile().SetCondition(cc.MI)
@@ -5653,11 +7730,11 @@ func (this *Logic) SetSubNzCc(
 		thread.RegFile().SetCondition(cc.XGTU)
 	}
 
-	if thread.RegFile().ReadFlagReg(instruction.ZERO) && (result < 0 || overflow) {
+	if thread.RegFile().ReadFlagReg(instruction.ZERO) && (this.IsNegative(result) || overflow) {
 		thread.RegFile().SetCondition(cc.XLES)
 	}
 
-	if !thread.RegFile().ReadFlagReg(instruction.ZERO) && (result >= 0 || overflow) {
+	if !thread.RegFile().ReadFlagReg(instruction.ZERO) && (!this.IsNegative(result) || overflow) {
 		thread.RegFile().SetCondition(cc.XGTS)
 	}
 }
@@ -5689,6 +7766,193 @@ func (this *Logic) SetSubSetCc(
 	}
 }
 
+// AccessWram passes a load or a store of the instruction to the race detector and the sanitizer, if
+// any. An access out of the WRAM faults the DPU instead, in which case it returns false.
+func (this *Logic) AccessWram(
+	instruction_ *instruction.Instruction,
+	address int64,
+	size int64,
+	is_write bool,
+) bool {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	if address < config_loader.WramOffset() ||
+		address+size > config_loader.WramOffset()+config_loader.WramSize() {
+		this.Fault(instruction_, instruction.MEMORY_FAULT)
+		return false
+	}
+
+	thread := this.scoreboard[instruction_]
+	pc := this.InstructionPc(instruction_)
+
+	if this.race_detector != nil {
+		this.race_detector.Access(thread.ThreadId(), pc, address, size, is_write)
+	}
+
+	if this.sanitizer != nil {
+		this.sanitizer.AccessWram(thread.ThreadId(), pc, address, size, is_write)
+	}
+
+	return true
+}
+
+// AccessDma passes a DMA command of the instruction to the race detector and the sanitizer, if any.
+// An ldma writes the WRAM, whereas an sdma reads it. A DMA command out of the WRAM or the MRAM faults
+// the DPU instead, in which case it returns false.
+func (this *Logic) AccessDma(
+	instruction_ *instruction.Instruction,
+	wram_address int64,
+	mram_address int64,
+	size int64,
+	is_ldma bool,
+) bool {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	if wram_address < config_loader.WramOffset() ||
+		wram_address+size > config_loader.WramOffset()+config_loader.WramSize() {
+		this.Fault(instruction_, instruction.DMA_FAULT)
+		return false
+	} else if mram_address < config_loader.MramOffset() ||
+		mram_address+size > config_loader.MramOffset()+config_loader.MramSize() {
+		this.Fault(instruction_, instruction.DMA_FAULT)
+		return false
+	}
+
+	thread := this.scoreboard[instruction_]
+	pc := this.InstructionPc(instruction_)
+
+	if this.race_detector != nil {
+		this.race_detector.Access(thread.ThreadId(), pc, wram_address, size, is_ldma)
+	}
+
+	if this.sanitizer != nil {
+		this.sanitizer.AccessDma(thread.ThreadId(), pc, wram_address, mram_address, size, is_ldma)
+	}
+
+	return true
+}
+
+func (this *Logic) AccessAtomic(instruction_ *instruction.Instruction, address int64) {
+	if this.sanitizer != nil {
+		thread := this.scoreboard[instruction_]
+		this.sanitizer.AccessAtomic(thread.ThreadId(), this.InstructionPc(instruction_), address)
+	}
+}
+
+// Fault stops the DPU as the hardware does once a tasklet faults. The exception register of the
+// tasklet holds the cause and its PC stays at the faulting instruction, while every tasklet of the
+// DPU stops, so that the host finds the DPU faulted once the DMA commands in flight have completed.
+func (this *Logic) Fault(instruction_ *instruction.Instruction, exception instruction.Exception) {
+	thread := this.scoreboard[instruction_]
+	thread.RegFile().SetException(exception)
+
+	this.Stop(instruction_)
+}
+
+// Stop stops the DPU at the faulting instruction, whose cause the exception register of its tasklet
+// already holds.
+func (this *Logic) Stop(instruction_ *instruction.Instruction) {
+	thread := this.scoreboard[instruction_]
+	thread.RegFile().WritePcReg(this.InstructionPc(instruction_))
+
+	for _, thread_ := range this.thread_scheduler.Threads() {
+		this.thread_scheduler.Stop(thread_.ThreadId())
+	}
+
+	if instruction_.Suffix() == instruction.DMA_RRI {
+		this.Unwait(instruction_)
+	}
+}
+
+// FaultException maps the code of a fault instruction to its exception, as the fault codes of
+// dpufault.h in the SDK are defined. Any other code, e.g., of __builtin_fault_i, has no exception.
+func (this *Logic) FaultException(code int64) (instruction.Exception, bool) {
+	if code == 1 {
+		return instruction.HEAP_FULL, true
+	} else if code == 2 {
+		return instruction.DIVISION_BY_ZERO, true
+	} else if code == 3 {
+		return instruction.ASSERT, true
+	} else if code == 4 {
+		return instruction.HALT, true
+	} else if code == 5 {
+		return instruction.PRINT_OVERFLOW, true
+	} else if code == 6 {
+		return instruction.ALREADY_PROFILING, true
+	} else if code == 7 {
+		return instruction.NOT_PROFILING, true
+	} else {
+		return instruction.MEMORY_FAULT, false
+	}
+}
+
+// Unwait removes a DMA instruction that will not hand a DMA command to the DMA from the wait queue.
+func (this *Logic) Unwait(instruction_ *instruction.Instruction) {
+	for i := 0; this.wait_q.CanPop(i + 1); i++ {
+		waiting_instruction, _ := this.wait_q.Front(i)
+
+		if waiting_instruction == instruction_ {
+			this.wait_q.Remove(i)
+			break
+		}
+	}
+
+	delete(this.scoreboard, instruction_)
+}
+
+func (this *Logic) InstructionPc(instruction_ *instruction.Instruction) int64 {
+	thread := this.scoreboard[instruction_]
+	pc := thread.RegFile().ReadPcReg()
+
+	if instruction_.Suffix() == instruction.DMA_RRI {
+		config_loader := new(misc.ConfigLoader)
+		config_loader.Init()
+
+		// a DMA instruction has already moved the PC of its thread past itself
+		pc -= int64(config_loader.IramDataWidth() / 8)
+	}
+
+	return pc
+}
+
+// Recover attaches the instruction, its tasklet, and the DPU to what the instruction raises, with
+// the PC that the instruction has been issued at, since the instruction may have moved it already.
+func (this *Logic) Recover(instruction_ *instruction.Instruction, thread *Thread, pc int64) {
+	if recovered := recover(); recovered != nil {
+		simulation_error := new(misc.SimulationError)
+		simulation_error.InitRecovered(recovered, misc.INTERNAL_ERROR)
+		simulation_error.SetComponent("Logic")
+		simulation_error.SetDpu(this.channel_id, this.rank_id, this.dpu_id)
+		simulation_error.SetInstruction(thread.ThreadId(), pc, instruction_.Stringify())
+		panic(simulation_error)
+	}
+}
+
+func (this *Logic) DetectSignal(thread *Thread, target_thread_id int) {
+	if this.race_detector != nil {
+		this.race_detector.Signal(thread.ThreadId(), target_thread_id)
+	}
+}
+
+func (this *Logic) AccessSize(op_code instruction.OpCode) int64 {
+	if op_code == instruction.LBS || op_code == instruction.LBU || op_code == instruction.SB ||
+		op_code == instruction.SB_ID {
+		return 1
+	} else if op_code == instruction.LHS || op_code == instruction.LHU || op_code == instruction.SH ||
+		op_code == instruction.SH_ID {
+		return 2
+	} else if op_code == instruction.LW || op_code == instruction.SW || op_code == instruction.SW_ID {
+		return 4
+	} else if op_code == instruction.LD || op_code == instruction.SD || op_code == instruction.SD_ID {
+		return 8
+	} else {
+		err := errors.New("op code is not a WRAM access")
+		panic(err)
+	}
+}
+
 func (this *Logic) SetFlags(instruction_ *instruction.Instruction, result int64, carry bool) {
 	thread := this.scoreboard[instruction_]
 
@@ -5705,6 +7969,16 @@ func (this *Logic) SetFlags(instruction_ *instruction.Instruction, result int64,
 	}
 }
 
+// IsNegative is whether the result of the ALU, which it returns as an unsigned value of the MRAM
+// data width, is negative as a signed value.
+func (this *Logic) IsNegative(result int64) bool {
+	result_word := new(word.Word)
+	result_word.Init(this.mram_data_width)
+	result_word.SetValue(result)
+
+	return result_word.SignBit()
+}
+
 func (this *Logic) Pow2(exponent int) int64 {
 	if exponent < 0 {
 		err := errors.New("exponent < 0")
@@ -0,0 +1,86 @@
+package logic
+
+import (
+	"uPIMulator/src/misc"
+)
+
+type PerfCounterMode int
+
+const (
+	COUNT_SAME PerfCounterMode = iota
+	COUNT_CYCLES
+	COUNT_INSTRUCTIONS
+	COUNT_NOTHING
+)
+
+// The time instruction reads the performance counter without its low bits, which perfcounter_get
+// of the SDK shifts back in.
+const perf_counter_imprecision = 4
+
+// A PerfCounter is the performance counter of a DPU, which counts either the logic cycles or the
+// issued instructions of the DPU. It counts the cycles until time_cfg configures it otherwise.
+type PerfCounter struct {
+	mode  PerfCounterMode
+	value int64
+}
+
+func (this *PerfCounter) Init() {
+	this.mode = COUNT_CYCLES
+	this.value = 0
+}
+
+func (this *PerfCounter) Fini() {
+}
+
+func (this *PerfCounter) Mode() PerfCounterMode {
+	return this.mode
+}
+
+func (this *PerfCounter) Value() int64 {
+	return this.value
+}
+
+// Read is the value that the time instruction writes to its destination register.
+func (this *PerfCounter) Read() int64 {
+	return this.value >> perf_counter_imprecision
+}
+
+// Config configures the performance counter as time_cfg does and returns what the counter has read
+// before. The lowest bit of the config resets the value and the two bits above it select the mode,
+// where COUNT_SAME keeps the current mode.
+func (this *PerfCounter) Config(config int64) int64 {
+	value := this.Read()
+
+	if config&1 != 0 {
+		this.value = 0
+	}
+
+	mode := PerfCounterMode((config >> 1) & 3)
+	if mode != COUNT_SAME {
+		this.mode = mode
+	}
+
+	return value
+}
+
+func (this *PerfCounter) Cycle(num_cycles int64) {
+	if this.mode == COUNT_CYCLES {
+		this.value += num_cycles
+	}
+}
+
+func (this *PerfCounter) Issue() {
+	if this.mode == COUNT_INSTRUCTIONS {
+		this.value++
+	}
+}
+
+func (this *PerfCounter) Checkpoint(checkpoint_writer *misc.CheckpointWriter) {
+	checkpoint_writer.WriteInt(int64(this.mode))
+	checkpoint_writer.WriteInt(this.value)
+}
+
+func (this *PerfCounter) Restore(checkpoint_reader *misc.CheckpointReader) {
+	this.mode = PerfCounterMode(checkpoint_reader.ReadInt())
+	this.value = checkpoint_reader.ReadInt()
+}
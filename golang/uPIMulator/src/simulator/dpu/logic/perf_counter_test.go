@@ -0,0 +1,46 @@
+package logic
+
+import (
+	"testing"
+)
+
+func TestPerfCounter(t *testing.T) {
+	perf_counter := new(PerfCounter)
+	perf_counter.Init()
+
+	perf_counter.Cycle(100)
+	perf_counter.Issue()
+	if perf_counter.Value() != 100 {
+		t.Fatalf("counts %d cycles by default, expected 100", perf_counter.Value())
+	} else if perf_counter.Read() != 100>>4 {
+		t.Fatalf("reads %d, expected %d", perf_counter.Read(), 100>>4)
+	}
+
+	// reset and count the instructions
+	if value := perf_counter.Config(1 | int64(COUNT_INSTRUCTIONS)<<1); value != 100>>4 {
+		t.Fatalf("config returns %d, expected %d", value, 100>>4)
+	}
+
+	perf_counter.Cycle(100)
+	for i := 0; i < 40; i++ {
+		perf_counter.Issue()
+	}
+	if perf_counter.Mode() != COUNT_INSTRUCTIONS || perf_counter.Value() != 40 {
+		t.Fatalf("counts %d in mode %d, expected 40 instructions", perf_counter.Value(), perf_counter.Mode())
+	}
+
+	// keep the mode without a reset
+	perf_counter.Config(int64(COUNT_SAME) << 1)
+	perf_counter.Issue()
+	if perf_counter.Mode() != COUNT_INSTRUCTIONS || perf_counter.Value() != 41 {
+		t.Fatalf("counts %d in mode %d, expected 41 instructions", perf_counter.Value(), perf_counter.Mode())
+	}
+
+	// stop counting
+	perf_counter.Config(int64(COUNT_NOTHING) << 1)
+	perf_counter.Cycle(100)
+	perf_counter.Issue()
+	if perf_counter.Value() != 41 {
+		t.Fatalf("counts %d while stopped, expected 41", perf_counter.Value())
+	}
+}
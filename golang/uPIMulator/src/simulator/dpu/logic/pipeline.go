@@ -53,6 +53,10 @@ func (this *Pipeline) Fini() {
 	this.ready_q.Fini()
 }
 
+func (this *Pipeline) NumPipelineStages() int {
+	return this.num_pipeline_stages
+}
+
 func (this *Pipeline) IsEmpty() bool {
 	return this.IsInputQEmpty() && this.IsWaitQEmpty() && this.IsReadyQEmpty()
 }
@@ -145,3 +149,15 @@ func (this *Pipeline) ServiceWaitQ() {
 		this.ready_q.Push(instruction_)
 	}
 }
+
+func (this *Pipeline) Checkpoint(checkpoint_writer *misc.CheckpointWriter) {
+	this.input_q.Checkpoint(checkpoint_writer)
+	this.wait_q.Checkpoint(checkpoint_writer)
+	this.ready_q.Checkpoint(checkpoint_writer)
+}
+
+func (this *Pipeline) Restore(checkpoint_reader *misc.CheckpointReader) {
+	this.input_q.Restore(checkpoint_reader)
+	this.wait_q.Restore(checkpoint_reader)
+	this.ready_q.Restore(checkpoint_reader)
+}
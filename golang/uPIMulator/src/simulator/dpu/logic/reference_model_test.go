@@ -0,0 +1,480 @@
+package logic
+
+import (
+	"fmt"
+	"math/bits"
+	"slices"
+	"strconv"
+	"testing"
+	"uPIMulator/src/linker/kernel/instruction"
+	"uPIMulator/src/linker/kernel/instruction/cc"
+)
+
+// A ReferenceModel models the ALU instructions from the two's complement semantics of their op
+// codes on 32-bit registers, independently of the Alu and of the Set*Cc functions of the logic, so
+// that fuzzing the logic against it in the RRRCI form and checking it in every other form finds
+// where the two disagree. The carry of a subtraction is its borrow, and subc and rsubc subtract the
+// carry flag as a borrow.
+//
+// A ReferenceModel only models the conditions whose semantics it is sure of, so that the NC5 to
+// NC14 conditions of additions and the extended comparisons, e.g., XLEU, are not checked.
+type ReferenceModel struct{}
+
+// A ReferenceResult is what the reference model expects an instruction to write to its destination
+// register and to the carry flag, and the conditions that it expects the instruction to set.
+type ReferenceResult struct {
+	result     uint32
+	carry      bool
+	conditions map[cc.Condition]bool
+}
+
+func (this *ReferenceModel) OpCodes() []string {
+	return []string{
+		"add",
+		"addc",
+		"sub",
+		"subc",
+		"rsub",
+		"rsubc",
+		"and",
+		"or",
+		"xor",
+		"nand",
+		"nor",
+		"nxor",
+		"lsl",
+		"lsr",
+		"asr",
+		"rol",
+		"ror",
+		"mul_ul_ul",
+		"mul_uh_uh",
+		"mul_sl_sl",
+		"mul_sh_sh",
+	}
+}
+
+func (this *ReferenceModel) Execute(
+	op_code string,
+	ra uint32,
+	rb uint32,
+	zero bool,
+	carry bool,
+) *ReferenceResult {
+	reference_result := new(ReferenceResult)
+	reference_result.conditions = make(map[cc.Condition]bool, 0)
+
+	if op_code == "add" || op_code == "addc" {
+		carry_in := uint64(0)
+		if op_code == "addc" && carry {
+			carry_in = 1
+		}
+
+		sum := uint64(ra) + uint64(rb) + carry_in
+		signed_sum := int64(int32(ra)) + int64(int32(rb)) + int64(carry_in)
+
+		reference_result.result = uint32(sum)
+		reference_result.carry = sum > 0xffffffff
+
+		this.SetResultConditions(reference_result, zero)
+		this.SetSignConditions(reference_result)
+		this.SetSourceConditions(reference_result, ra)
+		this.SetCarryConditions(reference_result, signed_sum)
+	} else if op_code == "sub" || op_code == "subc" || op_code == "rsub" || op_code == "rsubc" {
+		minuend, subtrahend := ra, rb
+		if op_code == "rsub" || op_code == "rsubc" {
+			minuend, subtrahend = rb, ra
+		}
+
+		borrow_in := uint64(0)
+		if (op_code == "subc" || op_code == "rsubc") && carry {
+			borrow_in = 1
+		}
+
+		signed_difference := int64(int32(minuend)) - int64(int32(subtrahend)) - int64(borrow_in)
+
+		reference_result.result = uint32(uint64(minuend) - uint64(subtrahend) - borrow_in)
+		reference_result.carry = uint64(minuend) < uint64(subtrahend)+borrow_in
+
+		this.SetResultConditions(reference_result, zero)
+		this.SetSignConditions(reference_result)
+		this.SetCarryConditions(reference_result, signed_difference)
+
+		reference_result.conditions[cc.EQ] = ra == rb
+		reference_result.conditions[cc.NEQ] = ra != rb
+		reference_result.conditions[cc.SPL] = int32(ra) >= 0
+		reference_result.conditions[cc.SMI] = int32(ra) < 0
+
+		// the operands that rsub compares are not settled, so that only sub and subc are checked
+		if op_code == "sub" || op_code == "subc" {
+			this.SetComparisonConditions(reference_result, ra, rb)
+		}
+	} else if op_code == "and" || op_code == "or" || op_code == "xor" || op_code == "nand" ||
+		op_code == "nor" || op_code == "nxor" || op_code == "lsl" || op_code == "lsr" ||
+		op_code == "asr" || op_code == "rol" || op_code == "ror" {
+		shift := int(rb % 32)
+
+		if op_code == "and" {
+			reference_result.result = ra & rb
+		} else if op_code == "or" {
+			reference_result.result = ra | rb
+		} else if op_code == "xor" {
+			reference_result.result = ra ^ rb
+		} else if op_code == "nand" {
+			reference_result.result = ^(ra & rb)
+		} else if op_code == "nor" {
+			reference_result.result = ^(ra | rb)
+		} else if op_code == "nxor" {
+			reference_result.result = ^(ra ^ rb)
+		} else if op_code == "lsl" {
+			reference_result.result = ra << shift
+		} else if op_code == "lsr" {
+			reference_result.result = ra >> shift
+		} else if op_code == "asr" {
+			reference_result.result = uint32(int32(ra) >> shift)
+		} else if op_code == "rol" {
+			reference_result.result = bits.RotateLeft32(ra, shift)
+		} else {
+			reference_result.result = bits.RotateLeft32(ra, -shift)
+		}
+
+		this.SetResultConditions(reference_result, zero)
+		this.SetSignConditions(reference_result)
+		this.SetSourceConditions(reference_result, ra)
+	} else {
+		is_signed := op_code == "mul_sl_sl" || op_code == "mul_sh_sh"
+		shift := 0
+		if op_code == "mul_uh_uh" || op_code == "mul_sh_sh" {
+			shift = 8
+		}
+
+		if is_signed {
+			reference_result.result = uint32(int32(int8(ra>>shift)) * int32(int8(rb>>shift)))
+		} else {
+			reference_result.result = uint32(ra>>shift&0xff) * uint32(rb>>shift&0xff)
+
+			// the sign of a small product is not settled, so that only unsigned products are checked
+			reference_result.conditions[cc.SMALL] = reference_result.result < 256
+			reference_result.conditions[cc.LARGE] = reference_result.result >= 256
+		}
+
+		this.SetResultConditions(reference_result, zero)
+		this.SetSourceConditions(reference_result, ra)
+	}
+
+	return reference_result
+}
+
+// SetResultConditions sets the zero conditions of the result, of which the extended ones also
+// take the zero flag that the previous instruction has left.
+func (this *ReferenceModel) SetResultConditions(reference_result *ReferenceResult, zero bool) {
+	reference_result.conditions[cc.Z] = reference_result.result == 0
+	reference_result.conditions[cc.NZ] = reference_result.result != 0
+	reference_result.conditions[cc.XZ] = reference_result.result == 0 && zero
+	reference_result.conditions[cc.XNZ] = !(reference_result.result == 0 && zero)
+}
+
+func (this *ReferenceModel) SetSignConditions(reference_result *ReferenceResult) {
+	reference_result.conditions[cc.PL] = int32(reference_result.result) >= 0
+	reference_result.conditions[cc.MI] = int32(reference_result.result) < 0
+}
+
+func (this *ReferenceModel) SetSourceConditions(reference_result *ReferenceResult, ra uint32) {
+	reference_result.conditions[cc.SZ] = ra == 0
+	reference_result.conditions[cc.SNZ] = ra != 0
+	reference_result.conditions[cc.SPL] = int32(ra) >= 0
+	reference_result.conditions[cc.SMI] = int32(ra) < 0
+}
+
+// SetCarryConditions sets the carry conditions and the overflow conditions, where the result
+// overflows if the exact signed result does not fit in 32 bits.
+func (this *ReferenceModel) SetCarryConditions(reference_result *ReferenceResult, signed_result int64) {
+	overflow := signed_result != int64(int32(reference_result.result))
+
+	reference_result.conditions[cc.C] = reference_result.carry
+	reference_result.conditions[cc.NC] = !reference_result.carry
+	reference_result.conditions[cc.OV] = overflow
+	reference_result.conditions[cc.NOV] = !overflow
+}
+
+func (this *ReferenceModel) SetComparisonConditions(
+	reference_result *ReferenceResult,
+	ra uint32,
+	rb uint32,
+) {
+	reference_result.conditions[cc.LTU] = ra < rb
+	reference_result.conditions[cc.LEU] = ra <= rb
+	reference_result.conditions[cc.GTU] = ra > rb
+	reference_result.conditions[cc.GEU] = ra >= rb
+	reference_result.conditions[cc.LTS] = int32(ra) < int32(rb)
+	reference_result.conditions[cc.LES] = int32(ra) <= int32(rb)
+	reference_result.conditions[cc.GTS] = int32(ra) > int32(rb)
+	reference_result.conditions[cc.GES] = int32(ra) >= int32(rb)
+}
+
+// UpdatesCarry is whether the op code leaves its carry in the carry flag, which the others clear.
+func (this *ReferenceModel) UpdatesCarry(op_code string) bool {
+	return op_code == "add" || op_code == "addc" || op_code == "sub" || op_code == "subc" ||
+		op_code == "rsub" || op_code == "rsubc"
+}
+
+// A ReferenceForm is a form of the ALU instructions that the reference model generates conformance
+// cases of: its destination, i.e., a register, zero, or a signed or unsigned pair, the order of its
+// operands, and whether it sets its condition to the destination or branches on it.
+type ReferenceForm struct {
+	destination string
+	operands    string
+	condition   string
+	op_codes    map[instruction.OpCode]bool
+}
+
+func (this *ReferenceModel) OpCode(op_code string) instruction.OpCode {
+	op_codes := map[string]instruction.OpCode{
+		"add":       instruction.ADD,
+		"addc":      instruction.ADDC,
+		"sub":       instruction.SUB,
+		"subc":      instruction.SUBC,
+		"rsub":      instruction.RSUB,
+		"rsubc":     instruction.RSUBC,
+		"and":       instruction.AND,
+		"or":        instruction.OR,
+		"xor":       instruction.XOR,
+		"nand":      instruction.NAND,
+		"nor":       instruction.NOR,
+		"nxor":      instruction.NXOR,
+		"lsl":       instruction.LSL,
+		"lsr":       instruction.LSR,
+		"asr":       instruction.ASR,
+		"rol":       instruction.ROL,
+		"ror":       instruction.ROR,
+		"mul_ul_ul": instruction.MUL_UL_UL,
+		"mul_uh_uh": instruction.MUL_UH_UH,
+		"mul_sl_sl": instruction.MUL_SL_SL,
+		"mul_sh_sh": instruction.MUL_SH_SH,
+	}
+
+	return op_codes[op_code]
+}
+
+// Forms are the forms of the ALU instructions, each with the op codes that the linker allows in it.
+func (this *ReferenceModel) Forms() []*ReferenceForm {
+	instruction_ := new(instruction.Instruction)
+
+	forms := make([]*ReferenceForm, 0)
+	for _, destination := range []string{"r", "zero", "s", "u"} {
+		forms = append(
+			forms,
+			&ReferenceForm{destination, "rr", "none", instruction_.RrrOpCodes()},
+			&ReferenceForm{destination, "rr", "set", instruction_.RrrcOpCodes()},
+			&ReferenceForm{destination, "rr", "branch", instruction_.RrrciOpCodes()},
+			&ReferenceForm{destination, "ri", "none", instruction_.RriOpCodes()},
+			&ReferenceForm{destination, "ri", "set", instruction_.RricOpCodes()},
+			&ReferenceForm{destination, "ri", "branch", instruction_.RriciOpCodes()},
+			&ReferenceForm{destination, "ri", "false", instruction_.RrifOpCodes()},
+			&ReferenceForm{destination, "ir", "set", instruction_.RircOpCodes()},
+			&ReferenceForm{destination, "ir", "branch", instruction_.RirciOpCodes()},
+		)
+	}
+	forms = append(
+		forms,
+		&ReferenceForm{"r", "ir", "none", instruction_.RirOpCodes()},
+		&ReferenceForm{"zero", "ir", "none", instruction_.RirOpCodes()},
+	)
+
+	return forms
+}
+
+// ConformanceCases are the conformance cases of each op code of the reference model in each form
+// that the linker allows it in, of which the expected state is that of the reference model. The
+// cases take turns over the operands, the conditions, and the zero and carry flags, so that every
+// form of an op code is executed with a few of each.
+func (this *ReferenceModel) ConformanceCases() []*ConformanceCase {
+	registers := []uint32{3, 0xffffffff, 0x7fffffff, 0x80000000, 5, 0x80, 0}
+	immediates := []int64{1, 5, -1, 127, -128, 0}
+	shifts := []int64{0, 1, 4, 31}
+
+	set_conditions := []string{"z", "nz", "xz", "xnz"}
+	branch_conditions := []string{"z", "nz", "xz", "xnz", "spl", "smi"}
+
+	conformance_cases := make([]*ConformanceCase, 0)
+	index := 0
+	for _, form := range this.Forms() {
+		for _, op_code := range this.OpCodes() {
+			if _, found := form.op_codes[this.OpCode(op_code)]; !found {
+				continue
+			}
+
+			for i := 0; i < 3; i++ {
+				index++
+
+				ra := registers[index%len(registers)]
+				rb := registers[(index*3+1)%len(registers)]
+				zero := index%2 == 0
+				carry := index%3 == 0
+
+				operand := "r1"
+				if form.operands != "rr" {
+					imm := immediates[index%len(immediates)]
+					if op_code == "lsl" || op_code == "lsr" || op_code == "asr" || op_code == "rol" ||
+						op_code == "ror" {
+						imm = shifts[index%len(shifts)]
+					}
+
+					rb = uint32(imm)
+					operand = strconv.FormatInt(imm, 10)
+				}
+
+				reference_result := this.Execute(op_code, ra, rb, zero, carry)
+				if form.operands == "ir" {
+					reference_result = this.Execute(op_code, rb, ra, zero, carry)
+				}
+
+				assembly := op_code
+				if form.destination == "s" || form.destination == "u" {
+					assembly += "." + form.destination + " d2"
+				} else if form.destination == "zero" {
+					assembly += " zero"
+				} else {
+					assembly += " r2"
+				}
+
+				if form.operands == "ir" {
+					assembly += ", " + operand + ", r0"
+				} else {
+					assembly += ", r0, " + operand
+				}
+
+				expected := ConformanceState{
+					Zero:  reference_result.result == 0,
+					Carry: this.UpdatesCarry(op_code) && reference_result.carry,
+				}
+
+				value := int64(reference_result.result)
+				if form.condition == "set" {
+					condition := set_conditions[index%len(set_conditions)]
+					assembly += ", " + condition
+
+					value = 0
+					if reference_result.conditions[this.Condition(condition)] {
+						value = 1
+					}
+				} else if form.condition == "branch" {
+					// the sources of the sign conditions of the RIR forms are not settled
+					condition := branch_conditions[index%len(branch_conditions)]
+					if form.operands == "ir" {
+						condition = set_conditions[index%len(set_conditions)]
+					}
+					assembly += ", " + condition + ", target"
+
+					expected.Taken = reference_result.conditions[this.Condition(condition)]
+				} else if form.condition == "false" {
+					assembly += ", false"
+				}
+
+				if form.destination == "r" {
+					expected.Regs = map[string]int64{"r2": value}
+				} else if form.destination == "s" {
+					expected.Regs = map[string]int64{"r2": int64(int32(value) >> 31), "r3": value}
+				} else if form.destination == "u" {
+					expected.Regs = map[string]int64{"r2": 0, "r3": value}
+				}
+
+				conformance_case := new(ConformanceCase)
+				conformance_case.Name = fmt.Sprintf("%s (r0: %#x, r1: %#x)", assembly, ra, rb)
+				conformance_case.Assembly = assembly
+				conformance_case.Regs = map[string]int64{"r0": int64(ra), "r1": int64(rb), "r2": 9, "r3": 9}
+				conformance_case.Zero = zero
+				conformance_case.Carry = carry
+				conformance_case.Expected = expected
+
+				conformance_cases = append(conformance_cases, conformance_case)
+			}
+		}
+	}
+
+	return conformance_cases
+}
+
+func (this *ReferenceModel) Condition(condition string) cc.Condition {
+	conditions := map[string]cc.Condition{
+		"z":   cc.Z,
+		"nz":  cc.NZ,
+		"xz":  cc.XZ,
+		"xnz": cc.XNZ,
+		"spl": cc.SPL,
+		"smi": cc.SMI,
+	}
+
+	return conditions[condition]
+}
+
+// FuzzConditionCodes executes an ALU instruction of the RRRCI form, whose op code and condition the
+// fuzzer chooses, and compares its destination register, its flags, the conditions that it sets, and
+// whether it branches with the reference model. It is run against the seeds by go test and is fuzzed
+// with, e.g.,
+//
+//	go test ./src/simulator/dpu/logic -run '^$' -fuzz FuzzConditionCodes -fuzztime 1m
+func FuzzConditionCodes(f *testing.F) {
+	reference_model := new(ReferenceModel)
+
+	seeds := []uint32{0, 1, 2, 0x7f, 0x80, 0xff, 0x100, 0x7fffffff, 0x80000000, 0xfffffffe, 0xffffffff}
+	for op_code := range reference_model.OpCodes() {
+		for i, ra := range seeds {
+			rb := seeds[(i*7+op_code)%len(seeds)]
+			f.Add(uint8(op_code), ra, rb, i%2 == 0, i%3 == 0, uint8(i))
+			f.Add(uint8(op_code), ra, ra, i%2 == 1, i%3 == 1, uint8(i+op_code))
+		}
+	}
+
+	runner := new(ConformanceRunner)
+	runner.Init()
+
+	f.Fuzz(func(t *testing.T, op_code_index uint8, ra uint32, rb uint32, zero bool, carry bool, condition_index uint8) {
+		op_code := reference_model.OpCodes()[int(op_code_index)%len(reference_model.OpCodes())]
+		reference_result := reference_model.Execute(op_code, ra, rb, zero, carry)
+
+		conditions := make([]cc.Condition, 0)
+		for condition := range reference_result.conditions {
+			conditions = append(conditions, condition)
+		}
+		slices.Sort(conditions)
+
+		condition := conditions[int(condition_index)%len(conditions)]
+
+		conformance_case := new(ConformanceCase)
+		conformance_case.Name = fmt.Sprintf("%s %#x, %#x (zero: %t, carry: %t)", op_code, ra, rb, zero, carry)
+		conformance_case.Assembly = fmt.Sprintf(
+			"%s r2, r0, r1, %s, target",
+			op_code,
+			new(instruction.Instruction).StringifyCondition(condition),
+		)
+		conformance_case.Regs = map[string]int64{"r0": int64(ra), "r1": int64(rb)}
+		conformance_case.Zero = zero
+		conformance_case.Carry = carry
+		conformance_case.Expected = ConformanceState{
+			Regs:  map[string]int64{"r2": int64(reference_result.result)},
+			Zero:  reference_result.result == 0,
+			Carry: reference_model.UpdatesCarry(op_code) && reference_result.carry,
+			Taken: reference_result.conditions[condition],
+		}
+
+		if recovered := runner.Execute(t, conformance_case); recovered != nil {
+			t.Fatalf("%s: %s raises %v", conformance_case.Name, conformance_case.Assembly, recovered)
+		}
+
+		runner.Check(t, conformance_case)
+
+		for _, condition := range conditions {
+			is_set := runner.Thread().RegFile().ReadConditionReg(condition)
+			if is_set != reference_result.conditions[condition] {
+				t.Errorf(
+					"%s: condition %s is %t, expected %t",
+					conformance_case.Name,
+					new(instruction.Instruction).StringifyCondition(condition),
+					is_set,
+					reference_result.conditions[condition],
+				)
+			}
+		}
+	})
+}
@@ -2,8 +2,10 @@ package logic
 
 import (
 	"errors"
+	"slices"
 	"uPIMulator/src/linker/kernel/instruction"
 	"uPIMulator/src/linker/kernel/instruction/reg_descriptor"
+	"uPIMulator/src/misc"
 )
 
 type RegSet struct {
@@ -248,3 +250,46 @@ func (this *RegSet) RegIndices() map[int]bool {
 
 	return reg_indices
 }
+
+func (this *RegSet) Checkpoint(checkpoint_writer *misc.CheckpointWriter) {
+	this.CheckpointGpRegSet(checkpoint_writer, this.prev_write_gp_reg_set)
+	this.CheckpointGpRegSet(checkpoint_writer, this.cur_read_gp_reg_set)
+}
+
+func (this *RegSet) CheckpointGpRegSet(
+	checkpoint_writer *misc.CheckpointWriter,
+	gp_reg_set map[*reg_descriptor.GpRegDescriptor]bool,
+) {
+	reg_indices := make([]int, 0)
+	for gp_reg_descriptor, _ := range gp_reg_set {
+		reg_indices = append(reg_indices, gp_reg_descriptor.Index())
+	}
+
+	slices.Sort(reg_indices)
+
+	checkpoint_writer.WriteInt(int64(len(reg_indices)))
+	for _, reg_index := range reg_indices {
+		checkpoint_writer.WriteInt(int64(reg_index))
+	}
+}
+
+func (this *RegSet) Restore(checkpoint_reader *misc.CheckpointReader) {
+	this.prev_write_gp_reg_set = this.RestoreGpRegSet(checkpoint_reader)
+	this.cur_read_gp_reg_set = this.RestoreGpRegSet(checkpoint_reader)
+}
+
+func (this *RegSet) RestoreGpRegSet(
+	checkpoint_reader *misc.CheckpointReader,
+) map[*reg_descriptor.GpRegDescriptor]bool {
+	gp_reg_set := make(map[*reg_descriptor.GpRegDescriptor]bool, 0)
+
+	num_reg_indices := int(checkpoint_reader.ReadInt())
+	for i := 0; i < num_reg_indices; i++ {
+		gp_reg_descriptor := new(reg_descriptor.GpRegDescriptor)
+		gp_reg_descriptor.Init(int(checkpoint_reader.ReadInt()))
+
+		gp_reg_set[gp_reg_descriptor] = true
+	}
+
+	return gp_reg_set
+}
@@ -63,6 +63,23 @@ func (this *Thread) SetThreadState(thread_state ThreadState) {
 	this.thread_state = thread_state
 }
 
+func (this *Thread) StringifyThreadState() string {
+	if this.thread_state == EMBRYO {
+		return "EMBRYO"
+	} else if this.thread_state == RUNNABLE {
+		return "RUNNABLE"
+	} else if this.thread_state == SLEEP {
+		return "SLEEP"
+	} else if this.thread_state == BLOCK {
+		return "BLOCK"
+	} else if this.thread_state == ZOMBIE {
+		return "ZOMBIE"
+	} else {
+		err := errors.New("thread state is not valid")
+		panic(err)
+	}
+}
+
 func (this *Thread) RegFile() *reg.RegFile {
 	return this.reg_file
 }
@@ -75,6 +92,22 @@ func (this *Thread) IncrementIssueCycle() {
 	this.issue_cycle++
 }
 
+func (this *Thread) SkipIssueCycle(num_cycles int64) {
+	this.issue_cycle += num_cycles
+}
+
 func (this *Thread) ResetIssueCycle() {
 	this.issue_cycle = 0
 }
+
+func (this *Thread) Checkpoint(checkpoint_writer *misc.CheckpointWriter) {
+	checkpoint_writer.WriteInt(int64(this.thread_state))
+	checkpoint_writer.WriteInt(this.issue_cycle)
+	this.reg_file.Checkpoint(checkpoint_writer)
+}
+
+func (this *Thread) Restore(checkpoint_reader *misc.CheckpointReader) {
+	this.thread_state = ThreadState(checkpoint_reader.ReadInt())
+	this.issue_cycle = checkpoint_reader.ReadInt()
+	this.reg_file.Restore(checkpoint_reader)
+}
@@ -2,6 +2,7 @@ package logic
 
 import (
 	"errors"
+	"uPIMulator/src/misc"
 )
 
 type ThreadQ struct {
@@ -123,3 +124,24 @@ func (this *ThreadQ) Cycle() {
 		this.cycles[0] -= 1
 	}
 }
+
+func (this *ThreadQ) Checkpoint(checkpoint_writer *misc.CheckpointWriter) {
+	checkpoint_writer.WriteInt(int64(len(this.threads)))
+	for i, thread := range this.threads {
+		checkpoint_writer.WriteInt(int64(thread.ThreadId()))
+		checkpoint_writer.WriteInt(this.cycles[i])
+	}
+}
+
+func (this *ThreadQ) Restore(checkpoint_reader *misc.CheckpointReader, threads []*Thread) {
+	this.threads = make([]*Thread, 0)
+	this.cycles = make([]int64, 0)
+
+	num_threads := int(checkpoint_reader.ReadInt())
+	for i := 0; i < num_threads; i++ {
+		thread_id := int(checkpoint_reader.ReadInt())
+
+		this.threads = append(this.threads, threads[thread_id])
+		this.cycles = append(this.cycles, checkpoint_reader.ReadInt())
+	}
+}
@@ -17,6 +17,8 @@ type ThreadScheduler struct {
 	thread_q *ThreadQ
 
 	stat_factory *misc.StatFactory
+
+	tracer *misc.Tracer
 }
 
 func (this *ThreadScheduler) Init(
@@ -56,6 +58,8 @@ func (this *ThreadScheduler) Init(
 	name := fmt.Sprintf("ThreadScheduler[%d_%d_%d]", channel_id, rank_id, dpu_id)
 	this.stat_factory = new(misc.StatFactory)
 	this.stat_factory.Init(name)
+
+	this.tracer = nil
 }
 
 func (this *ThreadScheduler) Fini() {
@@ -66,10 +70,23 @@ func (this *ThreadScheduler) Fini() {
 	this.thread_q.Fini()
 }
 
+func (this *ThreadScheduler) ConnectTracer(tracer *misc.Tracer) {
+	if this.tracer != nil {
+		err := errors.New("tracer is already set")
+		panic(err)
+	}
+
+	this.tracer = tracer
+}
+
 func (this *ThreadScheduler) StatFactory() *misc.StatFactory {
 	return this.stat_factory
 }
 
+func (this *ThreadScheduler) Threads() []*Thread {
+	return this.threads
+}
+
 func (this *ThreadScheduler) NumIssuableThreads() int {
 	num_issuable_threads := 0
 
@@ -111,6 +128,30 @@ func (this *ThreadScheduler) Schedule() *Thread {
 	return nil
 }
 
+func (this *ThreadScheduler) Skip(num_cycles int64) {
+	num_blocked_cycles := int64(0)
+	for _, thread := range this.threads {
+		if thread.ThreadState() == BLOCK {
+			first_blocked_cycle := this.num_revolver_scheduling_cycles - (thread.IssueCycle() - num_cycles) - 1
+			if first_blocked_cycle < 0 {
+				first_blocked_cycle = 0
+			}
+
+			if num_cycles-first_blocked_cycle > num_blocked_cycles {
+				num_blocked_cycles = num_cycles - first_blocked_cycle
+			}
+		}
+	}
+
+	if num_blocked_cycles > 0 {
+		this.stat_factory.Increment("breakdown_dma", num_blocked_cycles)
+	}
+
+	if num_cycles-num_blocked_cycles > 0 {
+		this.stat_factory.Increment("breakdown_etc", num_cycles-num_blocked_cycles)
+	}
+}
+
 func (this *ThreadScheduler) Boot(thread_id int) bool {
 	thread := this.threads[thread_id]
 
@@ -122,9 +163,11 @@ func (this *ThreadScheduler) Boot(thread_id int) bool {
 	thread_state := thread.ThreadState()
 	if thread_state == EMBRYO {
 		thread.SetThreadState(RUNNABLE)
+		this.Trace(thread)
 		return true
 	} else if thread_state == ZOMBIE {
 		thread.SetThreadState(RUNNABLE)
+		this.Trace(thread)
 		return true
 	} else {
 		err := errors.New("thread is not bootable")
@@ -143,6 +186,7 @@ func (this *ThreadScheduler) Sleep(thread_id int) bool {
 	thread_state := thread.ThreadState()
 	if thread_state == RUNNABLE {
 		thread.SetThreadState(SLEEP)
+		this.Trace(thread)
 		return true
 	} else {
 		err := errors.New("thread is not sleepable")
@@ -161,6 +205,7 @@ func (this *ThreadScheduler) Block(thread_id int) bool {
 	thread_state := thread.ThreadState()
 	if thread_state == RUNNABLE {
 		thread.SetThreadState(BLOCK)
+		this.Trace(thread)
 		return true
 	} else {
 		err := errors.New("thread is not blockable")
@@ -179,12 +224,15 @@ func (this *ThreadScheduler) Awake(thread_id int) bool {
 	thread_state := thread.ThreadState()
 	if thread_state == EMBRYO {
 		thread.SetThreadState(RUNNABLE)
+		this.Trace(thread)
 		return true
 	} else if thread_state == SLEEP {
 		thread.SetThreadState(RUNNABLE)
+		this.Trace(thread)
 		return true
 	} else if thread_state == BLOCK {
 		thread.SetThreadState(RUNNABLE)
+		this.Trace(thread)
 		return true
 	} else {
 		err := errors.New("thread is not awakable")
@@ -203,6 +251,7 @@ func (this *ThreadScheduler) Shutdown(thread_id int) bool {
 	thread_state := thread.ThreadState()
 	if thread_state == SLEEP {
 		thread.SetThreadState(ZOMBIE)
+		this.Trace(thread)
 		return true
 	} else {
 		err := errors.New("thread is not shotdownable")
@@ -210,5 +259,47 @@ func (this *ThreadScheduler) Shutdown(thread_id int) bool {
 	}
 }
 
+// Stop stops the thread from whatever state it is in, as a fault does to every thread of the DPU.
+func (this *ThreadScheduler) Stop(thread_id int) bool {
+	thread := this.threads[thread_id]
+
+	if thread.ThreadId() != thread_id {
+		err := errors.New("thread's thread ID != thread ID")
+		panic(err)
+	}
+
+	if thread.ThreadState() != ZOMBIE {
+		thread.SetThreadState(ZOMBIE)
+		this.Trace(thread)
+	}
+	return true
+}
+
+func (this *ThreadScheduler) Trace(thread *Thread) {
+	if this.tracer == nil {
+		return
+	}
+
+	if thread.ThreadState() == EMBRYO {
+		this.tracer.EndState(thread.ThreadId())
+	} else {
+		this.tracer.Transition(thread.ThreadId(), thread.StringifyThreadState())
+	}
+}
+
 func (this *ThreadScheduler) Cycle() {
 }
+
+func (this *ThreadScheduler) Checkpoint(checkpoint_writer *misc.CheckpointWriter) {
+	this.thread_q.Checkpoint(checkpoint_writer)
+	this.stat_factory.Checkpoint(checkpoint_writer)
+}
+
+func (this *ThreadScheduler) Restore(checkpoint_reader *misc.CheckpointReader) {
+	this.thread_q.Restore(checkpoint_reader, this.threads)
+	this.stat_factory.Restore(checkpoint_reader)
+
+	for _, thread := range this.threads {
+		this.Trace(thread)
+	}
+}
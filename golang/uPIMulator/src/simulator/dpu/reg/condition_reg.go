@@ -3,6 +3,7 @@ package reg
 import (
 	"errors"
 	"uPIMulator/src/linker/kernel/instruction/cc"
+	"uPIMulator/src/misc"
 )
 
 type ConditionReg struct {
@@ -57,3 +58,25 @@ func (this *ConditionReg) ClearConditions() {
 		}
 	}
 }
+
+func (this *ConditionReg) Checkpoint(checkpoint_writer *misc.CheckpointWriter) {
+	for i := 0; i <= int(cc.LARGE); i++ {
+		condition := cc.Condition(i)
+		if condition == cc.TRUE || condition == cc.FALSE {
+			continue
+		} else {
+			checkpoint_writer.WriteBool(this.conditions[condition])
+		}
+	}
+}
+
+func (this *ConditionReg) Restore(checkpoint_reader *misc.CheckpointReader) {
+	for i := 0; i <= int(cc.LARGE); i++ {
+		condition := cc.Condition(i)
+		if condition == cc.TRUE || condition == cc.FALSE {
+			continue
+		} else {
+			this.conditions[condition] = checkpoint_reader.ReadBool()
+		}
+	}
+}
@@ -1,11 +1,19 @@
 package reg
 
 import (
+	"errors"
+	"fmt"
+	"strings"
 	"uPIMulator/src/linker/kernel/instruction"
+	"uPIMulator/src/misc"
 )
 
+// An ExceptionReg holds the exceptions that have faulted the tasklet, and the code of the fault
+// instruction that has faulted it, if any. A fault code that dpufault.h does not define faults the
+// tasklet with the code alone.
 type ExceptionReg struct {
 	exceptions map[instruction.Exception]bool
+	fault_code *int64
 }
 
 func (this *ExceptionReg) Init() {
@@ -21,6 +29,33 @@ func (this *ExceptionReg) Exception(exception instruction.Exception) bool {
 	return this.exceptions[exception]
 }
 
+func (this *ExceptionReg) HasException() bool {
+	for _, is_set := range this.exceptions {
+		if is_set {
+			return true
+		}
+	}
+	return this.fault_code != nil
+}
+
+func (this *ExceptionReg) HasFaultCode() bool {
+	return this.fault_code != nil
+}
+
+func (this *ExceptionReg) FaultCode() int64 {
+	if this.fault_code == nil {
+		err := errors.New("fault code is not set")
+		panic(err)
+	}
+
+	return *this.fault_code
+}
+
+func (this *ExceptionReg) SetFaultCode(fault_code int64) {
+	this.fault_code = new(int64)
+	*this.fault_code = fault_code
+}
+
 func (this *ExceptionReg) SetException(exception instruction.Exception) {
 	this.exceptions[exception] = true
 }
@@ -35,4 +70,73 @@ func (this *ExceptionReg) ClearExceptions() {
 
 		this.exceptions[exception] = false
 	}
+
+	this.fault_code = nil
+}
+
+// StringifyExceptions names the exceptions that are set, in the order of their codes, followed by
+// the fault code, if any.
+func (this *ExceptionReg) StringifyExceptions() string {
+	names := make([]string, 0)
+	for i := 0; i <= int(instruction.NOT_PROFILING); i++ {
+		exception := instruction.Exception(i)
+
+		if this.exceptions[exception] {
+			names = append(names, this.StringifyException(exception))
+		}
+	}
+
+	if this.fault_code != nil {
+		names = append(names, fmt.Sprintf("fault %d", *this.fault_code))
+	}
+	return strings.Join(names, ", ")
+}
+
+func (this *ExceptionReg) StringifyException(exception instruction.Exception) string {
+	if exception == instruction.MEMORY_FAULT {
+		return "memory_fault"
+	} else if exception == instruction.DMA_FAULT {
+		return "dma_fault"
+	} else if exception == instruction.HEAP_FULL {
+		return "heap_full"
+	} else if exception == instruction.DIVISION_BY_ZERO {
+		return "division_by_zero"
+	} else if exception == instruction.ASSERT {
+		return "assert"
+	} else if exception == instruction.HALT {
+		return "halt"
+	} else if exception == instruction.PRINT_OVERFLOW {
+		return "print_overflow"
+	} else if exception == instruction.ALREADY_PROFILING {
+		return "already_profiling"
+	} else if exception == instruction.NOT_PROFILING {
+		return "not_profiling"
+	} else {
+		err := errors.New("exception is not valid")
+		panic(err)
+	}
+}
+
+func (this *ExceptionReg) Checkpoint(checkpoint_writer *misc.CheckpointWriter) {
+	for i := 0; i <= int(instruction.NOT_PROFILING); i++ {
+		exception := instruction.Exception(i)
+		checkpoint_writer.WriteBool(this.exceptions[exception])
+	}
+
+	checkpoint_writer.WriteBool(this.fault_code != nil)
+	if this.fault_code != nil {
+		checkpoint_writer.WriteInt(*this.fault_code)
+	}
+}
+
+func (this *ExceptionReg) Restore(checkpoint_reader *misc.CheckpointReader) {
+	for i := 0; i <= int(instruction.NOT_PROFILING); i++ {
+		exception := instruction.Exception(i)
+		this.exceptions[exception] = checkpoint_reader.ReadBool()
+	}
+
+	this.fault_code = nil
+	if checkpoint_reader.ReadBool() {
+		this.SetFaultCode(checkpoint_reader.ReadInt())
+	}
 }
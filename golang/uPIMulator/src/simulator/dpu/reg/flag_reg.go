@@ -2,6 +2,7 @@ package reg
 
 import (
 	"uPIMulator/src/linker/kernel/instruction"
+	"uPIMulator/src/misc"
 )
 
 type FlagReg struct {
@@ -36,3 +37,17 @@ func (this *FlagReg) ClearFlags() {
 		this.flags[flag] = false
 	}
 }
+
+func (this *FlagReg) Checkpoint(checkpoint_writer *misc.CheckpointWriter) {
+	for i := 0; i <= int(instruction.CARRY); i++ {
+		flag := instruction.Flag(i)
+		checkpoint_writer.WriteBool(this.flags[flag])
+	}
+}
+
+func (this *FlagReg) Restore(checkpoint_reader *misc.CheckpointReader) {
+	for i := 0; i <= int(instruction.CARRY); i++ {
+		flag := instruction.Flag(i)
+		this.flags[flag] = checkpoint_reader.ReadBool()
+	}
+}
@@ -36,3 +36,11 @@ func (this *GpReg) Read(representation word.Representation) int64 {
 func (this *GpReg) Write(value int64) {
 	this.word.SetValue(value)
 }
+
+func (this *GpReg) Checkpoint(checkpoint_writer *misc.CheckpointWriter) {
+	checkpoint_writer.WriteInt(this.Read(word.UNSIGNED))
+}
+
+func (this *GpReg) Restore(checkpoint_reader *misc.CheckpointReader) {
+	this.Write(checkpoint_reader.ReadInt())
+}
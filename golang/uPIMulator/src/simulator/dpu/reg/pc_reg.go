@@ -36,3 +36,11 @@ func (this *PcReg) Increment() {
 
 	this.Write(this.Read() + iram_data_size)
 }
+
+func (this *PcReg) Checkpoint(checkpoint_writer *misc.CheckpointWriter) {
+	checkpoint_writer.WriteInt(this.Read())
+}
+
+func (this *PcReg) Restore(checkpoint_reader *misc.CheckpointReader) {
+	this.Write(checkpoint_reader.ReadInt())
+}
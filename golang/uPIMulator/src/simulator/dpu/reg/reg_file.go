@@ -108,6 +108,22 @@ func (this *RegFile) ReadExceptionReg(exception instruction.Exception) bool {
 	return this.exception_reg.Exception(exception)
 }
 
+func (this *RegFile) HasException() bool {
+	return this.exception_reg.HasException()
+}
+
+func (this *RegFile) StringifyExceptions() string {
+	return this.exception_reg.StringifyExceptions()
+}
+
+func (this *RegFile) HasFaultCode() bool {
+	return this.exception_reg.HasFaultCode()
+}
+
+func (this *RegFile) ReadFaultCode() int64 {
+	return this.exception_reg.FaultCode()
+}
+
 func (this *RegFile) WriteGpReg(gp_reg_descriptor *reg_descriptor.GpRegDescriptor, value int64) {
 	this.gp_regs[gp_reg_descriptor.Index()].Write(value)
 }
@@ -157,6 +173,10 @@ func (this *RegFile) SetException(exception instruction.Exception) {
 	this.exception_reg.SetException(exception)
 }
 
+func (this *RegFile) SetFaultCode(fault_code int64) {
+	this.exception_reg.SetFaultCode(fault_code)
+}
+
 func (this *RegFile) ClearException(exception instruction.Exception) {
 	this.exception_reg.ClearException(exception)
 }
@@ -164,3 +184,25 @@ func (this *RegFile) ClearException(exception instruction.Exception) {
 func (this *RegFile) ClearExceptions() {
 	this.exception_reg.ClearExceptions()
 }
+
+func (this *RegFile) Checkpoint(checkpoint_writer *misc.CheckpointWriter) {
+	for _, gp_reg := range this.gp_regs {
+		gp_reg.Checkpoint(checkpoint_writer)
+	}
+
+	this.pc_reg.Checkpoint(checkpoint_writer)
+	this.condition_reg.Checkpoint(checkpoint_writer)
+	this.flag_reg.Checkpoint(checkpoint_writer)
+	this.exception_reg.Checkpoint(checkpoint_writer)
+}
+
+func (this *RegFile) Restore(checkpoint_reader *misc.CheckpointReader) {
+	for _, gp_reg := range this.gp_regs {
+		gp_reg.Restore(checkpoint_reader)
+	}
+
+	this.pc_reg.Restore(checkpoint_reader)
+	this.condition_reg.Restore(checkpoint_reader)
+	this.flag_reg.Restore(checkpoint_reader)
+	this.exception_reg.Restore(checkpoint_reader)
+}
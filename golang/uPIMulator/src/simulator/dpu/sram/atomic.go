@@ -42,6 +42,11 @@ func (this *Atomic) Size() int64 {
 	return this.size
 }
 
+// Holder returns the tasklet that holds the lock at the address, or -1 if the lock is free.
+func (this *Atomic) Holder(address int64) int {
+	return this.locks[this.Index(address)].ThreadId()
+}
+
 func (this *Atomic) CanAcquire(address int64) bool {
 	return this.locks[this.Index(address)].CanAcquire()
 }
@@ -69,3 +74,15 @@ func (this *Atomic) Index(address int64) int {
 
 	return int(address - this.address)
 }
+
+func (this *Atomic) Checkpoint(checkpoint_writer *misc.CheckpointWriter) {
+	for _, lock := range this.locks {
+		lock.Checkpoint(checkpoint_writer)
+	}
+}
+
+func (this *Atomic) Restore(checkpoint_reader *misc.CheckpointReader) {
+	for _, lock := range this.locks {
+		lock.Restore(checkpoint_reader)
+	}
+}
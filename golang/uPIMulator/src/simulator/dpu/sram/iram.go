@@ -8,8 +8,9 @@ import (
 )
 
 type Iram struct {
-	address int64
-	size    int64
+	address   int64
+	size      int64
+	data_size int64
 
 	byte_stream *encoding.ByteStream
 }
@@ -20,6 +21,7 @@ func (this *Iram) Init() {
 
 	this.address = config_loader.IramOffset()
 	this.size = config_loader.IramSize()
+	this.data_size = int64(config_loader.IramDataWidth() / 8)
 
 	this.byte_stream = new(encoding.ByteStream)
 	this.byte_stream.Init()
@@ -40,17 +42,12 @@ func (this *Iram) Size() int64 {
 }
 
 func (this *Iram) Read(address int64) *instruction.Instruction {
-	config_loader := new(misc.ConfigLoader)
-	config_loader.Init()
-
-	iram_data_size := int64(config_loader.IramDataWidth() / 8)
+	index := this.Index(address)
 
 	byte_stream := new(encoding.ByteStream)
 	byte_stream.Init()
-	for i := int64(0); i < iram_data_size; i++ {
-		index := this.Index(address) + int(i)
-
-		byte_stream.Append(this.byte_stream.Get(index))
+	for i := int64(0); i < this.data_size; i++ {
+		byte_stream.Append(this.byte_stream.Get(index + int(i)))
 	}
 
 	instruction_ := new(instruction.Instruction)
@@ -58,32 +55,66 @@ func (this *Iram) Read(address int64) *instruction.Instruction {
 	return instruction_
 }
 
-func (this *Iram) Write(address int64, byte_stream *encoding.ByteStream) {
-	for i := int64(0); i < byte_stream.Size(); i++ {
-		index := this.Index(address) + int(i)
+// Peek reads the raw bytes of an IRAM range that does not have to be aligned with the IRAM data size.
+func (this *Iram) Peek(address int64, size int64) *encoding.ByteStream {
+	if address < this.address {
+		err := errors.New("address < IRAM offset")
+		panic(err)
+	} else if address+size > this.address+this.size {
+		err := errors.New("address + size > IRAM offset + IRAM size")
+		panic(err)
+	}
 
-		this.byte_stream.Set(index, byte_stream.Get(int(i)))
+	byte_stream := new(encoding.ByteStream)
+	byte_stream.Init()
+	for i := int64(0); i < size; i++ {
+		byte_stream.Append(this.byte_stream.Get(int(address - this.address + i)))
 	}
+
+	return byte_stream
 }
 
-func (this *Iram) Index(address int64) int {
-	config_loader := new(misc.ConfigLoader)
-	config_loader.Init()
+func (this *Iram) Write(address int64, byte_stream *encoding.ByteStream) {
+	index := this.Index(address)
+
+	if address+byte_stream.Size() > this.address+this.size {
+		err := errors.New("address + size > IRAM offset + IRAM size")
+		panic(err)
+	}
 
-	iram_data_size := int64(config_loader.IramDataWidth() / 8)
+	for i := int64(0); i < byte_stream.Size(); i++ {
+		this.byte_stream.Set(index+int(i), byte_stream.Get(int(i)))
+	}
+}
 
+func (this *Iram) Index(address int64) int {
 	if address < this.address {
 		err := errors.New("address < IRAM offset")
 		panic(err)
-	} else if address+iram_data_size > this.address+this.size {
+	} else if address+this.data_size > this.address+this.size {
 		err := errors.New("address >= IRAM offset + IRAM size")
 		panic(err)
 	}
 
-	if (address-this.address)%iram_data_size != 0 {
+	if (address-this.address)%this.data_size != 0 {
 		err := errors.New("addresses are not aligned with IRAM data size")
 		panic(err)
 	}
 
 	return int(address - this.address)
 }
+
+func (this *Iram) Checkpoint(checkpoint_writer *misc.CheckpointWriter) {
+	checkpoint_writer.WriteByteStream(this.byte_stream)
+}
+
+func (this *Iram) Restore(checkpoint_reader *misc.CheckpointReader) {
+	byte_stream := checkpoint_reader.ReadByteStream()
+
+	if byte_stream.Size() != this.size {
+		err := errors.New("byte stream's size != IRAM size")
+		panic(err)
+	}
+
+	this.byte_stream = byte_stream
+}
@@ -2,6 +2,7 @@ package sram
 
 import (
 	"errors"
+	"uPIMulator/src/misc"
 )
 
 type Lock struct {
@@ -19,6 +20,14 @@ func (this *Lock) Fini() {
 	}
 }
 
+// ThreadId returns the tasklet that holds the lock, or -1 if the lock is free.
+func (this *Lock) ThreadId() int {
+	if this.thread_id == nil {
+		return -1
+	}
+	return *this.thread_id
+}
+
 func (this *Lock) CanAcquire() bool {
 	return this.thread_id == nil
 }
@@ -45,3 +54,18 @@ func (this *Lock) Release(thread_id int) {
 
 	this.thread_id = nil
 }
+
+func (this *Lock) Checkpoint(checkpoint_writer *misc.CheckpointWriter) {
+	checkpoint_writer.WriteBool(this.thread_id != nil)
+	if this.thread_id != nil {
+		checkpoint_writer.WriteInt(int64(*this.thread_id))
+	}
+}
+
+func (this *Lock) Restore(checkpoint_reader *misc.CheckpointReader) {
+	this.thread_id = nil
+	if checkpoint_reader.ReadBool() {
+		this.thread_id = new(int)
+		*this.thread_id = int(checkpoint_reader.ReadInt())
+	}
+}
@@ -75,3 +75,18 @@ func (this *Wram) Index(address int64) int {
 
 	return int(address - this.address)
 }
+
+func (this *Wram) Checkpoint(checkpoint_writer *misc.CheckpointWriter) {
+	checkpoint_writer.WriteByteStream(this.byte_stream)
+}
+
+func (this *Wram) Restore(checkpoint_reader *misc.CheckpointReader) {
+	byte_stream := checkpoint_reader.ReadByteStream()
+
+	if byte_stream.Size() != this.size {
+		err := errors.New("byte stream's size != WRAM size")
+		panic(err)
+	}
+
+	this.byte_stream = byte_stream
+}
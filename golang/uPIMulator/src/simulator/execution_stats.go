@@ -0,0 +1,30 @@
+package simulator
+
+type ExecutionStats struct {
+	execution int
+	cycles    int64
+
+	dpu_stats []map[string]map[string]int64
+}
+
+func (this *ExecutionStats) Init(
+	execution int,
+	cycles int64,
+	dpu_stats []map[string]map[string]int64,
+) {
+	this.execution = execution
+	this.cycles = cycles
+	this.dpu_stats = dpu_stats
+}
+
+func (this *ExecutionStats) Execution() int {
+	return this.execution
+}
+
+func (this *ExecutionStats) Cycles() int64 {
+	return this.cycles
+}
+
+func (this *ExecutionStats) DpuStats() []map[string]map[string]int64 {
+	return this.dpu_stats
+}
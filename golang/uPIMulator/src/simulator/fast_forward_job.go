@@ -0,0 +1,37 @@
+package simulator
+
+import (
+	"uPIMulator/src/simulator/dpu"
+)
+
+// A FastForwardJob stays in the worker pool of the simulator for the whole run, and runs its DPU
+// ahead once it is armed, if the DPU is at the cycle that it is armed with and is not idle.
+type FastForwardJob struct {
+	dpu            *dpu.Dpu
+	cycles         int64
+	max_num_cycles int64
+	is_armed       bool
+}
+
+func (this *FastForwardJob) Init(dpu_ *dpu.Dpu) {
+	this.dpu = dpu_
+	this.cycles = 0
+	this.max_num_cycles = 0
+	this.is_armed = false
+}
+
+func (this *FastForwardJob) Arm(cycles int64, max_num_cycles int64) {
+	this.cycles = cycles
+	this.max_num_cycles = max_num_cycles
+	this.is_armed = true
+}
+
+func (this *FastForwardJob) Disarm() {
+	this.is_armed = false
+}
+
+func (this *FastForwardJob) Execute() {
+	if this.is_armed && this.dpu.Cycles() == this.cycles && !this.dpu.IsIdle() {
+		this.dpu.FastForward(this.max_num_cycles)
+	}
+}
@@ -0,0 +1,694 @@
+package simulator
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"uPIMulator/src/abi/encoding"
+	"uPIMulator/src/abi/word"
+	"uPIMulator/src/linker/kernel/instruction"
+	"uPIMulator/src/linker/kernel/instruction/reg_descriptor"
+	"uPIMulator/src/misc"
+	"uPIMulator/src/simulator/dpu"
+	"uPIMulator/src/simulator/dpu/logic"
+)
+
+const (
+	gdb_sigint  = 2
+	gdb_sigill  = 4
+	gdb_sigtrap = 5
+)
+
+// A GdbServer serves the GDB remote serial protocol for a single DPU over a local TCP socket. Each
+// tasklet is a GDB thread whose ID is the tasklet ID plus one, and the registers are the GP
+// registers followed by the PC and the zero and carry flags, all 32 bits wide. Memory addresses
+// are those of the linker, so WRAM, MRAM, and IRAM are told apart by their offsets. Breakpoints
+// are kept by the server rather than written into the IRAM, and a tasklet stops before it
+// executes a fault instruction. The simulator runs one system cycle at a time while the DPU is
+// resumed, so the other DPUs keep running as well.
+type GdbServer struct {
+	simulator *Simulator
+	dpu_index int
+	dpu       *dpu.Dpu
+
+	port       int
+	listener   net.Listener
+	connection net.Conn
+	input      chan byte
+	is_ack     bool
+
+	breakpoints   []*Breakpoint
+	breakpoint_id int
+
+	thread_id int
+	pcs       []int64
+	states    []string
+}
+
+func (this *GdbServer) Init(simulator *Simulator, command_line_parser *misc.CommandLineParser) {
+	this.simulator = simulator
+
+	dpus := simulator.Host().Dpus()
+
+	this.dpu_index = int(command_line_parser.IntParameter("gdb_dpu"))
+	if this.dpu_index < 0 || this.dpu_index >= len(dpus) {
+		err := errors.New("gdb_dpu is out of range")
+		panic(err)
+	}
+	this.dpu = dpus[this.dpu_index]
+
+	this.port = int(command_line_parser.IntParameter("gdb_port"))
+	this.is_ack = true
+
+	this.breakpoints = make([]*Breakpoint, 0)
+	this.breakpoint_id = 0
+
+	this.thread_id = 0
+	this.pcs = make([]int64, len(this.dpu.Threads()))
+	this.states = make([]string, len(this.dpu.Threads()))
+	this.Observe()
+}
+
+func (this *GdbServer) Run() {
+	listener, listen_err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", this.port))
+	if listen_err != nil {
+		panic(listen_err)
+	}
+	this.listener = listener
+
+	fmt.Printf("waiting for gdb on 127.0.0.1:%d...\n", this.port)
+
+	connection, accept_err := this.listener.Accept()
+	if accept_err != nil {
+		panic(accept_err)
+	}
+	this.connection = connection
+
+	this.input = make(chan byte, 4096)
+	go this.Receive()
+
+	for {
+		packet, is_received := this.ReadPacket()
+		if !is_received {
+			break
+		}
+
+		if packet == "c" || packet == "s" || strings.HasPrefix(packet, "vCont;") {
+			stop_reply := this.Resume(packet)
+			this.WritePacket(stop_reply)
+
+			if strings.HasPrefix(stop_reply, "W") {
+				break
+			}
+		} else if packet == "k" {
+			break
+		} else if strings.HasPrefix(packet, "D") {
+			this.WritePacket("OK")
+			break
+		} else {
+			this.WritePacket(this.Handle(packet))
+		}
+	}
+
+	this.connection.Close()
+	this.listener.Close()
+
+	for !this.simulator.IsFinished() {
+		this.simulator.Cycle()
+	}
+}
+
+func (this *GdbServer) Receive() {
+	reader := bufio.NewReader(this.connection)
+
+	for {
+		value, err := reader.ReadByte()
+		if err != nil {
+			close(this.input)
+			return
+		}
+
+		this.input <- value
+	}
+}
+
+// ReadPacket returns the payload of the next packet with a valid checksum, skipping the
+// acknowledgments and interrupts that arrive while the DPU is stopped.
+func (this *GdbServer) ReadPacket() (string, bool) {
+	for {
+		value, is_open := <-this.input
+		if !is_open {
+			return "", false
+		} else if value != '$' {
+			continue
+		}
+
+		payload := make([]byte, 0)
+		for {
+			value, is_open = <-this.input
+			if !is_open {
+				return "", false
+			} else if value == '#' {
+				break
+			}
+
+			payload = append(payload, value)
+		}
+
+		checksum := make([]byte, 0)
+		for len(checksum) < 2 {
+			value, is_open = <-this.input
+			if !is_open {
+				return "", false
+			}
+
+			checksum = append(checksum, value)
+		}
+
+		expected_checksum, parse_err := strconv.ParseUint(string(checksum), 16, 8)
+		if parse_err != nil || uint8(expected_checksum) != this.Checksum(string(payload)) {
+			if this.is_ack {
+				this.Write("-")
+			}
+			continue
+		}
+
+		if this.is_ack {
+			this.Write("+")
+		}
+
+		return string(payload), true
+	}
+}
+
+func (this *GdbServer) WritePacket(payload string) {
+	this.Write(fmt.Sprintf("$%s#%02x", payload, this.Checksum(payload)))
+}
+
+func (this *GdbServer) Write(message string) {
+	if _, err := this.connection.Write([]byte(message)); err != nil {
+		panic(err)
+	}
+}
+
+func (this *GdbServer) Checksum(payload string) uint8 {
+	checksum := uint8(0)
+	for i := 0; i < len(payload); i++ {
+		checksum += payload[i]
+	}
+	return checksum
+}
+
+// Handle returns the reply to a packet that does not resume the DPU. An empty reply tells GDB that
+// the packet is not supported.
+func (this *GdbServer) Handle(packet string) string {
+	if packet == "?" {
+		return this.StopReply(gdb_sigtrap)
+	} else if strings.HasPrefix(packet, "qSupported") {
+		return "PacketSize=4000;qXfer:features:read+;QStartNoAckMode+;vContSupported+"
+	} else if packet == "QStartNoAckMode" {
+		this.is_ack = false
+		return "OK"
+	} else if strings.HasPrefix(packet, "qXfer:features:read:target.xml:") {
+		return this.HandleTargetXml(strings.TrimPrefix(packet, "qXfer:features:read:target.xml:"))
+	} else if packet == "qAttached" {
+		return "1"
+	} else if packet == "qC" {
+		return fmt.Sprintf("QC%x", this.thread_id+1)
+	} else if packet == "qfThreadInfo" {
+		thread_ids := make([]string, 0)
+		for _, thread := range this.dpu.Threads() {
+			thread_ids = append(thread_ids, fmt.Sprintf("%x", thread.ThreadId()+1))
+		}
+		return "m" + strings.Join(thread_ids, ",")
+	} else if packet == "qsThreadInfo" {
+		return "l"
+	} else if strings.HasPrefix(packet, "qThreadExtraInfo,") {
+		thread, err := this.ParseThread(strings.TrimPrefix(packet, "qThreadExtraInfo,"))
+		if err != nil {
+			return "E01"
+		}
+		return hex.EncodeToString([]byte(thread.StringifyThreadState()))
+	} else if strings.HasPrefix(packet, "T") {
+		if _, err := this.ParseThread(packet[1:]); err != nil {
+			return "E01"
+		}
+		return "OK"
+	} else if strings.HasPrefix(packet, "H") && len(packet) >= 2 {
+		return this.HandleSetThread(packet[2:])
+	} else if packet == "g" {
+		return this.HandleReadRegisters()
+	} else if strings.HasPrefix(packet, "G") {
+		return this.HandleWriteRegisters(packet[1:])
+	} else if strings.HasPrefix(packet, "p") {
+		return this.HandleReadRegister(packet[1:])
+	} else if strings.HasPrefix(packet, "P") {
+		return this.HandleWriteRegister(packet[1:])
+	} else if strings.HasPrefix(packet, "m") {
+		return this.HandleReadMemory(packet[1:])
+	} else if strings.HasPrefix(packet, "M") {
+		return this.HandleWriteMemory(packet[1:])
+	} else if strings.HasPrefix(packet, "Z0,") || strings.HasPrefix(packet, "Z1,") {
+		return this.HandleInsertBreakpoint(packet[3:])
+	} else if strings.HasPrefix(packet, "z0,") || strings.HasPrefix(packet, "z1,") {
+		return this.HandleRemoveBreakpoint(packet[3:])
+	} else if packet == "vCont?" {
+		return "vCont;c;C;s;S"
+	} else {
+		return ""
+	}
+}
+
+func (this *GdbServer) HandleTargetXml(annex string) string {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	registers := make([]string, 0)
+	for i := 0; i < config_loader.NumGpRegisters(); i++ {
+		registers = append(registers, fmt.Sprintf("<reg name=\"r%d\" bitsize=\"32\" type=\"uint32\"/>", i))
+	}
+	registers = append(registers, "<reg name=\"pc\" bitsize=\"32\" type=\"code_ptr\"/>")
+	registers = append(registers, "<reg name=\"zf\" bitsize=\"32\" type=\"uint32\"/>")
+	registers = append(registers, "<reg name=\"cf\" bitsize=\"32\" type=\"uint32\"/>")
+
+	target_xml := "<?xml version=\"1.0\"?><!DOCTYPE target SYSTEM \"gdb-target.dtd\">" +
+		"<target><architecture>dpu</architecture><feature name=\"org.upmem.dpu.core\">" +
+		strings.Join(registers, "") +
+		"</feature></target>"
+
+	words := strings.Split(annex, ",")
+	if len(words) != 2 {
+		return "E01"
+	}
+
+	offset, offset_err := strconv.ParseInt(words[0], 16, 64)
+	length, length_err := strconv.ParseInt(words[1], 16, 64)
+	if offset_err != nil || length_err != nil {
+		return "E01"
+	}
+
+	if offset >= int64(len(target_xml)) {
+		return "l"
+	} else if offset+length >= int64(len(target_xml)) {
+		return "l" + target_xml[offset:]
+	}
+	return "m" + target_xml[offset:offset+length]
+}
+
+func (this *GdbServer) HandleSetThread(thread string) string {
+	if thread == "0" || thread == "-1" {
+		return "OK"
+	}
+
+	thread_, err := this.ParseThread(thread)
+	if err != nil {
+		return "E01"
+	}
+
+	this.thread_id = thread_.ThreadId()
+	return "OK"
+}
+
+func (this *GdbServer) HandleReadRegisters() string {
+	registers := ""
+	for i := 0; i < this.NumRegisters(); i++ {
+		registers += this.EncodeRegister(this.ReadRegister(i))
+	}
+	return registers
+}
+
+func (this *GdbServer) HandleWriteRegisters(registers string) string {
+	if len(registers) != 8*this.NumRegisters() {
+		return "E01"
+	}
+
+	for i := 0; i < this.NumRegisters(); i++ {
+		value, err := this.DecodeRegister(registers[8*i : 8*i+8])
+		if err != nil {
+			return "E01"
+		}
+
+		this.WriteRegister(i, value)
+	}
+	return "OK"
+}
+
+func (this *GdbServer) HandleReadRegister(index string) string {
+	index_, err := strconv.ParseInt(index, 16, 64)
+	if err != nil || index_ < 0 || index_ >= int64(this.NumRegisters()) {
+		return "E01"
+	}
+
+	return this.EncodeRegister(this.ReadRegister(int(index_)))
+}
+
+func (this *GdbServer) HandleWriteRegister(assignment string) string {
+	words := strings.Split(assignment, "=")
+	if len(words) != 2 {
+		return "E01"
+	}
+
+	index, index_err := strconv.ParseInt(words[0], 16, 64)
+	if index_err != nil || index < 0 || index >= int64(this.NumRegisters()) {
+		return "E01"
+	}
+
+	value, value_err := this.DecodeRegister(words[1])
+	if value_err != nil {
+		return "E01"
+	}
+
+	this.WriteRegister(int(index), value)
+	return "OK"
+}
+
+func (this *GdbServer) HandleReadMemory(range_ string) string {
+	address, size, err := this.ParseRange(range_)
+	if err != nil {
+		return "E01"
+	}
+
+	byte_stream, read_err := this.ReadMemory(address, size)
+	if read_err != nil {
+		return "E01"
+	}
+
+	bytes := make([]byte, 0)
+	for i := int64(0); i < byte_stream.Size(); i++ {
+		bytes = append(bytes, byte_stream.Get(int(i)))
+	}
+	return hex.EncodeToString(bytes)
+}
+
+func (this *GdbServer) HandleWriteMemory(assignment string) string {
+	words := strings.Split(assignment, ":")
+	if len(words) != 2 {
+		return "E01"
+	}
+
+	address, size, err := this.ParseRange(words[0])
+	if err != nil {
+		return "E01"
+	}
+
+	bytes, decode_err := hex.DecodeString(words[1])
+	if decode_err != nil || int64(len(bytes)) != size {
+		return "E01"
+	}
+
+	byte_stream := new(encoding.ByteStream)
+	byte_stream.Init()
+	for _, value := range bytes {
+		byte_stream.Append(value)
+	}
+
+	if write_err := this.WriteMemory(address, byte_stream); write_err != nil {
+		return "E01"
+	}
+	return "OK"
+}
+
+func (this *GdbServer) HandleInsertBreakpoint(breakpoint string) string {
+	address, err := strconv.ParseInt(strings.Split(breakpoint, ",")[0], 16, 64)
+	if err != nil {
+		return "E01"
+	}
+
+	for _, breakpoint_ := range this.breakpoints {
+		if breakpoint_.Address() == address {
+			return "OK"
+		}
+	}
+
+	this.breakpoint_id++
+
+	breakpoint_ := new(Breakpoint)
+	breakpoint_.Init(this.breakpoint_id, address, this.dpu_index, -1)
+
+	this.breakpoints = append(this.breakpoints, breakpoint_)
+	return "OK"
+}
+
+func (this *GdbServer) HandleRemoveBreakpoint(breakpoint string) string {
+	address, err := strconv.ParseInt(strings.Split(breakpoint, ",")[0], 16, 64)
+	if err != nil {
+		return "E01"
+	}
+
+	for i, breakpoint_ := range this.breakpoints {
+		if breakpoint_.Address() == address {
+			this.breakpoints = append(this.breakpoints[:i], this.breakpoints[i+1:]...)
+			return "OK"
+		}
+	}
+	return "OK"
+}
+
+// Resume runs the simulator until a tasklet of the DPU hits a breakpoint, arrives at a fault
+// instruction, or finishes its step, GDB interrupts, or the simulation finishes. A step applies
+// to the current thread unless vCont names another thread.
+func (this *GdbServer) Resume(packet string) string {
+	step_thread_id := -1
+	if packet == "s" {
+		step_thread_id = this.thread_id
+	} else if strings.HasPrefix(packet, "vCont;") {
+		for _, action := range strings.Split(strings.TrimPrefix(packet, "vCont;"), ";") {
+			words := strings.Split(action, ":")
+			if words[0] != "s" && !strings.HasPrefix(words[0], "S") {
+				continue
+			}
+
+			step_thread_id = this.thread_id
+			if len(words) == 2 {
+				if thread, err := this.ParseThread(words[1]); err == nil {
+					step_thread_id = thread.ThreadId()
+				}
+			}
+		}
+	}
+
+	for {
+		if this.simulator.IsFinished() {
+			return "W00"
+		}
+
+		select {
+		case value, is_open := <-this.input:
+			if !is_open || value == 0x03 {
+				return this.StopReply(gdb_sigint)
+			}
+		default:
+		}
+
+		this.simulator.Cycle()
+
+		signal := -1
+		for _, thread := range this.dpu.Threads() {
+			thread_id := thread.ThreadId()
+			pc := thread.RegFile().ReadPcReg()
+			state := thread.StringifyThreadState()
+
+			is_running := state != "EMBRYO" && state != "ZOMBIE"
+			is_arrived := pc != this.pcs[thread_id] || this.states[thread_id] == "EMBRYO" ||
+				this.states[thread_id] == "ZOMBIE"
+
+			if signal == -1 && is_running && is_arrived {
+				if this.IsFault(pc) {
+					this.thread_id = thread_id
+					signal = gdb_sigill
+				} else if thread_id == step_thread_id {
+					this.thread_id = thread_id
+					signal = gdb_sigtrap
+				} else {
+					for _, breakpoint := range this.breakpoints {
+						if breakpoint.IsHit(this.dpu_index, thread_id, pc) {
+							this.thread_id = thread_id
+							signal = gdb_sigtrap
+							break
+						}
+					}
+				}
+			}
+		}
+
+		this.Observe()
+
+		if signal != -1 {
+			return this.StopReply(signal)
+		}
+	}
+}
+
+func (this *GdbServer) StopReply(signal int) string {
+	return fmt.Sprintf("T%02xthread:%x;", signal, this.thread_id+1)
+}
+
+func (this *GdbServer) Observe() {
+	for _, thread := range this.dpu.Threads() {
+		this.pcs[thread.ThreadId()] = thread.RegFile().ReadPcReg()
+		this.states[thread.ThreadId()] = thread.StringifyThreadState()
+	}
+}
+
+func (this *GdbServer) IsFault(pc int64) bool {
+	iram := this.dpu.Iram()
+	if pc < iram.Address() || pc >= iram.Address()+iram.Size() {
+		return false
+	}
+
+	return iram.Read(pc).OpCode() == instruction.FAULT
+}
+
+func (this *GdbServer) ParseThread(thread string) (*logic.Thread, error) {
+	thread_id, err := strconv.ParseInt(thread, 16, 64)
+	if err != nil {
+		return nil, err
+	} else if thread_id < 1 || thread_id > int64(len(this.dpu.Threads())) {
+		return nil, errors.New("thread ID is out of range")
+	}
+
+	return this.dpu.Threads()[thread_id-1], nil
+}
+
+func (this *GdbServer) ParseRange(range_ string) (int64, int64, error) {
+	words := strings.Split(range_, ",")
+	if len(words) != 2 {
+		return 0, 0, errors.New("range is not valid")
+	}
+
+	address, address_err := strconv.ParseInt(words[0], 16, 64)
+	if address_err != nil {
+		return 0, 0, address_err
+	}
+
+	size, size_err := strconv.ParseInt(words[1], 16, 64)
+	if size_err != nil {
+		return 0, 0, size_err
+	}
+
+	return address, size, nil
+}
+
+func (this *GdbServer) NumRegisters() int {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	return config_loader.NumGpRegisters() + 3
+}
+
+func (this *GdbServer) ReadRegister(index int) int64 {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	reg_file := this.dpu.Threads()[this.thread_id].RegFile()
+
+	if index < config_loader.NumGpRegisters() {
+		gp_reg_descriptor := new(reg_descriptor.GpRegDescriptor)
+		gp_reg_descriptor.Init(index)
+
+		return reg_file.ReadGpReg(gp_reg_descriptor, word.UNSIGNED)
+	} else if index == config_loader.NumGpRegisters() {
+		return reg_file.ReadPcReg()
+	} else if index == config_loader.NumGpRegisters()+1 {
+		if reg_file.ReadFlagReg(instruction.ZERO) {
+			return 1
+		}
+		return 0
+	} else {
+		if reg_file.ReadFlagReg(instruction.CARRY) {
+			return 1
+		}
+		return 0
+	}
+}
+
+func (this *GdbServer) WriteRegister(index int, value int64) {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	reg_file := this.dpu.Threads()[this.thread_id].RegFile()
+
+	if index < config_loader.NumGpRegisters() {
+		gp_reg_descriptor := new(reg_descriptor.GpRegDescriptor)
+		gp_reg_descriptor.Init(index)
+
+		reg_file.WriteGpReg(gp_reg_descriptor, value)
+	} else if index == config_loader.NumGpRegisters() {
+		reg_file.WritePcReg(value)
+	} else {
+		flag := instruction.ZERO
+		if index == config_loader.NumGpRegisters()+2 {
+			flag = instruction.CARRY
+		}
+
+		if value != 0 {
+			reg_file.SetFlag(flag)
+		} else {
+			reg_file.ClearFlag(flag)
+		}
+	}
+}
+
+// registers are sent in the little-endian byte order of the DPU
+func (this *GdbServer) EncodeRegister(value int64) string {
+	bytes := make([]byte, 0)
+	for i := 0; i < 4; i++ {
+		bytes = append(bytes, byte(value>>(8*i)))
+	}
+	return hex.EncodeToString(bytes)
+}
+
+func (this *GdbServer) DecodeRegister(register string) (int64, error) {
+	bytes, err := hex.DecodeString(register)
+	if err != nil {
+		return 0, err
+	} else if len(bytes) != 4 {
+		return 0, errors.New("register is not 32 bits wide")
+	}
+
+	value := int64(0)
+	for i := 0; i < 4; i++ {
+		value |= int64(bytes[i]) << (8 * i)
+	}
+	return value, nil
+}
+
+func (this *GdbServer) ReadMemory(address int64, size int64) (*encoding.ByteStream, error) {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	wram := this.dpu.Wram()
+	iram := this.dpu.Iram()
+
+	if wram.Address() <= address && address+size <= wram.Address()+wram.Size() {
+		return wram.Read(address, size), nil
+	} else if config_loader.MramOffset() <= address &&
+		address+size <= config_loader.MramOffset()+config_loader.MramSize() {
+		return this.dpu.MemoryController().Peek(address, size), nil
+	} else if iram.Address() <= address && address+size <= iram.Address()+iram.Size() {
+		return iram.Peek(address, size), nil
+	}
+	return nil, errors.New("range is out of WRAM, MRAM, and IRAM")
+}
+
+func (this *GdbServer) WriteMemory(address int64, byte_stream *encoding.ByteStream) error {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	wram := this.dpu.Wram()
+	size := byte_stream.Size()
+
+	if wram.Address() <= address && address+size <= wram.Address()+wram.Size() {
+		wram.Write(address, size, byte_stream)
+		return nil
+	} else if config_loader.MramOffset() <= address &&
+		address+size <= config_loader.MramOffset()+config_loader.MramSize() {
+		this.dpu.MemoryController().Poke(address, byte_stream)
+		return nil
+	}
+	return errors.New("range is out of WRAM and MRAM")
+}
@@ -2,7 +2,9 @@ package host
 
 import (
 	"errors"
+	"fmt"
 	"uPIMulator/src/abi/encoding"
+	"uPIMulator/src/misc"
 	"uPIMulator/src/simulator/channel"
 )
 
@@ -65,7 +67,21 @@ func (this *ChannelTransferReadJob) CompareByteStreams(
 
 		for j := int64(0); j < byte_stream_1.Size(); j++ {
 			if byte_stream_1.Get(int(j)) != byte_stream_2.Get(int(j)) {
-				err := errors.New("bytes are different")
+				err_msg := fmt.Sprintf(
+					"bytes are different at address 0x%x: expected 0x%02x, but read 0x%02x",
+					this.channel_message.Address()+j,
+					byte_stream_1.Get(int(j)),
+					byte_stream_2.Get(int(j)),
+				)
+
+				err := new(misc.SimulationError)
+				err.Init(misc.FUNCTIONAL_MISMATCH, err_msg)
+				err.SetComponent("Host")
+				err.SetDpu(
+					this.channel_message.ChannelId(),
+					this.channel_message.RankId(),
+					this.channel_message.DpuIds()[i],
+				)
 				panic(err)
 			}
 		}
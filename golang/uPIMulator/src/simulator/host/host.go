@@ -2,15 +2,18 @@ package host
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
 	"uPIMulator/src/abi/encoding"
 	"uPIMulator/src/core"
+	"uPIMulator/src/linker/kernel"
 	"uPIMulator/src/misc"
 	"uPIMulator/src/simulator/channel"
 	"uPIMulator/src/simulator/dpu"
+	"uPIMulator/src/simulator/dpu/logic"
 )
 
 type Host struct {
@@ -24,8 +27,9 @@ type Host struct {
 	num_dpus_per_rank     int
 	num_tasklets          int
 
-	addresses map[string]int64
-	values    map[string]int64
+	addresses    map[string]int64
+	values       map[string]int64
+	source_lines map[int64]*kernel.SourceLine
 
 	atomic *encoding.ByteStream
 	iram   *encoding.ByteStream
@@ -41,6 +45,8 @@ type Host struct {
 	output_dpu_mram_heap_pointer_name []*Chunk
 
 	channels []*channel.Channel
+
+	worker_pool *core.WorkerPool
 }
 
 func (this *Host) Init(command_line_parser *misc.CommandLineParser) {
@@ -58,6 +64,7 @@ func (this *Host) Init(command_line_parser *misc.CommandLineParser) {
 
 	this.InitAddresses()
 	this.InitValues()
+	this.InitSourceLines()
 	this.InitAtomic()
 	this.InitIram()
 	this.InitWram()
@@ -90,6 +97,49 @@ func (this *Host) InitAddresses() {
 	}
 }
 
+// InitSourceLines reads the lines of the form "address: function file:line_number", which the
+// linker dumps for every instruction in the IRAM. A binary that is linked without them has no source
+// lines, so that its instructions are located by their PCs alone.
+func (this *Host) InitSourceLines() {
+	this.source_lines = make(map[int64]*kernel.SourceLine, 0)
+
+	path := filepath.Join(this.bin_dirpath, "lines.txt")
+	if _, stat_err := os.Stat(path); os.IsNotExist(stat_err) {
+		return
+	}
+
+	file_scanner := new(misc.FileScanner)
+	file_scanner.Init(path)
+
+	lines := file_scanner.ReadLines()
+
+	for _, line := range lines {
+		words := strings.SplitN(line, " ", 3)
+		if len(words) != 3 || !strings.HasSuffix(words[0], ":") || !strings.Contains(words[2], ":") {
+			err_msg := fmt.Sprintf("line (%s) is malformed", line)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+
+		address, address_err := strconv.ParseInt(words[0][:len(words[0])-1], 10, 64)
+		if address_err != nil {
+			panic(address_err)
+		}
+
+		file_end := strings.LastIndex(words[2], ":")
+
+		line_number, line_number_err := strconv.Atoi(words[2][file_end+1:])
+		if line_number_err != nil {
+			panic(line_number_err)
+		}
+
+		source_line := new(kernel.SourceLine)
+		source_line.Init(words[2][:file_end], line_number, words[1])
+
+		this.source_lines[address] = source_line
+	}
+}
+
 func (this *Host) InitValues() {
 	path := filepath.Join(this.bin_dirpath, "values.txt")
 
@@ -220,10 +270,48 @@ func (this *Host) InitByteStream(path string) *encoding.ByteStream {
 }
 
 func (this *Host) Fini() {
+	if this.worker_pool != nil {
+		this.worker_pool.Fini()
+	}
 }
 
 func (this *Host) ConnectChannels(channels []*channel.Channel) {
 	this.channels = channels
+
+	this.InitWorkerPool()
+}
+
+func (this *Host) InitWorkerPool() {
+	if _, found := this.addresses["__sys_end"]; !found {
+		err := errors.New("__sys_end is not found")
+		panic(err)
+	}
+
+	sys_end := this.addresses["__sys_end"]
+
+	this.worker_pool = new(core.WorkerPool)
+	this.worker_pool.Init(this.num_simulation_threads)
+
+	dpus := this.Dpus()
+
+	for _, dpu_ := range dpus {
+		cycle_job := new(CycleJob)
+		cycle_job.Init(sys_end, dpu_)
+
+		this.worker_pool.Enque(cycle_job)
+	}
+}
+
+func (this *Host) Addresses() map[string]int64 {
+	return this.addresses
+}
+
+func (this *Host) Values() map[string]int64 {
+	return this.values
+}
+
+func (this *Host) SourceLines() map[int64]*kernel.SourceLine {
+	return this.source_lines
 }
 
 func (this *Host) NumExecutions() int {
@@ -270,10 +358,76 @@ func (this *Host) Schedule(execution int) {
 }
 
 func (this *Host) Check(execution int) {
+	this.CheckFaults()
+
 	this.ChannelTransferOutputDpuHost(execution)
 	this.ChannelTransferOutputDpuMramHeapPointerName(execution)
 }
 
+// CheckFaults reports the DPUs that a fault has stopped, as dpu_launch of the SDK returns
+// DPU_ERR_DPU_FAULT, with the source line of each faulting instruction if the linker has dumped it.
+// Every faulted DPU is reported before the run stops, and the error names the DPU, the tasklet and
+// the instruction only if a single DPU has faulted.
+func (this *Host) CheckFaults() {
+	faults := make([]string, 0)
+
+	var faulted_dpu *dpu.Dpu
+	var faulted_thread *logic.Thread
+
+	for _, dpu_ := range this.Dpus() {
+		thread := dpu_.FaultedThread()
+
+		if thread != nil {
+			pc := thread.RegFile().ReadPcReg()
+
+			fault := fmt.Sprintf(
+				"DPU%d-%d-%d tasklet %d at pc 0x%x (%s) with %s",
+				dpu_.ChannelId(),
+				dpu_.RankId(),
+				dpu_.DpuId(),
+				thread.ThreadId(),
+				pc,
+				dpu_.Iram().Read(pc).Stringify(),
+				thread.RegFile().StringifyExceptions(),
+			)
+			if source_line, found := this.source_lines[pc]; found {
+				fault += fmt.Sprintf(
+					" in %s at %s:%d",
+					source_line.Function(),
+					source_line.File(),
+					source_line.LineNumber(),
+				)
+			}
+
+			faults = append(faults, fault)
+
+			faulted_dpu = dpu_
+			faulted_thread = thread
+		}
+	}
+
+	if len(faults) > 0 {
+		err_msg := fmt.Sprintf(
+			"%d of %d DPUs have faulted: %s",
+			len(faults),
+			len(this.Dpus()),
+			strings.Join(faults, "; "),
+		)
+
+		err := new(misc.SimulationError)
+		err.Init(misc.DPU_FAULT, err_msg)
+		err.SetComponent("Host")
+
+		if len(faults) == 1 {
+			pc := faulted_thread.RegFile().ReadPcReg()
+
+			err.SetDpu(faulted_dpu.ChannelId(), faulted_dpu.RankId(), faulted_dpu.DpuId())
+			err.SetInstruction(faulted_thread.ThreadId(), pc, faulted_dpu.Iram().Read(pc).Stringify())
+		}
+		panic(err)
+	}
+}
+
 func (this *Host) Launch() {
 	config_loader := new(misc.ConfigLoader)
 	config_loader.Init()
@@ -286,6 +440,7 @@ func (this *Host) Launch() {
 		for _, thread := range threads {
 			bootstrap := config_loader.IramOffset()
 			thread.RegFile().WritePcReg(bootstrap)
+			thread.RegFile().ClearExceptions()
 		}
 
 		dpu_.Boot()
@@ -715,24 +870,5 @@ func (this *Host) FindOutputDpuMramHeapPointerNameChunk(
 }
 
 func (this *Host) Cycle() {
-	if _, found := this.addresses["__sys_end"]; !found {
-		err := errors.New("__sys_end is not found")
-		panic(err)
-	}
-
-	sys_end := this.addresses["__sys_end"]
-
-	thread_pool := new(core.ThreadPool)
-	thread_pool.Init(this.num_simulation_threads)
-
-	dpus := this.Dpus()
-
-	for _, dpu_ := range dpus {
-		cycle_job := new(CycleJob)
-		cycle_job.Init(sys_end, dpu_)
-
-		thread_pool.Enque(cycle_job)
-	}
-
-	thread_pool.Start()
+	this.worker_pool.Start()
 }
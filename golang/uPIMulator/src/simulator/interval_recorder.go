@@ -0,0 +1,180 @@
+package simulator
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"uPIMulator/src/misc"
+	"uPIMulator/src/simulator/channel"
+)
+
+// An IntervalRecorder writes how much the selected stats have grown since the previous record,
+// either per DPU or summed over all DPUs, as rows of a CSV time series.
+type IntervalRecorder struct {
+	channels []*channel.Channel
+
+	patterns  []string
+	aggregate bool
+
+	previous []map[string]map[string]int64
+
+	file_streamer *misc.FileStreamer
+}
+
+func (this *IntervalRecorder) Init(
+	channels []*channel.Channel,
+	path_ string,
+	command_line_parser *misc.CommandLineParser,
+) {
+	this.channels = channels
+
+	this.patterns = strings.Split(command_line_parser.StringParameter("interval_stats"), ",")
+	for _, pattern := range this.patterns {
+		if _, match_err := path.Match(pattern, ""); match_err != nil {
+			err_msg := fmt.Sprintf("interval stat pattern (%s) is malformed", pattern)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+	}
+
+	this.aggregate = command_line_parser.BoolParameter("interval_aggregate")
+
+	this.RecordBaseline()
+
+	this.file_streamer = new(misc.FileStreamer)
+	this.file_streamer.Init(path_)
+	this.file_streamer.WriteLines([]string{"cycle,channel_id,rank_id,dpu_id,component,stat,value"})
+}
+
+func (this *IntervalRecorder) Fini() {
+	this.file_streamer.Fini()
+}
+
+func (this *IntervalRecorder) RecordBaseline() {
+	this.previous = this.Snapshot()
+}
+
+func (this *IntervalRecorder) Snapshot() []map[string]map[string]int64 {
+	snapshot := make([]map[string]map[string]int64, 0)
+
+	for _, channel_ := range this.channels {
+		for _, dpu_ := range channel_.Dpus() {
+			snapshot = append(snapshot, dpu_.ComponentStats())
+		}
+	}
+
+	return snapshot
+}
+
+func (this *IntervalRecorder) IsSelected(stat string) bool {
+	for _, pattern := range this.patterns {
+		if is_matched, _ := path.Match(pattern, stat); is_matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (this *IntervalRecorder) Record(cycles int64) {
+	snapshot := this.Snapshot()
+
+	lines := make([]string, 0)
+	aggregated_stats := make(map[string]map[string]int64, 0)
+
+	index := 0
+	for _, channel_ := range this.channels {
+		for _, rank_ := range channel_.Ranks() {
+			for _, dpu_ := range rank_.Dpus() {
+				delta := this.Delta(this.previous[index], snapshot[index])
+
+				if this.aggregate {
+					for component, stats := range delta {
+						if _, found := aggregated_stats[component]; !found {
+							aggregated_stats[component] = make(map[string]int64, 0)
+						}
+
+						for stat, value := range stats {
+							aggregated_stats[component][stat] += value
+						}
+					}
+				} else {
+					channel_id := fmt.Sprintf("%d", channel_.ChannelId())
+					rank_id := fmt.Sprintf("%d", rank_.RankId())
+					dpu_id := fmt.Sprintf("%d", dpu_.DpuId())
+
+					lines = append(lines, this.ToLines(cycles, channel_id, rank_id, dpu_id, delta)...)
+				}
+
+				index++
+			}
+		}
+	}
+
+	if this.aggregate {
+		lines = append(lines, this.ToLines(cycles, "all", "all", "all", aggregated_stats)...)
+	}
+
+	this.file_streamer.WriteLines(lines)
+
+	this.previous = snapshot
+}
+
+func (this *IntervalRecorder) Delta(
+	previous map[string]map[string]int64,
+	current map[string]map[string]int64,
+) map[string]map[string]int64 {
+	delta := make(map[string]map[string]int64, 0)
+
+	for component, stats := range current {
+		delta[component] = make(map[string]int64, 0)
+
+		for stat, value := range stats {
+			if this.IsSelected(stat) {
+				delta[component][stat] = value - previous[component][stat]
+			}
+		}
+	}
+
+	return delta
+}
+
+func (this *IntervalRecorder) ToLines(
+	cycles int64,
+	channel_id string,
+	rank_id string,
+	dpu_id string,
+	component_stats map[string]map[string]int64,
+) []string {
+	lines := make([]string, 0)
+
+	components := make([]string, 0)
+	for component := range component_stats {
+		components = append(components, component)
+	}
+	slices.Sort(components)
+
+	for _, component := range components {
+		stats := make([]string, 0)
+		for stat := range component_stats[component] {
+			stats = append(stats, stat)
+		}
+		slices.Sort(stats)
+
+		for _, stat := range stats {
+			fields := []string{
+				fmt.Sprintf("%d", cycles),
+				channel_id,
+				rank_id,
+				dpu_id,
+				component,
+				stat,
+				fmt.Sprintf("%d", component_stats[component][stat]),
+			}
+			lines = append(lines, strings.Join(fields, ","))
+		}
+	}
+
+	return lines
+}
@@ -0,0 +1,265 @@
+package simulator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"uPIMulator/src/linker/kernel"
+	"uPIMulator/src/misc"
+	"uPIMulator/src/simulator/dpu"
+)
+
+// A ProfileWriter sums the profiles of all DPUs and attributes them to the source lines and the
+// functions of the PC map that the linker has dumped. It writes a flat profile per function and
+// per source line, followed by the source files annotated with their counts. The source files are
+// looked up at the paths of the .file directives first, and then under the root directory, since
+// the benchmarks may have been compiled in a container.
+type ProfileWriter struct {
+	root_dirpath string
+
+	profilers    []*misc.Profiler
+	source_lines map[int64]*kernel.SourceLine
+}
+
+func (this *ProfileWriter) Init(
+	dpus []*dpu.Dpu,
+	source_lines map[int64]*kernel.SourceLine,
+	command_line_parser *misc.CommandLineParser,
+) {
+	this.root_dirpath = command_line_parser.StringParameter("root_dirpath")
+
+	this.profilers = make([]*misc.Profiler, 0)
+	for _, dpu_ := range dpus {
+		profiler := new(misc.Profiler)
+		profiler.Init()
+
+		dpu_.ConnectProfiler(profiler)
+
+		this.profilers = append(this.profilers, profiler)
+	}
+
+	this.source_lines = source_lines
+}
+
+// RecordBaseline discards the profiles, e.g., those of the executions that a restored checkpoint
+// has skipped over.
+func (this *ProfileWriter) RecordBaseline() {
+	for _, profiler := range this.profilers {
+		profiler.Reset()
+	}
+}
+
+func (this *ProfileWriter) Write(path string) {
+	function_counts := make(map[string][]int64, 0)
+	line_counts := make(map[string][]int64, 0)
+	file_counts := make(map[string]map[int][]int64, 0)
+	total_counts := make([]int64, 3)
+
+	for _, profiler := range this.profilers {
+		counts := []map[int64]int64{
+			profiler.NumInstructions(),
+			profiler.NumStallCycles(),
+			profiler.NumDmaWaitCycles(),
+		}
+
+		for i, pc_counts := range counts {
+			for pc, count := range pc_counts {
+				source_line := this.SourceLine(pc)
+
+				location := fmt.Sprintf("%s:%d", source_line.File(), source_line.LineNumber())
+
+				if _, found := function_counts[source_line.Function()]; !found {
+					function_counts[source_line.Function()] = make([]int64, 3)
+				}
+				if _, found := line_counts[location]; !found {
+					line_counts[location] = make([]int64, 3)
+				}
+				if _, found := file_counts[source_line.File()]; !found {
+					file_counts[source_line.File()] = make(map[int][]int64, 0)
+				}
+				if _, found := file_counts[source_line.File()][source_line.LineNumber()]; !found {
+					file_counts[source_line.File()][source_line.LineNumber()] = make([]int64, 3)
+				}
+
+				function_counts[source_line.Function()][i] += count
+				line_counts[location][i] += count
+				file_counts[source_line.File()][source_line.LineNumber()][i] += count
+				total_counts[i] += count
+			}
+		}
+	}
+
+	lines := make([]string, 0)
+
+	lines = append(lines, "== flat profile by function ==")
+	lines = append(lines, this.ToFlatLines(function_counts, total_counts, "function")...)
+	lines = append(lines, "")
+
+	lines = append(lines, "== flat profile by source line ==")
+	lines = append(lines, this.ToFlatLines(line_counts, total_counts, "source line")...)
+
+	files := make([]string, 0)
+	for file, _ := range file_counts {
+		if file != "??" {
+			files = append(files, file)
+		}
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		lines = append(lines, "")
+		lines = append(lines, fmt.Sprintf("== %s ==", file))
+		lines = append(lines, this.ToAnnotatedLines(file, file_counts[file])...)
+	}
+
+	file_dumper := new(misc.FileDumper)
+	file_dumper.Init(path)
+	file_dumper.WriteLines(lines)
+}
+
+func (this *ProfileWriter) SourceLine(pc int64) *kernel.SourceLine {
+	if source_line, found := this.source_lines[pc]; found {
+		return source_line
+	}
+
+	source_line := new(kernel.SourceLine)
+	source_line.Init("??", 0, "??")
+	return source_line
+}
+
+// ToFlatLines sorts the entries by the issued instructions first and by the stall and DMA wait
+// cycles next.
+func (this *ProfileWriter) ToFlatLines(
+	counts map[string][]int64,
+	total_counts []int64,
+	name string,
+) []string {
+	names := make([]string, 0)
+	for name_, _ := range counts {
+		names = append(names, name_)
+	}
+
+	sort_fn := func(i int, j int) bool {
+		for k := 0; k < 3; k++ {
+			if counts[names[i]][k] != counts[names[j]][k] {
+				return counts[names[i]][k] > counts[names[j]][k]
+			}
+		}
+		return names[i] < names[j]
+	}
+
+	sort.Slice(names, sort_fn)
+
+	lines := make([]string, 0)
+	lines = append(
+		lines,
+		fmt.Sprintf("%14s %7s %14s %7s %16s %7s  %s", "instructions", "%", "stall_cycles", "%", "dma_wait_cycles", "%", name),
+	)
+
+	for _, name_ := range names {
+		line := fmt.Sprintf(
+			"%14d %6.2f%% %14d %6.2f%% %16d %6.2f%%  %s",
+			counts[name_][0],
+			this.Percentage(counts[name_][0], total_counts[0]),
+			counts[name_][1],
+			this.Percentage(counts[name_][1], total_counts[1]),
+			counts[name_][2],
+			this.Percentage(counts[name_][2], total_counts[2]),
+			name_,
+		)
+		lines = append(lines, line)
+	}
+
+	lines = append(
+		lines,
+		fmt.Sprintf("%14d %7s %14d %7s %16d %7s  %s", total_counts[0], "", total_counts[1], "", total_counts[2], "", "total"),
+	)
+
+	return lines
+}
+
+func (this *ProfileWriter) ToAnnotatedLines(file string, counts map[int][]int64) []string {
+	lines := make([]string, 0)
+	lines = append(
+		lines,
+		fmt.Sprintf("%14s %14s %16s %6s  %s", "instructions", "stall_cycles", "dma_wait_cycles", "line", "source"),
+	)
+
+	source_path := this.FindSource(file)
+	if source_path == "" {
+		lines = append(lines, "(the source is not found, so only the profiled lines are listed)")
+
+		line_numbers := make([]int, 0)
+		for line_number, _ := range counts {
+			line_numbers = append(line_numbers, line_number)
+		}
+		sort.Ints(line_numbers)
+
+		for _, line_number := range line_numbers {
+			lines = append(lines, this.ToAnnotatedLine(counts[line_number], line_number, ""))
+		}
+
+		return lines
+	}
+
+	file_scanner := new(misc.FileScanner)
+	file_scanner.Init(source_path)
+
+	source := file_scanner.ReadLines()
+
+	// line 0 holds the instructions that the compiler has not attributed to any line
+	if counts_, found := counts[0]; found {
+		lines = append(lines, this.ToAnnotatedLine(counts_, 0, "<no line>"))
+	}
+
+	for i, source_line := range source {
+		lines = append(lines, this.ToAnnotatedLine(counts[i+1], i+1, source_line))
+	}
+
+	return lines
+}
+
+func (this *ProfileWriter) ToAnnotatedLine(counts []int64, line_number int, source_line string) string {
+	if counts == nil {
+		return fmt.Sprintf("%14s %14s %16s %6d  %s", "", "", "", line_number, source_line)
+	}
+
+	return fmt.Sprintf(
+		"%14d %14d %16d %6d  %s",
+		counts[0],
+		counts[1],
+		counts[2],
+		line_number,
+		source_line,
+	)
+}
+
+// FindSource returns the path of the source file, or an empty string if it is not found. A path
+// that does not exist is retried under the root directory with its leading directories removed
+// one by one, e.g., /root/uPIMulator/benchmark/VA/dpu/task.c is retried as benchmark/VA/dpu/task.c.
+func (this *ProfileWriter) FindSource(file string) string {
+	if _, err := os.Stat(file); err == nil {
+		return file
+	}
+
+	words := strings.Split(filepath.ToSlash(file), "/")
+	for i := 1; i < len(words); i++ {
+		path := filepath.Join(this.root_dirpath, filepath.Join(words[i:]...))
+
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+
+	return ""
+}
+
+func (this *ProfileWriter) Percentage(count int64, total_count int64) float64 {
+	if total_count == 0 {
+		return 0
+	}
+
+	return 100 * float64(count) / float64(total_count)
+}
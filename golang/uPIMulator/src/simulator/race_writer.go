@@ -0,0 +1,79 @@
+package simulator
+
+import (
+	"fmt"
+	"uPIMulator/src/misc"
+	"uPIMulator/src/simulator/dpu"
+)
+
+// A RaceWriter reports the data races that the race detectors of the DPUs have found, with the
+// tasklets and the PCs of both accesses and the WRAM address where they first conflicted.
+type RaceWriter struct {
+	dpus           []*dpu.Dpu
+	race_detectors []*misc.RaceDetector
+
+	symbolizer *Symbolizer
+}
+
+func (this *RaceWriter) Init(dpus []*dpu.Dpu, symbolizer *Symbolizer) {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	this.dpus = dpus
+
+	this.race_detectors = make([]*misc.RaceDetector, 0)
+	for _, dpu_ := range dpus {
+		race_detector := new(misc.RaceDetector)
+		race_detector.Init(config_loader.MaxNumTasklets())
+
+		dpu_.ConnectRaceDetector(race_detector)
+
+		this.race_detectors = append(this.race_detectors, race_detector)
+	}
+
+	this.symbolizer = symbolizer
+}
+
+func (this *RaceWriter) RecordBaseline() {
+	for _, race_detector := range this.race_detectors {
+		race_detector.Launch()
+		race_detector.Reset()
+	}
+}
+
+func (this *RaceWriter) NumRaces() int {
+	num_races := 0
+	for _, race_detector := range this.race_detectors {
+		num_races += len(race_detector.Races())
+	}
+	return num_races
+}
+
+func (this *RaceWriter) Write(path string) {
+	lines := make([]string, 0)
+
+	for i, race_detector := range this.race_detectors {
+		dpu_ := this.dpus[i]
+
+		for _, race := range race_detector.Races() {
+			line := fmt.Sprintf(
+				"DPU%d-%d-%d: %s race on %s between tasklet %d at %s and tasklet %d at %s (%d occurrences)",
+				dpu_.ChannelId(),
+				dpu_.RankId(),
+				dpu_.DpuId(),
+				race.Kind(),
+				this.symbolizer.WramAddress(race.Address()),
+				race.First().ThreadId(),
+				this.symbolizer.Pc(race.First().Pc()),
+				race.Second().ThreadId(),
+				this.symbolizer.Pc(race.Second().Pc()),
+				race.NumOccurrences(),
+			)
+			lines = append(lines, line)
+		}
+	}
+
+	file_dumper := new(misc.FileDumper)
+	file_dumper.Init(path)
+	file_dumper.WriteLines(lines)
+}
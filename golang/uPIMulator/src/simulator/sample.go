@@ -0,0 +1,28 @@
+package simulator
+
+type Sample struct {
+	num_instructions int64
+	stats            map[string]int64
+}
+
+func (this *Sample) Init(
+	begin_num_instructions int64,
+	end_num_instructions int64,
+	begin_stats map[string]int64,
+	end_stats map[string]int64,
+) {
+	this.num_instructions = end_num_instructions - begin_num_instructions
+
+	this.stats = make(map[string]int64, 0)
+	for stat, value := range end_stats {
+		this.stats[stat] = value - begin_stats[stat]
+	}
+}
+
+func (this *Sample) NumInstructions() int64 {
+	return this.num_instructions
+}
+
+func (this *Sample) Value(stat string) int64 {
+	return this.stats[stat]
+}
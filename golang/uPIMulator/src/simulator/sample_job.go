@@ -0,0 +1,13 @@
+package simulator
+
+type SampleJob struct {
+	sampler *Sampler
+}
+
+func (this *SampleJob) Init(sampler *Sampler) {
+	this.sampler = sampler
+}
+
+func (this *SampleJob) Execute() {
+	this.sampler.Cycle()
+}
@@ -0,0 +1,312 @@
+package simulator
+
+import (
+	"fmt"
+	"math"
+	"uPIMulator/src/misc"
+	"uPIMulator/src/simulator/dpu"
+)
+
+type SamplingPhase int
+
+const (
+	WARMUP SamplingPhase = iota
+	WINDOW
+	DRAIN
+	FUNCTIONAL
+)
+
+// z-score of the two-sided 95% confidence interval
+const confidence_z = 1.96
+
+// A Sampler alternates a DPU between the functional mode and the timing mode. Each sample runs
+// sampling_warmup unmeasured logic cycles followed by sampling_window measured logic cycles,
+// drains the in-flight instructions, and then executes sampling_period instructions functionally.
+// The timing stats, e.g., the cycles and the stalls, are extrapolated from the samples with a ratio
+// estimator over the executed instructions, while the counters that the functional mode keeps
+// counting, i.e., the instructions and the reads and the writes of the MRAM, are exact.
+type Sampler struct {
+	dpu *dpu.Dpu
+
+	sampling_period int64
+	sampling_warmup int64
+	sampling_window int64
+
+	sampling_phase   SamplingPhase
+	num_phase_cycles int64
+
+	begin_num_instructions int64
+	begin_stats            map[string]int64
+
+	num_functional_instructions int64
+
+	samples []*Sample
+}
+
+func (this *Sampler) Init(dpu_ *dpu.Dpu, command_line_parser *misc.CommandLineParser) {
+	this.dpu = dpu_
+
+	this.sampling_period = command_line_parser.IntParameter("sampling_period")
+	this.sampling_warmup = command_line_parser.IntParameter("sampling_warmup")
+	this.sampling_window = command_line_parser.IntParameter("sampling_window")
+
+	this.num_functional_instructions = 0
+
+	this.samples = make([]*Sample, 0)
+
+	this.Begin(WARMUP)
+}
+
+func (this *Sampler) Dpu() *dpu.Dpu {
+	return this.dpu
+}
+
+func (this *Sampler) NumSamples() int {
+	return len(this.samples)
+}
+
+func (this *Sampler) Cycle() {
+	if this.dpu.IsZombie() {
+		if this.sampling_phase == WINDOW {
+			this.End()
+			this.Begin(DRAIN)
+		}
+		return
+	}
+
+	this.Advance()
+
+	if this.sampling_phase == WARMUP || this.sampling_phase == WINDOW {
+		this.dpu.Cycle()
+	} else if this.sampling_phase == DRAIN {
+		this.dpu.Drain()
+	} else {
+		this.dpu.Step()
+	}
+
+	this.num_phase_cycles++
+}
+
+func (this *Sampler) Advance() {
+	for {
+		if this.sampling_phase == WARMUP && this.num_phase_cycles >= this.sampling_warmup {
+			this.Begin(WINDOW)
+		} else if this.sampling_phase == WINDOW && this.num_phase_cycles >= this.sampling_window {
+			this.End()
+			this.Begin(DRAIN)
+		} else if this.sampling_phase == DRAIN && this.dpu.IsDrained() {
+			this.Begin(FUNCTIONAL)
+		} else if this.sampling_phase == FUNCTIONAL &&
+			this.NumInstructions()-this.begin_num_instructions >= this.sampling_period {
+			this.num_functional_instructions += this.NumInstructions() - this.begin_num_instructions
+			this.Begin(WARMUP)
+		} else {
+			return
+		}
+	}
+}
+
+func (this *Sampler) Begin(sampling_phase SamplingPhase) {
+	this.sampling_phase = sampling_phase
+	this.num_phase_cycles = 0
+
+	this.begin_num_instructions = this.NumInstructions()
+
+	if sampling_phase == WINDOW {
+		this.begin_stats = this.Stats()
+	}
+}
+
+func (this *Sampler) End() {
+	sample := new(Sample)
+	sample.Init(this.begin_num_instructions, this.NumInstructions(), this.begin_stats, this.Stats())
+
+	this.samples = append(this.samples, sample)
+}
+
+func (this *Sampler) NumInstructions() int64 {
+	return this.dpu.Logic().StatFactory().Value("num_instructions")
+}
+
+func (this *Sampler) Stats() map[string]int64 {
+	stats := make(map[string]int64, 0)
+
+	for _, stat_factory := range this.dpu.StatFactories() {
+		for _, stat := range stat_factory.Stats() {
+			stats[this.Key(stat_factory, stat)] = stat_factory.Value(stat)
+		}
+	}
+
+	return stats
+}
+
+func (this *Sampler) Key(stat_factory *misc.StatFactory, stat string) string {
+	return fmt.Sprintf("%s_%s", stat_factory.Name(), stat)
+}
+
+func (this *Sampler) IsExact() bool {
+	num_functional_instructions := this.num_functional_instructions
+	if this.sampling_phase == FUNCTIONAL {
+		num_functional_instructions += this.NumInstructions() - this.begin_num_instructions
+	}
+
+	return num_functional_instructions == 0
+}
+
+// Estimate returns the ratio of the numerator to the denominator over the samples and the
+// half width of its confidence interval, which is NaN with fewer than two samples.
+func (this *Sampler) Estimate(numerators []int64, denominators []int64) (float64, float64) {
+	num_samples := len(numerators)
+
+	numerator_sum := int64(0)
+	denominator_sum := int64(0)
+	for i := 0; i < num_samples; i++ {
+		numerator_sum += numerators[i]
+		denominator_sum += denominators[i]
+	}
+
+	if denominator_sum == 0 {
+		return 0, math.NaN()
+	}
+
+	ratio := float64(numerator_sum) / float64(denominator_sum)
+
+	if num_samples < 2 {
+		return ratio, math.NaN()
+	}
+
+	denominator_mean := float64(denominator_sum) / float64(num_samples)
+
+	squared_error_sum := 0.0
+	for i := 0; i < num_samples; i++ {
+		residual := float64(numerators[i]) - ratio*float64(denominators[i])
+		squared_error_sum += residual * residual
+	}
+
+	variance := squared_error_sum / float64(num_samples*(num_samples-1)) / (denominator_mean * denominator_mean)
+
+	return ratio, confidence_z * math.Sqrt(variance)
+}
+
+// IsExactStat tells whether a stat is counted in every phase, the functional one included, so that
+// it is exact rather than extrapolated.
+func (this *Sampler) IsExactStat(stat_factory *misc.StatFactory, stat string) bool {
+	if stat_factory == this.dpu.Logic().StatFactory() {
+		return stat == "num_instructions"
+	} else if stat_factory == this.dpu.MemoryController().RowBuffer().StatFactory() {
+		return stat == "num_reads" || stat == "read_bytes" || stat == "num_writes" || stat == "write_bytes"
+	}
+	return false
+}
+
+func (this *Sampler) Extrapolate(stat_factory *misc.StatFactory, stat string) (int64, float64) {
+	if this.IsExact() || this.IsExactStat(stat_factory, stat) {
+		return stat_factory.Value(stat), 0
+	}
+
+	key := this.Key(stat_factory, stat)
+
+	numerators := make([]int64, 0)
+	denominators := make([]int64, 0)
+	for _, sample := range this.samples {
+		numerators = append(numerators, sample.Value(key))
+		denominators = append(denominators, sample.NumInstructions())
+	}
+
+	ratio, half_width := this.Estimate(numerators, denominators)
+
+	num_instructions := float64(this.NumInstructions())
+
+	return int64(math.Round(ratio * num_instructions)), half_width * num_instructions
+}
+
+func (this *Sampler) ComponentStats() map[string]map[string]int64 {
+	component_stats := make(map[string]map[string]int64, 0)
+
+	for component, stat_factory := range this.dpu.StatComponents() {
+		component_stats[component] = make(map[string]int64, 0)
+
+		for _, stat := range stat_factory.Stats() {
+			value, _ := this.Extrapolate(stat_factory, stat)
+			component_stats[component][stat] = value
+		}
+	}
+
+	return component_stats
+}
+
+func (this *Sampler) RowBufferHitRate() (float64, float64) {
+	row_buffer_stat_factory := this.dpu.MemoryController().RowBuffer().StatFactory()
+
+	num_activations_key := this.Key(row_buffer_stat_factory, "num_activations")
+	num_reads_key := this.Key(row_buffer_stat_factory, "num_reads")
+	num_writes_key := this.Key(row_buffer_stat_factory, "num_writes")
+
+	if this.IsExact() {
+		num_accesses := row_buffer_stat_factory.Value("num_reads") + row_buffer_stat_factory.Value("num_writes")
+		num_hits := num_accesses - row_buffer_stat_factory.Value("num_activations")
+
+		if num_accesses == 0 {
+			return 0, 0
+		}
+		return float64(num_hits) / float64(num_accesses), 0
+	}
+
+	numerators := make([]int64, 0)
+	denominators := make([]int64, 0)
+	for _, sample := range this.samples {
+		num_accesses := sample.Value(num_reads_key) + sample.Value(num_writes_key)
+		num_hits := num_accesses - sample.Value(num_activations_key)
+
+		numerators = append(numerators, num_hits)
+		denominators = append(denominators, num_accesses)
+	}
+
+	return this.Estimate(numerators, denominators)
+}
+
+func (this *Sampler) ToLines() []string {
+	lines := make([]string, 0)
+
+	for _, stat_factory := range this.dpu.StatFactories() {
+		for _, stat := range stat_factory.Stats() {
+			value, _ := this.Extrapolate(stat_factory, stat)
+
+			line := fmt.Sprintf("%s: %d", this.Key(stat_factory, stat), value)
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
+
+func (this *Sampler) ToConfidenceLines() []string {
+	lines := make([]string, 0)
+
+	dpu_stat_factory := this.dpu.StatFactory()
+
+	num_samples_line := fmt.Sprintf("%s: %d", this.Key(dpu_stat_factory, "num_samples"), len(this.samples))
+	lines = append(lines, num_samples_line)
+
+	for _, stat_factory := range this.dpu.StatFactories() {
+		for _, stat := range stat_factory.Stats() {
+			value, half_width := this.Extrapolate(stat_factory, stat)
+
+			line := fmt.Sprintf("%s: %d +- %.1f", this.Key(stat_factory, stat), value, half_width)
+			lines = append(lines, line)
+		}
+	}
+
+	row_buffer_stat_factory := this.dpu.MemoryController().RowBuffer().StatFactory()
+	hit_rate, half_width := this.RowBufferHitRate()
+
+	hit_rate_line := fmt.Sprintf(
+		"%s: %.4f +- %.4f",
+		this.Key(row_buffer_stat_factory, "hit_rate"),
+		hit_rate,
+		half_width,
+	)
+	lines = append(lines, hit_rate_line)
+
+	return lines
+}
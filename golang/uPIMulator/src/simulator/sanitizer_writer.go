@@ -0,0 +1,64 @@
+package simulator
+
+import (
+	"fmt"
+	"uPIMulator/src/misc"
+	"uPIMulator/src/simulator/dpu"
+)
+
+// A SanitizerWriter reports the findings of the sanitizers of the DPUs, one line per check that
+// has failed at a PC, followed by the number of its occurrences.
+type SanitizerWriter struct {
+	sanitizers []*misc.Sanitizer
+}
+
+func (this *SanitizerWriter) Init(
+	dpus []*dpu.Dpu,
+	values map[string]int64,
+	symbolizer *Symbolizer,
+	command_line_parser *misc.CommandLineParser,
+) {
+	min_access_granularity := command_line_parser.IntParameter("min_access_granularity")
+
+	this.sanitizers = make([]*misc.Sanitizer, 0)
+	for _, dpu_ := range dpus {
+		name := fmt.Sprintf("DPU%d-%d-%d", dpu_.ChannelId(), dpu_.RankId(), dpu_.DpuId())
+
+		sanitizer := new(misc.Sanitizer)
+		sanitizer.Init(name, values, min_access_granularity, symbolizer)
+
+		dpu_.ConnectSanitizer(sanitizer)
+
+		this.sanitizers = append(this.sanitizers, sanitizer)
+	}
+}
+
+func (this *SanitizerWriter) RecordBaseline() {
+	for _, sanitizer := range this.sanitizers {
+		sanitizer.Reset()
+		sanitizer.InitializeAll()
+	}
+}
+
+func (this *SanitizerWriter) NumFindings() int {
+	num_findings := 0
+	for _, sanitizer := range this.sanitizers {
+		num_findings += len(sanitizer.Findings())
+	}
+	return num_findings
+}
+
+func (this *SanitizerWriter) Write(path string) {
+	lines := make([]string, 0)
+
+	for _, sanitizer := range this.sanitizers {
+		for _, finding := range sanitizer.Findings() {
+			line := fmt.Sprintf("%s: %s (%d occurrences)", finding.Check(), finding.Message(), finding.NumOccurrences())
+			lines = append(lines, line)
+		}
+	}
+
+	file_dumper := new(misc.FileDumper)
+	file_dumper.Init(path)
+	file_dumper.WriteLines(lines)
+}
@@ -1,14 +1,22 @@
 package simulator
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"path/filepath"
+	"slices"
+	"strings"
 	"uPIMulator/src/core"
 	"uPIMulator/src/misc"
 	"uPIMulator/src/simulator/channel"
 	"uPIMulator/src/simulator/host"
 )
 
+// The version of the checkpoint format, which is bumped whenever what is checkpointed changes, so
+// that a checkpoint of another version is rejected before it is misread.
+const checkpoint_version = 1
+
 type Simulator struct {
 	host     *host.Host
 	channels []*channel.Channel
@@ -16,6 +24,36 @@ type Simulator struct {
 	bin_dirpath            string
 	num_simulation_threads int
 	execution              int
+	cycles                 int64
+	is_hung                bool
+
+	benchmark         string
+	num_tasklets      int
+	wordline_size     int64
+	checkpoint_period int64
+	interval_period   int64
+
+	mode          string
+	fast_forward  bool
+	stats_formats []string
+	trace         bool
+	profile       bool
+	call_graph    bool
+	detect_races  bool
+	sanitize      bool
+
+	samplers          []*Sampler
+	fast_forward_jobs []*FastForwardJob
+	skip_jobs         []*SkipJob
+	worker_pool       *core.WorkerPool
+	stat_exporter     *StatExporter
+	interval_recorder *IntervalRecorder
+	trace_writer      *TraceWriter
+	profile_writer    *ProfileWriter
+	call_graph_writer *CallGraphWriter
+	race_writer       *RaceWriter
+	sanitizer_writer  *SanitizerWriter
+	watchdog          *Watchdog
 
 	verbose int
 }
@@ -37,47 +75,201 @@ func (this *Simulator) Init(command_line_parser *misc.CommandLineParser) {
 
 	this.host.ConnectChannels(this.channels)
 
-	this.bin_dirpath = command_line_parser.StringParameter("bin_dirpath")
+	this.mode = command_line_parser.StringParameter("mode")
+
 	this.num_simulation_threads = int(command_line_parser.IntParameter("num_simulation_threads"))
+	this.worker_pool = new(core.WorkerPool)
+	this.worker_pool.Init(this.num_simulation_threads)
+
+	this.fast_forward = command_line_parser.BoolParameter("fast_forward")
+
+	this.samplers = make([]*Sampler, 0)
+	this.fast_forward_jobs = make([]*FastForwardJob, 0)
+	this.skip_jobs = make([]*SkipJob, 0)
+
+	dpus := this.host.Dpus()
+	for _, dpu_ := range dpus {
+		if this.fast_forward {
+			fast_forward_job := new(FastForwardJob)
+			fast_forward_job.Init(dpu_)
+
+			this.fast_forward_jobs = append(this.fast_forward_jobs, fast_forward_job)
+			this.worker_pool.Enque(fast_forward_job)
+		} else if this.mode == "sampled" {
+			sampler := new(Sampler)
+			sampler.Init(dpu_, command_line_parser)
+
+			this.samplers = append(this.samplers, sampler)
+
+			sample_job := new(SampleJob)
+			sample_job.Init(sampler)
+
+			this.worker_pool.Enque(sample_job)
+		} else if this.mode == "functional" {
+			step_job := new(StepJob)
+			step_job.Init(dpu_)
+
+			this.worker_pool.Enque(step_job)
+		} else {
+			cycle_job := new(CycleJob)
+			cycle_job.Init(dpu_)
+
+			this.worker_pool.Enque(cycle_job)
+		}
+	}
+
+	// the skip jobs follow the fast-forward jobs so that both are spread over the workers alike
+	if this.fast_forward {
+		for _, dpu_ := range dpus {
+			skip_job := new(SkipJob)
+			skip_job.Init(dpu_)
+
+			this.skip_jobs = append(this.skip_jobs, skip_job)
+			this.worker_pool.Enque(skip_job)
+		}
+	}
+
+	this.bin_dirpath = command_line_parser.StringParameter("bin_dirpath")
 	this.execution = 0
+	this.cycles = 0
+	this.is_hung = false
+
+	this.benchmark = command_line_parser.StringParameter("benchmark")
+	this.num_tasklets = int(command_line_parser.IntParameter("num_tasklets"))
+	this.wordline_size = command_line_parser.IntParameter("wordline_size")
+	this.checkpoint_period = command_line_parser.IntParameter("checkpoint_period")
+	this.stats_formats = strings.Split(command_line_parser.StringParameter("stats_format"), ",")
+
+	this.stat_exporter = new(StatExporter)
+	this.stat_exporter.Init(this.channels, command_line_parser)
+
+	this.interval_period = command_line_parser.IntParameter("interval_period")
+	if this.interval_period > 0 {
+		this.interval_recorder = new(IntervalRecorder)
+		this.interval_recorder.Init(
+			this.channels,
+			filepath.Join(this.bin_dirpath, "intervals.csv"),
+			command_line_parser,
+		)
+	}
+
+	this.trace = command_line_parser.BoolParameter("trace")
+	if this.trace {
+		this.trace_writer = new(TraceWriter)
+		this.trace_writer.Init(
+			this.channels,
+			filepath.Join(this.bin_dirpath, "trace.json"),
+			command_line_parser,
+		)
+	}
+
+	this.profile = command_line_parser.BoolParameter("profile")
+	if this.profile {
+		this.profile_writer = new(ProfileWriter)
+		this.profile_writer.Init(dpus, this.host.SourceLines(), command_line_parser)
+	}
+
+	this.call_graph = command_line_parser.BoolParameter("call_graph")
+	if this.call_graph {
+		this.call_graph_writer = new(CallGraphWriter)
+		this.call_graph_writer.Init(dpus, this.host.Addresses(), this.host.SourceLines())
+	}
+
+	symbolizer := new(Symbolizer)
+	symbolizer.Init(this.host.Addresses(), this.host.Values(), this.host.SourceLines())
+
+	this.detect_races = command_line_parser.BoolParameter("detect_races")
+	if this.detect_races {
+		this.race_writer = new(RaceWriter)
+		this.race_writer.Init(dpus, symbolizer)
+	}
+
+	this.sanitize = command_line_parser.BoolParameter("sanitize")
+	if this.sanitize {
+		this.sanitizer_writer = new(SanitizerWriter)
+		this.sanitizer_writer.Init(dpus, this.host.Values(), symbolizer, command_line_parser)
+	}
+
+	if command_line_parser.IntParameter("watchdog_window") > 0 {
+		this.watchdog = new(Watchdog)
+		this.watchdog.Init(dpus, symbolizer, command_line_parser)
+	}
 
 	this.host.Load()
 	this.host.Schedule(this.execution)
 	this.host.Launch()
+
+	if this.trace {
+		this.trace_writer.RecordChannels(this.cycles)
+	}
 }
 
 func (this *Simulator) Fini() {
+	if this.interval_recorder != nil {
+		this.interval_recorder.Fini()
+	}
+
+	if this.trace_writer != nil {
+		this.trace_writer.Fini(this.cycles)
+	}
+
+	this.worker_pool.Fini()
 	this.host.Fini()
 
-	for _, channel_ := range this.channels {
-		channel_.Fini()
+	// the tasklets of a hung DPU never finish, so its channel cannot be torn down
+	if !this.is_hung {
+		for _, channel_ := range this.channels {
+			channel_.Fini()
+		}
 	}
 }
 
+func (this *Simulator) Host() *host.Host {
+	return this.host
+}
+
+// Samplers are the samplers of the DPUs in the sampled mode.
+func (this *Simulator) Samplers() []*Sampler {
+	return this.samplers
+}
+
+func (this *Simulator) Execution() int {
+	return this.execution
+}
+
+func (this *Simulator) Cycles() int64 {
+	return this.cycles
+}
+
 func (this *Simulator) IsFinished() bool {
-	return this.execution == this.host.NumExecutions()
+	return this.is_hung || this.execution == this.host.NumExecutions()
+}
+
+func (this *Simulator) IsHung() bool {
+	return this.is_hung
 }
 
 func (this *Simulator) Cycle() {
 	this.host.Cycle()
 
-	thread_pool := new(core.ThreadPool)
-	thread_pool.Init(this.num_simulation_threads)
+	var cycles int64
+	if this.fast_forward {
+		cycles = this.FastForwardDpus()
+	} else {
+		this.worker_pool.Start()
 
-	dpus := this.host.Dpus()
-	for _, dpu_ := range dpus {
-		cycle_job := new(CycleJob)
-		cycle_job.Init(dpu_)
-
-		thread_pool.Enque(cycle_job)
+		cycles = this.cycles + 1
 	}
 
-	thread_pool.Start()
+	if this.trace {
+		this.trace_writer.RecordDpus()
+	}
 
 	if this.host.IsZombie() {
 		fmt.Printf("execution (%d) is finished...\n", this.execution)
 
 		this.host.Check(this.execution)
+		this.stat_exporter.RecordExecution(this.execution, cycles)
 		this.execution++
 
 		if !this.IsFinished() {
@@ -86,27 +278,300 @@ func (this *Simulator) Cycle() {
 		}
 	}
 
+	this.cycles = cycles
+
+	if this.trace {
+		this.trace_writer.RecordChannels(this.cycles)
+	}
+
+	if this.interval_period > 0 && this.cycles%this.interval_period == 0 {
+		this.interval_recorder.Record(this.cycles)
+	}
+
+	if this.watchdog != nil && this.watchdog.Check(this.cycles) {
+		fmt.Printf(
+			"DPUs (%d) make no progress, execution (%d) is stopped at cycle (%d)...\n",
+			this.watchdog.NumHungDpus(),
+			this.execution,
+			this.cycles,
+		)
+
+		this.is_hung = true
+	}
+
+	if this.checkpoint_period > 0 && this.cycles%this.checkpoint_period == 0 && !this.IsFinished() {
+		checkpoint_filename := fmt.Sprintf("checkpoint_%d.bin", this.cycles)
+		this.Checkpoint(filepath.Join(this.bin_dirpath, checkpoint_filename))
+	}
+
 	if this.verbose >= 1 {
 		fmt.Println("system is cycling...")
 	}
 }
 
+// DPUs never interact with each other, so a DPU whose tasklets are all stalled on DMA runs ahead
+// until its memory controller hands a DMA command back, while an idle DPU stays behind and catches
+// up in a single skip. The simulator then advances to the earliest DPU. Both run through the worker
+// pool, which runs the fast-forward jobs and the skip jobs that are armed.
+func (this *Simulator) FastForwardDpus() int64 {
+	max_num_cycles := int64(math.MaxInt64)
+	if this.checkpoint_period > 0 {
+		max_num_cycles = this.checkpoint_period - this.cycles%this.checkpoint_period
+	}
+	if this.interval_period > 0 && this.interval_period-this.cycles%this.interval_period < max_num_cycles {
+		max_num_cycles = this.interval_period - this.cycles%this.interval_period
+	}
+
+	dpus := this.host.Dpus()
+
+	for _, skip_job := range this.skip_jobs {
+		skip_job.Disarm()
+	}
+	for _, fast_forward_job := range this.fast_forward_jobs {
+		fast_forward_job.Arm(this.cycles, max_num_cycles)
+	}
+
+	this.worker_pool.Start()
+
+	cycles := int64(math.MaxInt64)
+	for _, dpu_ := range dpus {
+		if dpu_.Cycles() > this.cycles && dpu_.Cycles() < cycles {
+			cycles = dpu_.Cycles()
+		}
+	}
+
+	if cycles == math.MaxInt64 {
+		cycles = this.cycles + 1
+	}
+
+	for _, fast_forward_job := range this.fast_forward_jobs {
+		fast_forward_job.Disarm()
+	}
+	for _, skip_job := range this.skip_jobs {
+		skip_job.Arm(this.cycles, cycles-this.cycles)
+	}
+
+	this.worker_pool.Start()
+
+	return cycles
+}
+
+func (this *Simulator) Checkpoint(path string) {
+	for _, channel_ := range this.channels {
+		if !channel_.IsEmpty() {
+			err := errors.New("channel is not empty")
+			panic(err)
+		}
+	}
+
+	checkpoint_writer := new(misc.CheckpointWriter)
+	checkpoint_writer.Init(path)
+
+	checkpoint_writer.WriteString("uPIMulator")
+	checkpoint_writer.WriteInt(checkpoint_version)
+	checkpoint_writer.WriteString(this.benchmark)
+
+	dpus := this.host.Dpus()
+
+	checkpoint_writer.WriteInt(int64(len(this.channels)))
+	checkpoint_writer.WriteInt(int64(len(dpus)))
+	checkpoint_writer.WriteInt(int64(this.num_tasklets))
+	checkpoint_writer.WriteInt(this.wordline_size)
+
+	// the architecture that the DPUs are checkpointed with
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	for _, parameter := range config_loader.Parameters() {
+		checkpoint_writer.WriteString(parameter)
+		checkpoint_writer.WriteInt(config_loader.Values()[parameter])
+	}
+
+	checkpoint_writer.WriteInt(int64(this.execution))
+	checkpoint_writer.WriteInt(this.cycles)
+
+	for _, dpu_ := range dpus {
+		dpu_.Checkpoint(checkpoint_writer)
+	}
+
+	checkpoint_writer.Flush()
+
+	fmt.Printf("checkpoint (%s) is written at cycle (%d)...\n", path, this.cycles)
+}
+
+func (this *Simulator) Restore(path string) {
+	checkpoint_reader := new(misc.CheckpointReader)
+	checkpoint_reader.Init(path)
+
+	if checkpoint_reader.ReadString() != "uPIMulator" {
+		err := errors.New("checkpoint is not a uPIMulator checkpoint")
+		panic(err)
+	} else if version := checkpoint_reader.ReadInt(); version != checkpoint_version {
+		err_msg := fmt.Sprintf("checkpoint's version (%d) != version (%d)", version, checkpoint_version)
+		err := errors.New(err_msg)
+		panic(err)
+	} else if checkpoint_reader.ReadString() != this.benchmark {
+		err := errors.New("checkpoint's benchmark != benchmark")
+		panic(err)
+	}
+
+	dpus := this.host.Dpus()
+
+	if checkpoint_reader.ReadInt() != int64(len(this.channels)) {
+		err := errors.New("checkpoint's num_channels != num_channels")
+		panic(err)
+	} else if checkpoint_reader.ReadInt() != int64(len(dpus)) {
+		err := errors.New("checkpoint's number of DPUs != number of DPUs")
+		panic(err)
+	} else if checkpoint_reader.ReadInt() != int64(this.num_tasklets) {
+		err := errors.New("checkpoint's num_tasklets != num_tasklets")
+		panic(err)
+	} else if checkpoint_reader.ReadInt() != this.wordline_size {
+		err := errors.New("checkpoint's wordline_size != wordline_size")
+		panic(err)
+	}
+
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	for _, parameter := range config_loader.Parameters() {
+		if checkpoint_reader.ReadString() != parameter {
+			err := errors.New("checkpoint's architecture parameters != architecture parameters")
+			panic(err)
+		} else if checkpoint_reader.ReadInt() != config_loader.Values()[parameter] {
+			err_msg := fmt.Sprintf("checkpoint's %s != %s", parameter, parameter)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+	}
+
+	this.execution = int(checkpoint_reader.ReadInt())
+	this.cycles = checkpoint_reader.ReadInt()
+
+	if this.execution >= this.host.NumExecutions() {
+		err := errors.New("checkpoint's execution >= number of executions")
+		panic(err)
+	}
+
+	for _, dpu_ := range dpus {
+		dpu_.Restore(checkpoint_reader)
+	}
+
+	if !checkpoint_reader.IsEnd() {
+		err := errors.New("checkpoint is not fully restored")
+		panic(err)
+	}
+
+	this.stat_exporter.RecordBaseline()
+	if this.interval_recorder != nil {
+		this.interval_recorder.RecordBaseline()
+	}
+	if this.trace_writer != nil {
+		this.trace_writer.RecordBaseline()
+	}
+	if this.profile_writer != nil {
+		this.profile_writer.RecordBaseline()
+	}
+	if this.call_graph_writer != nil {
+		this.call_graph_writer.RecordBaseline()
+	}
+	if this.race_writer != nil {
+		this.race_writer.RecordBaseline()
+	}
+	if this.sanitizer_writer != nil {
+		this.sanitizer_writer.RecordBaseline()
+	}
+	if this.watchdog != nil {
+		this.watchdog.RecordBaseline(this.cycles)
+	}
+
+	fmt.Printf("checkpoint (%s) is restored at cycle (%d)...\n", path, this.cycles)
+}
+
 func (this *Simulator) Dump() {
 	file_dumper := new(misc.FileDumper)
 	file_dumper.Init(filepath.Join(this.bin_dirpath, "log.txt"))
 
 	lines := make([]string, 0)
 
-	dpus := this.host.Dpus()
-	for _, dpu_ := range dpus {
-		lines = append(lines, dpu_.StatFactory().ToLines()...)
-		lines = append(lines, dpu_.ThreadScheduler().StatFactory().ToLines()...)
-		lines = append(lines, dpu_.Logic().StatFactory().ToLines()...)
-		lines = append(lines, dpu_.Logic().CycleRule().StatFactory().ToLines()...)
-		lines = append(lines, dpu_.MemoryController().StatFactory().ToLines()...)
-		lines = append(lines, dpu_.MemoryController().MemoryScheduler().StatFactory().ToLines()...)
-		lines = append(lines, dpu_.MemoryController().RowBuffer().StatFactory().ToLines()...)
+	if this.mode == "sampled" {
+		confidence_lines := make([]string, 0)
+
+		for _, sampler := range this.samplers {
+			lines = append(lines, sampler.ToLines()...)
+			confidence_lines = append(confidence_lines, sampler.ToConfidenceLines()...)
+		}
+
+		confidence_file_dumper := new(misc.FileDumper)
+		confidence_file_dumper.Init(filepath.Join(this.bin_dirpath, "sampling.txt"))
+		confidence_file_dumper.WriteLines(confidence_lines)
+	} else {
+		dpus := this.host.Dpus()
+		for _, dpu_ := range dpus {
+			for _, stat_factory := range dpu_.StatFactories() {
+				lines = append(lines, stat_factory.ToLines()...)
+			}
+		}
 	}
 
 	file_dumper.WriteLines(lines)
+
+	if this.interval_period > 0 && this.cycles%this.interval_period != 0 {
+		this.interval_recorder.Record(this.cycles)
+	}
+
+	dpu_stats := this.stat_exporter.Snapshot()
+	if this.mode == "sampled" {
+		dpu_stats = make([]map[string]map[string]int64, 0)
+		for _, sampler := range this.samplers {
+			dpu_stats = append(dpu_stats, sampler.ComponentStats())
+		}
+	}
+
+	if this.profile {
+		this.profile_writer.Write(filepath.Join(this.bin_dirpath, "profile.txt"))
+	}
+
+	if this.call_graph {
+		this.call_graph_writer.Write(
+			filepath.Join(this.bin_dirpath, "call_graph.folded"),
+			filepath.Join(this.bin_dirpath, "call_graph.txt"),
+		)
+	}
+
+	if this.detect_races {
+		races_path := filepath.Join(this.bin_dirpath, "races.txt")
+		this.race_writer.Write(races_path)
+
+		fmt.Printf("data races (%d) are detected, see (%s)...\n", this.race_writer.NumRaces(), races_path)
+	}
+
+	if this.sanitize {
+		sanitizer_path := filepath.Join(this.bin_dirpath, "sanitizer.txt")
+		this.sanitizer_writer.Write(sanitizer_path)
+
+		fmt.Printf(
+			"sanitizer findings (%d) are reported, see (%s)...\n",
+			this.sanitizer_writer.NumFindings(),
+			sanitizer_path,
+		)
+	}
+
+	if this.is_hung {
+		watchdog_path := filepath.Join(this.bin_dirpath, "watchdog.txt")
+		this.watchdog.Write(watchdog_path)
+
+		for _, line := range this.watchdog.Report() {
+			fmt.Println(line)
+		}
+
+		fmt.Printf("watchdog report is written, see (%s)...\n", watchdog_path)
+	}
+
+	if slices.Contains(this.stats_formats, "json") {
+		this.stat_exporter.ExportJson(filepath.Join(this.bin_dirpath, "stats.json"), dpu_stats, this.cycles)
+	}
+	if slices.Contains(this.stats_formats, "csv") {
+		this.stat_exporter.ExportCsv(filepath.Join(this.bin_dirpath, "stats.csv"), dpu_stats, this.cycles)
+	}
 }
@@ -0,0 +1,37 @@
+package simulator
+
+import (
+	"uPIMulator/src/simulator/dpu"
+)
+
+// A SkipJob stays in the worker pool of the simulator for the whole run, and catches its DPU up
+// once it is armed, if the DPU is still at the cycle that it is armed with.
+type SkipJob struct {
+	dpu        *dpu.Dpu
+	cycles     int64
+	num_cycles int64
+	is_armed   bool
+}
+
+func (this *SkipJob) Init(dpu_ *dpu.Dpu) {
+	this.dpu = dpu_
+	this.cycles = 0
+	this.num_cycles = 0
+	this.is_armed = false
+}
+
+func (this *SkipJob) Arm(cycles int64, num_cycles int64) {
+	this.cycles = cycles
+	this.num_cycles = num_cycles
+	this.is_armed = true
+}
+
+func (this *SkipJob) Disarm() {
+	this.is_armed = false
+}
+
+func (this *SkipJob) Execute() {
+	if this.is_armed && this.dpu.Cycles() == this.cycles {
+		this.dpu.Skip(this.num_cycles)
+	}
+}
@@ -0,0 +1,258 @@
+package simulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"uPIMulator/src/misc"
+	"uPIMulator/src/simulator/channel"
+)
+
+// A StatExporter writes the stats as a system -> channel -> rank -> DPU -> component -> stat
+// hierarchy. Stats of a DPU are kept as component -> stat -> value maps, indexed in the order of
+// the DPUs in the channels, and the per-execution sections hold the raw stats accumulated during
+// each execution.
+type StatExporter struct {
+	channels []*channel.Channel
+
+	benchmark    string
+	mode         string
+	options      map[string]string
+	architecture map[string]int64
+
+	start_time time.Time
+
+	baseline   []map[string]map[string]int64
+	executions []*ExecutionStats
+}
+
+func (this *StatExporter) Init(
+	channels []*channel.Channel,
+	command_line_parser *misc.CommandLineParser,
+) {
+	this.channels = channels
+
+	this.benchmark = command_line_parser.StringParameter("benchmark")
+	this.mode = command_line_parser.StringParameter("mode")
+
+	this.options = make(map[string]string, 0)
+	for _, option := range command_line_parser.Options() {
+		this.options[option] = command_line_parser.Parameter(option)
+	}
+
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	this.architecture = config_loader.Values()
+
+	this.start_time = time.Now()
+
+	this.baseline = this.Snapshot()
+	this.executions = make([]*ExecutionStats, 0)
+}
+
+func (this *StatExporter) Snapshot() []map[string]map[string]int64 {
+	snapshot := make([]map[string]map[string]int64, 0)
+
+	for _, channel_ := range this.channels {
+		for _, dpu_ := range channel_.Dpus() {
+			snapshot = append(snapshot, dpu_.ComponentStats())
+		}
+	}
+
+	return snapshot
+}
+
+func (this *StatExporter) RecordBaseline() {
+	this.baseline = this.Snapshot()
+}
+
+func (this *StatExporter) RecordExecution(execution int, cycles int64) {
+	snapshot := this.Snapshot()
+
+	dpu_stats := make([]map[string]map[string]int64, 0)
+	for i, component_stats := range snapshot {
+		delta := make(map[string]map[string]int64, 0)
+
+		for component, stats := range component_stats {
+			delta[component] = make(map[string]int64, 0)
+
+			for stat, value := range stats {
+				delta[component][stat] = value - this.baseline[i][component][stat]
+			}
+		}
+
+		dpu_stats = append(dpu_stats, delta)
+	}
+
+	execution_stats := new(ExecutionStats)
+	execution_stats.Init(execution, cycles, dpu_stats)
+
+	this.executions = append(this.executions, execution_stats)
+
+	this.baseline = snapshot
+}
+
+func (this *StatExporter) System(dpu_stats []map[string]map[string]int64) map[string]interface{} {
+	channels := make([]interface{}, 0)
+
+	index := 0
+	for _, channel_ := range this.channels {
+		ranks := make([]interface{}, 0)
+
+		for _, rank_ := range channel_.Ranks() {
+			dpus := make([]interface{}, 0)
+
+			for _, dpu_ := range rank_.Dpus() {
+				dpu_section := map[string]interface{}{
+					"dpu_id":     dpu_.DpuId(),
+					"components": dpu_stats[index],
+				}
+				dpus = append(dpus, dpu_section)
+
+				index++
+			}
+
+			rank_section := map[string]interface{}{
+				"rank_id": rank_.RankId(),
+				"dpus":    dpus,
+			}
+			ranks = append(ranks, rank_section)
+		}
+
+		channel_section := map[string]interface{}{
+			"channel_id": channel_.ChannelId(),
+			"ranks":      ranks,
+		}
+		channels = append(channels, channel_section)
+	}
+
+	return map[string]interface{}{
+		"channels": channels,
+	}
+}
+
+// Metadata describes the run that the stats are of: the benchmark, the mode, the options, the
+// architecture, and the wall-clock time that the run has taken so far.
+func (this *StatExporter) Metadata() map[string]interface{} {
+	end_time := time.Now()
+
+	return map[string]interface{}{
+		"benchmark":          this.benchmark,
+		"mode":               this.mode,
+		"options":            this.options,
+		"architecture":       this.architecture,
+		"start_time":         this.start_time.Format(time.RFC3339),
+		"end_time":           end_time.Format(time.RFC3339),
+		"wall_clock_seconds": end_time.Sub(this.start_time).Seconds(),
+	}
+}
+
+func (this *StatExporter) ExportJson(path string, dpu_stats []map[string]map[string]int64, cycles int64) {
+	metadata := this.Metadata()
+
+	total := map[string]interface{}{
+		"cycles": cycles,
+		"system": this.System(dpu_stats),
+	}
+
+	executions := make([]interface{}, 0)
+	for _, execution_stats := range this.executions {
+		execution_section := map[string]interface{}{
+			"execution": execution_stats.Execution(),
+			"cycles":    execution_stats.Cycles(),
+			"system":    this.System(execution_stats.DpuStats()),
+		}
+		executions = append(executions, execution_section)
+	}
+
+	stats := map[string]interface{}{
+		"metadata":   metadata,
+		"total":      total,
+		"executions": executions,
+	}
+
+	this.WriteJson(path, stats)
+}
+
+// ExportCsv writes the stats as rows of a CSV file, and the metadata of the run with the cycles to
+// a JSON file next to it, e.g., stats_metadata.json next to stats.csv, so that the rows stay plain
+// CSV.
+func (this *StatExporter) ExportCsv(path string, dpu_stats []map[string]map[string]int64, cycles int64) {
+	lines := make([]string, 0)
+	lines = append(lines, "section,channel_id,rank_id,dpu_id,component,stat,value")
+
+	lines = append(lines, this.CsvLines("total", dpu_stats)...)
+
+	for _, execution_stats := range this.executions {
+		section := fmt.Sprintf("execution_%d", execution_stats.Execution())
+		lines = append(lines, this.CsvLines(section, execution_stats.DpuStats())...)
+	}
+
+	file_dumper := new(misc.FileDumper)
+	file_dumper.Init(path)
+	file_dumper.WriteLines(lines)
+
+	metadata := this.Metadata()
+	metadata["cycles"] = cycles
+
+	this.WriteJson(strings.TrimSuffix(path, filepath.Ext(path))+"_metadata.json", metadata)
+}
+
+func (this *StatExporter) WriteJson(path string, content interface{}) {
+	bytes, err := json.MarshalIndent(content, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+
+	file_dumper := new(misc.FileDumper)
+	file_dumper.Init(path)
+	file_dumper.WriteLines([]string{string(bytes)})
+}
+
+func (this *StatExporter) CsvLines(section string, dpu_stats []map[string]map[string]int64) []string {
+	lines := make([]string, 0)
+
+	index := 0
+	for _, channel_ := range this.channels {
+		for _, rank_ := range channel_.Ranks() {
+			for _, dpu_ := range rank_.Dpus() {
+				component_stats := dpu_stats[index]
+
+				components := make([]string, 0)
+				for component := range component_stats {
+					components = append(components, component)
+				}
+				slices.Sort(components)
+
+				for _, component := range components {
+					stats := make([]string, 0)
+					for stat := range component_stats[component] {
+						stats = append(stats, stat)
+					}
+					slices.Sort(stats)
+
+					for _, stat := range stats {
+						fields := []string{
+							section,
+							fmt.Sprintf("%d", channel_.ChannelId()),
+							fmt.Sprintf("%d", rank_.RankId()),
+							fmt.Sprintf("%d", dpu_.DpuId()),
+							component,
+							stat,
+							fmt.Sprintf("%d", component_stats[component][stat]),
+						}
+						lines = append(lines, strings.Join(fields, ","))
+					}
+				}
+
+				index++
+			}
+		}
+	}
+
+	return lines
+}
@@ -0,0 +1,17 @@
+package simulator
+
+import (
+	"uPIMulator/src/simulator/dpu"
+)
+
+type StepJob struct {
+	dpu *dpu.Dpu
+}
+
+func (this *StepJob) Init(dpu_ *dpu.Dpu) {
+	this.dpu = dpu_
+}
+
+func (this *StepJob) Execute() {
+	this.dpu.Step()
+}
@@ -0,0 +1,148 @@
+package simulator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"uPIMulator/src/linker/kernel"
+	"uPIMulator/src/misc"
+)
+
+// A Symbolizer names the PCs and the WRAM addresses of the kernel for the diagnostics. A PC is
+// named after its function and source line, and a WRAM address after the nearest symbol at or
+// below it, i.e., a global variable, the stack of a tasklet, the software cache, or the heap, plus
+// the offset from the symbol. The hidden labels of the sections and the local labels of the
+// assembler, which contain a dot, are skipped. A PC can also be named after the nearest label at
+// or below it, including the local labels, which tells the basic block the PC is in.
+type Symbolizer struct {
+	source_lines map[int64]*kernel.SourceLine
+
+	iram_labels    []string
+	iram_addresses map[string]int64
+
+	wram_symbols   []string
+	wram_addresses map[string]int64
+}
+
+func (this *Symbolizer) Init(
+	addresses map[string]int64,
+	values map[string]int64,
+	source_lines map[int64]*kernel.SourceLine,
+) {
+	this.source_lines = source_lines
+
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	iram_end_address := config_loader.IramOffset() + config_loader.IramSize()
+	wram_end_address := config_loader.WramOffset() + config_loader.WramSize()
+
+	this.iram_labels = make([]string, 0)
+	this.iram_addresses = make(map[string]int64, 0)
+
+	for label_name, address := range addresses {
+		if address < config_loader.IramOffset() || address >= iram_end_address {
+			continue
+		}
+
+		this.iram_labels = append(this.iram_labels, label_name)
+		this.iram_addresses[label_name] = address
+	}
+
+	this.wram_symbols = make([]string, 0)
+	this.wram_addresses = make(map[string]int64, 0)
+
+	for label_name, address := range addresses {
+		if strings.Contains(label_name, ".") || address < config_loader.WramOffset() || address >= wram_end_address {
+			continue
+		}
+
+		this.wram_symbols = append(this.wram_symbols, label_name)
+		this.wram_addresses[label_name] = address
+	}
+
+	for name, value := range values {
+		if strings.HasPrefix(name, "__sys_stack_thread_") || name == "__sw_cache_buffer" ||
+			name == "__sys_heap_pointer_reset" {
+			this.wram_symbols = append(this.wram_symbols, name)
+			this.wram_addresses[name] = value
+		}
+	}
+
+	sort_fn := func(i int, j int) bool {
+		if this.wram_addresses[this.wram_symbols[i]] != this.wram_addresses[this.wram_symbols[j]] {
+			return this.wram_addresses[this.wram_symbols[i]] < this.wram_addresses[this.wram_symbols[j]]
+		}
+		return this.wram_symbols[i] < this.wram_symbols[j]
+	}
+
+	sort.Slice(this.wram_symbols, sort_fn)
+
+	// the shorter label of the same address comes last, so that a function wins over its sections
+	iram_sort_fn := func(i int, j int) bool {
+		if this.iram_addresses[this.iram_labels[i]] != this.iram_addresses[this.iram_labels[j]] {
+			return this.iram_addresses[this.iram_labels[i]] < this.iram_addresses[this.iram_labels[j]]
+		} else if len(this.iram_labels[i]) != len(this.iram_labels[j]) {
+			return len(this.iram_labels[i]) > len(this.iram_labels[j])
+		}
+		return this.iram_labels[i] > this.iram_labels[j]
+	}
+
+	sort.Slice(this.iram_labels, iram_sort_fn)
+}
+
+func (this *Symbolizer) Pc(pc int64) string {
+	if source_line, found := this.source_lines[pc]; found {
+		if source_line.File() == "??" {
+			return fmt.Sprintf("0x%x %s", pc, source_line.Function())
+		}
+
+		return fmt.Sprintf(
+			"0x%x %s (%s:%d)",
+			pc,
+			source_line.Function(),
+			source_line.File(),
+			source_line.LineNumber(),
+		)
+	}
+
+	return fmt.Sprintf("0x%x", pc)
+}
+
+func (this *Symbolizer) Label(pc int64) string {
+	label := ""
+	for _, iram_label := range this.iram_labels {
+		if this.iram_addresses[iram_label] > pc {
+			break
+		}
+
+		label = iram_label
+	}
+
+	if label == "" {
+		return fmt.Sprintf("0x%x", pc)
+	} else if this.iram_addresses[label] == pc {
+		return fmt.Sprintf("0x%x <%s>", pc, label)
+	} else {
+		return fmt.Sprintf("0x%x <%s+%d>", pc, label, pc-this.iram_addresses[label])
+	}
+}
+
+func (this *Symbolizer) WramAddress(address int64) string {
+	symbol := ""
+	for _, wram_symbol := range this.wram_symbols {
+		if this.wram_addresses[wram_symbol] > address {
+			break
+		}
+
+		symbol = wram_symbol
+	}
+
+	if symbol == "" {
+		return fmt.Sprintf("0x%x", address)
+	} else if this.wram_addresses[symbol] == address {
+		return fmt.Sprintf("%s (0x%x)", symbol, address)
+	} else {
+		return fmt.Sprintf("%s+%d (0x%x)", symbol, address-this.wram_addresses[symbol], address)
+	}
+}
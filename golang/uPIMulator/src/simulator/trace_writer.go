@@ -0,0 +1,202 @@
+package simulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"uPIMulator/src/misc"
+	"uPIMulator/src/simulator/channel"
+	"uPIMulator/src/simulator/dpu"
+)
+
+// A TraceWriter streams the events of the DPU and channel tracers as a Chrome trace-event JSON
+// file, which can be opened in chrome://tracing or ui.perfetto.dev. The host is process 0 with a
+// thread per channel, and each DPU is a process with a thread per tasklet. Host transfers run while
+// the DPUs are stopped, so each host phase is laid out on the timeline right after the cycle that
+// triggered it and pushes the following DPU events back by the length of the phase.
+type TraceWriter struct {
+	channels []*channel.Channel
+	dpus     []*dpu.Dpu
+
+	logic_frequency int64
+
+	dpu_tracers     []*misc.Tracer
+	channel_tracers []*misc.Tracer
+
+	offset         int64
+	channel_cycles []int64
+
+	num_events    int
+	file_streamer *misc.FileStreamer
+}
+
+func (this *TraceWriter) Init(
+	channels []*channel.Channel,
+	path string,
+	command_line_parser *misc.CommandLineParser,
+) {
+	this.channels = channels
+
+	this.logic_frequency = command_line_parser.IntParameter("logic_frequency")
+
+	this.file_streamer = new(misc.FileStreamer)
+	this.file_streamer.Init(path)
+	this.file_streamer.WriteLines([]string{"{\"traceEvents\": ["})
+
+	this.num_events = 0
+
+	this.WriteMetadata(0, -1, "process_name", "Host")
+
+	this.channel_tracers = make([]*misc.Tracer, 0)
+	this.channel_cycles = make([]int64, 0)
+	for _, channel_ := range this.channels {
+		tracer := new(misc.Tracer)
+		tracer.Init(0)
+
+		channel_.ConnectTracer(tracer)
+
+		this.channel_tracers = append(this.channel_tracers, tracer)
+		this.channel_cycles = append(this.channel_cycles, channel_.Cycles())
+
+		this.WriteMetadata(0, channel_.ChannelId(), "thread_name", fmt.Sprintf("channel %d", channel_.ChannelId()))
+	}
+
+	this.dpus = make([]*dpu.Dpu, 0)
+	this.dpu_tracers = make([]*misc.Tracer, 0)
+	for _, channel_ := range this.channels {
+		for _, dpu_ := range channel_.Dpus() {
+			pid := len(this.dpus) + 1
+
+			tracer := new(misc.Tracer)
+			tracer.Init(pid)
+
+			dpu_.ConnectTracer(tracer)
+
+			this.dpus = append(this.dpus, dpu_)
+			this.dpu_tracers = append(this.dpu_tracers, tracer)
+
+			process_name := fmt.Sprintf("DPU %d-%d-%d", dpu_.ChannelId(), dpu_.RankId(), dpu_.DpuId())
+			this.WriteMetadata(pid, -1, "process_name", process_name)
+
+			for _, thread := range dpu_.Threads() {
+				thread_name := fmt.Sprintf("tasklet %d", thread.ThreadId())
+				this.WriteMetadata(pid, thread.ThreadId(), "thread_name", thread_name)
+			}
+		}
+	}
+
+	this.offset = 0
+}
+
+func (this *TraceWriter) Fini(cycles int64) {
+	for _, tracer := range this.dpu_tracers {
+		tracer.EndStates()
+	}
+
+	this.RecordDpus()
+	this.RecordChannels(cycles)
+
+	this.file_streamer.WriteLines([]string{"]}"})
+	this.file_streamer.Fini()
+}
+
+// RecordBaseline discards the buffered events, e.g., those of the executions that a restored
+// checkpoint has skipped over.
+func (this *TraceWriter) RecordBaseline() {
+	for _, tracer := range this.dpu_tracers {
+		tracer.Drain()
+	}
+
+	for i, tracer := range this.channel_tracers {
+		tracer.Drain()
+		this.channel_cycles[i] = this.channels[i].Cycles()
+	}
+}
+
+func (this *TraceWriter) RecordDpus() {
+	for _, tracer := range this.dpu_tracers {
+		for _, trace_event := range tracer.Drain() {
+			this.WriteEvent(tracer.Pid(), trace_event, trace_event.Cycles()+this.offset)
+		}
+	}
+}
+
+// RecordChannels lays out the host phase that has run since the previous record at the given
+// cycle and advances the timeline past it.
+func (this *TraceWriter) RecordChannels(cycles int64) {
+	num_phase_cycles := int64(0)
+
+	for i, tracer := range this.channel_tracers {
+		for _, trace_event := range tracer.Drain() {
+			timestamp := this.offset + cycles + trace_event.Cycles() - this.channel_cycles[i]
+			this.WriteEvent(tracer.Pid(), trace_event, timestamp)
+		}
+
+		if this.channels[i].Cycles()-this.channel_cycles[i] > num_phase_cycles {
+			num_phase_cycles = this.channels[i].Cycles() - this.channel_cycles[i]
+		}
+
+		this.channel_cycles[i] = this.channels[i].Cycles()
+	}
+
+	this.offset += num_phase_cycles
+}
+
+func (this *TraceWriter) WriteMetadata(pid int, tid int, name string, value string) {
+	event := map[string]interface{}{
+		"name": name,
+		"ph":   "M",
+		"pid":  pid,
+		"args": map[string]interface{}{"name": value},
+	}
+
+	if tid >= 0 {
+		event["tid"] = tid
+	}
+
+	this.Write(event)
+}
+
+func (this *TraceWriter) WriteEvent(pid int, trace_event *misc.TraceEvent, cycles int64) {
+	event := map[string]interface{}{
+		"name": trace_event.Name(),
+		"cat":  trace_event.Category(),
+		"ph":   trace_event.Phase(),
+		"ts":   this.Microseconds(cycles),
+		"pid":  pid,
+		"tid":  trace_event.Tid(),
+	}
+
+	if trace_event.Phase() == "X" {
+		event["dur"] = this.Microseconds(trace_event.Duration())
+	} else {
+		// async IDs are only unique within a tracer, so they are scoped to the process
+		event["id2"] = map[string]interface{}{"local": fmt.Sprintf("0x%x", trace_event.Id())}
+	}
+
+	if trace_event.Args() != nil {
+		event["args"] = trace_event.Args()
+	}
+
+	this.Write(event)
+}
+
+// the logic frequency is in MHz, so a cycle takes 1 / logic_frequency microseconds
+func (this *TraceWriter) Microseconds(cycles int64) float64 {
+	return float64(cycles) / float64(this.logic_frequency)
+}
+
+func (this *TraceWriter) Write(event map[string]interface{}) {
+	bytes, err := json.Marshal(event)
+	if err != nil {
+		panic(err)
+	}
+
+	line := string(bytes)
+	if this.num_events > 0 {
+		line = "," + line
+	}
+
+	this.file_streamer.WriteLines([]string{line})
+
+	this.num_events++
+}
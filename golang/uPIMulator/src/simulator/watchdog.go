@@ -0,0 +1,189 @@
+package simulator
+
+import (
+	"fmt"
+	"strings"
+	"uPIMulator/src/misc"
+	"uPIMulator/src/simulator/dpu"
+	"uPIMulator/src/simulator/dpu/logic"
+)
+
+// A Watchdog stops the simulation when a DPU makes no forward progress for a window of cycles,
+// i.e., none of its tasklets finishes, none of its DMA commands retires, and none of its locks is
+// acquired or released. This is the case when the tasklets deadlock on each other, e.g., all of
+// them sleep or wait for a lock that is never released, or livelock, e.g., spin on an acquire. A
+// long computation that touches neither the MRAM nor the locks also trips the watchdog, so the
+// window must be larger than the longest such stretch of the kernel.
+type Watchdog struct {
+	dpus              []*dpu.Dpu
+	progress_trackers []*misc.ProgressTracker
+
+	window int64
+
+	num_events      []int64
+	num_zombies     []int
+	progress_cycles []int64
+
+	hung_dpus []int
+	cycles    int64
+
+	symbolizer *Symbolizer
+}
+
+func (this *Watchdog) Init(
+	dpus []*dpu.Dpu,
+	symbolizer *Symbolizer,
+	command_line_parser *misc.CommandLineParser,
+) {
+	num_tasklets := int(command_line_parser.IntParameter("num_tasklets"))
+	num_history := int(command_line_parser.IntParameter("watchdog_history"))
+
+	this.dpus = dpus
+
+	this.progress_trackers = make([]*misc.ProgressTracker, 0)
+	for _, dpu_ := range dpus {
+		progress_tracker := new(misc.ProgressTracker)
+		progress_tracker.Init(num_tasklets, num_history)
+
+		dpu_.ConnectProgressTracker(progress_tracker)
+
+		this.progress_trackers = append(this.progress_trackers, progress_tracker)
+	}
+
+	this.window = command_line_parser.IntParameter("watchdog_window")
+
+	this.num_events = make([]int64, len(dpus))
+	this.num_zombies = make([]int, len(dpus))
+	this.progress_cycles = make([]int64, len(dpus))
+
+	this.hung_dpus = make([]int, 0)
+	this.cycles = 0
+
+	this.symbolizer = symbolizer
+}
+
+func (this *Watchdog) RecordBaseline(cycles int64) {
+	for i, progress_tracker := range this.progress_trackers {
+		progress_tracker.Reset()
+
+		this.num_events[i] = progress_tracker.NumEvents()
+		this.num_zombies[i] = this.NumZombies(this.dpus[i])
+		this.progress_cycles[i] = cycles
+	}
+}
+
+func (this *Watchdog) IsHung() bool {
+	return len(this.hung_dpus) > 0
+}
+
+func (this *Watchdog) NumHungDpus() int {
+	return len(this.hung_dpus)
+}
+
+// Check looks for the DPUs that have made no progress within the window as of the cycle, and
+// returns whether there is any. A DPU that has finished its kernel is waiting for the host, which
+// counts as progress.
+func (this *Watchdog) Check(cycles int64) bool {
+	for i, dpu_ := range this.dpus {
+		num_events := this.progress_trackers[i].NumEvents()
+		num_zombies := this.NumZombies(dpu_)
+
+		if dpu_.IsZombie() || num_events != this.num_events[i] || num_zombies != this.num_zombies[i] {
+			this.num_events[i] = num_events
+			this.num_zombies[i] = num_zombies
+			this.progress_cycles[i] = cycles
+		} else if cycles-this.progress_cycles[i] >= this.window {
+			this.hung_dpus = append(this.hung_dpus, i)
+		}
+	}
+
+	this.cycles = cycles
+
+	return this.IsHung()
+}
+
+func (this *Watchdog) NumZombies(dpu_ *dpu.Dpu) int {
+	num_zombies := 0
+	for _, thread := range dpu_.Threads() {
+		if thread.ThreadState() == logic.ZOMBIE {
+			num_zombies++
+		}
+	}
+	return num_zombies
+}
+
+// Report lists the tasklets of the DPUs that have made no progress, with their states, their PCs,
+// the locks that they hold, and the last PCs that they have issued.
+func (this *Watchdog) Report() []string {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	iram_data_size := int64(config_loader.IramDataWidth() / 8)
+
+	lines := make([]string, 0)
+
+	for _, i := range this.hung_dpus {
+		dpu_ := this.dpus[i]
+
+		lines = append(
+			lines,
+			fmt.Sprintf(
+				"DPU%d-%d-%d: no progress from cycle %d to cycle %d",
+				dpu_.ChannelId(),
+				dpu_.RankId(),
+				dpu_.DpuId(),
+				this.progress_cycles[i],
+				this.cycles,
+			),
+		)
+
+		locks := make(map[int][]string, 0)
+		atomic := dpu_.Atomic()
+		for address := atomic.Address(); address < atomic.Address()+atomic.Size(); address++ {
+			if holder := atomic.Holder(address); holder != -1 {
+				locks[holder] = append(locks[holder], fmt.Sprintf("%d", address))
+			}
+		}
+
+		for _, thread := range dpu_.Threads() {
+			pc := thread.RegFile().ReadPcReg()
+			if thread.ThreadState() == logic.BLOCK {
+				// a blocked tasklet has already moved its PC past the DMA instruction
+				pc -= iram_data_size
+			}
+
+			lines = append(
+				lines,
+				fmt.Sprintf(
+					"  tasklet %d: %s at %s, %s",
+					thread.ThreadId(),
+					thread.StringifyThreadState(),
+					this.symbolizer.Label(pc),
+					this.symbolizer.Pc(pc),
+				),
+			)
+
+			if thread_locks, found := locks[thread.ThreadId()]; found {
+				lines = append(lines, fmt.Sprintf("    holds locks: %s", strings.Join(thread_locks, ", ")))
+			} else {
+				lines = append(lines, "    holds locks: none")
+			}
+
+			history := this.progress_trackers[i].History(thread.ThreadId())
+			if len(history) > 0 {
+				lines = append(lines, fmt.Sprintf("    last instructions (%d):", len(history)))
+				for _, history_pc := range history {
+					lines = append(lines, fmt.Sprintf("      %s", this.symbolizer.Label(history_pc)))
+				}
+			}
+		}
+	}
+
+	return lines
+}
+
+func (this *Watchdog) Write(path string) {
+	file_dumper := new(misc.FileDumper)
+	file_dumper.Init(path)
+	file_dumper.WriteLines(this.Report())
+}
@@ -0,0 +1,51 @@
+package sweeper
+
+import (
+	"fmt"
+)
+
+// A SweepCase is a point of a sweep, i.e., a value for each of the parameters that the sweep
+// varies, and the build whose binary it simulates.
+type SweepCase struct {
+	case_id  int
+	build_id int
+
+	names      []string
+	parameters map[string]string
+}
+
+func (this *SweepCase) Init(case_id int, names []string, values []string) {
+	this.case_id = case_id
+	this.build_id = -1
+
+	this.names = names
+
+	this.parameters = make(map[string]string, 0)
+	for i, name := range names {
+		this.parameters[name] = values[i]
+	}
+}
+
+func (this *SweepCase) CaseId() int {
+	return this.case_id
+}
+
+func (this *SweepCase) Name() string {
+	return fmt.Sprintf("case_%d", this.case_id)
+}
+
+func (this *SweepCase) BuildId() int {
+	return this.build_id
+}
+
+func (this *SweepCase) SetBuildId(build_id int) {
+	this.build_id = build_id
+}
+
+func (this *SweepCase) Names() []string {
+	return this.names
+}
+
+func (this *SweepCase) Parameter(name string) string {
+	return this.parameters[name]
+}
@@ -0,0 +1,250 @@
+package sweeper
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"slices"
+	"strconv"
+)
+
+// A SweepFile is a JSON file that describes the cases of a sweep, e.g.,
+//
+//	{
+//	  "sampling": "latin_hypercube",
+//	  "num_samples": 32,
+//	  "seed": 7,
+//	  "parameters": {
+//	    "num_tasklets": [1, 2, 4, 8, 16],
+//	    "t_rcd": {"min": 16, "max": 48, "step": 4},
+//	    "wram_size": [65536, 131072]
+//	  }
+//	}
+//
+// The parameters are the command line options and the architecture parameters of the config file,
+// each given as a list of values or as a range of integers, whose step is 1 unless it is given. The
+// grid sampling, which is the default, takes every combination of the values, and the random and
+// the Latin-hypercube samplings take num_samples combinations that the seed determines, so that a
+// sweep expands to the same cases every time it is resumed.
+type SweepFile struct {
+	sampling    string
+	num_samples int
+	seed        int64
+
+	parameters []*SweepParameter
+}
+
+func (this *SweepFile) Init(path string) {
+	content, read_err := os.ReadFile(path)
+	if read_err != nil {
+		err_msg := fmt.Sprintf("sweep file (%s) cannot be read: %s", path, read_err.Error())
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	var sections struct {
+		Sampling   string                     `json:"sampling"`
+		NumSamples int                        `json:"num_samples"`
+		Seed       int64                      `json:"seed"`
+		Parameters map[string]json.RawMessage `json:"parameters"`
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+
+	if decode_err := decoder.Decode(&sections); decode_err != nil {
+		err_msg := fmt.Sprintf("sweep file (%s) is not valid: %s", path, decode_err.Error())
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	this.sampling = sections.Sampling
+	if this.sampling == "" {
+		this.sampling = "grid"
+	}
+
+	if this.sampling != "grid" && this.sampling != "random" && this.sampling != "latin_hypercube" {
+		err_msg := fmt.Sprintf("sampling (%s) is not grid, random, or latin_hypercube", this.sampling)
+		err := errors.New(err_msg)
+		panic(err)
+	} else if this.sampling != "grid" && sections.NumSamples <= 0 {
+		err := errors.New("num samples <= 0")
+		panic(err)
+	}
+
+	this.num_samples = sections.NumSamples
+	this.seed = sections.Seed
+
+	if len(sections.Parameters) == 0 {
+		err_msg := fmt.Sprintf("sweep file (%s) has no parameters", path)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	names := make([]string, 0)
+	for name := range sections.Parameters {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	this.parameters = make([]*SweepParameter, 0)
+	for _, name := range names {
+		this.parameters = append(this.parameters, this.DecodeParameter(name, sections.Parameters[name]))
+	}
+}
+
+func (this *SweepFile) DecodeParameter(name string, message json.RawMessage) *SweepParameter {
+	sweep_parameter := new(SweepParameter)
+
+	var values []interface{}
+	var sweep_range struct {
+		Min  *int64 `json:"min"`
+		Max  *int64 `json:"max"`
+		Step *int64 `json:"step"`
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(message))
+	decoder.UseNumber()
+
+	if decode_err := decoder.Decode(&values); decode_err == nil {
+		string_values := make([]string, 0)
+		for _, value := range values {
+			if bool_value, ok := value.(bool); ok {
+				string_values = append(string_values, strconv.FormatBool(bool_value))
+			} else if number_value, ok := value.(json.Number); ok {
+				string_values = append(string_values, number_value.String())
+			} else if string_value, ok := value.(string); ok {
+				string_values = append(string_values, string_value)
+			} else {
+				err_msg := fmt.Sprintf("value of sweep parameter (%s) is not a string, number, or bool", name)
+				err := errors.New(err_msg)
+				panic(err)
+			}
+		}
+
+		sweep_parameter.Init(name, string_values)
+		return sweep_parameter
+	}
+
+	decoder = json.NewDecoder(bytes.NewReader(message))
+	decoder.DisallowUnknownFields()
+
+	if decode_err := decoder.Decode(&sweep_range); decode_err != nil ||
+		sweep_range.Min == nil ||
+		sweep_range.Max == nil {
+		err_msg := fmt.Sprintf(
+			"sweep parameter (%s) is not a list of values or a range with min and max",
+			name,
+		)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	step := int64(1)
+	if sweep_range.Step != nil {
+		step = *sweep_range.Step
+	}
+
+	sweep_parameter.InitRange(name, *sweep_range.Min, *sweep_range.Max, step)
+	return sweep_parameter
+}
+
+func (this *SweepFile) Sampling() string {
+	return this.sampling
+}
+
+func (this *SweepFile) Names() []string {
+	names := make([]string, 0)
+	for _, sweep_parameter := range this.parameters {
+		names = append(names, sweep_parameter.Name())
+	}
+	return names
+}
+
+// Cases expands the parameters into the cases of the sweep by the sampling of the sweep file.
+func (this *SweepFile) Cases() []*SweepCase {
+	var indices [][]int64
+	if this.sampling == "grid" {
+		indices = this.GridIndices()
+	} else if this.sampling == "random" {
+		indices = this.RandomIndices()
+	} else {
+		indices = this.LatinHypercubeIndices()
+	}
+
+	sweep_cases := make([]*SweepCase, 0)
+	for case_id, case_indices := range indices {
+		values := make([]string, 0)
+		for i, sweep_parameter := range this.parameters {
+			values = append(values, sweep_parameter.Value(case_indices[i]))
+		}
+
+		sweep_case := new(SweepCase)
+		sweep_case.Init(case_id, this.Names(), values)
+		sweep_cases = append(sweep_cases, sweep_case)
+	}
+	return sweep_cases
+}
+
+// GridIndices enumerates every combination of the values, varying the last parameter the fastest.
+func (this *SweepFile) GridIndices() [][]int64 {
+	indices := [][]int64{make([]int64, 0)}
+
+	for _, sweep_parameter := range this.parameters {
+		next_indices := make([][]int64, 0)
+		for _, case_indices := range indices {
+			for index := int64(0); index < sweep_parameter.Size(); index++ {
+				next_indices = append(next_indices, append(slices.Clone(case_indices), index))
+			}
+		}
+		indices = next_indices
+	}
+
+	return indices
+}
+
+func (this *SweepFile) RandomIndices() [][]int64 {
+	random := rand.New(rand.NewSource(this.seed))
+
+	indices := make([][]int64, 0)
+	for i := 0; i < this.num_samples; i++ {
+		case_indices := make([]int64, 0)
+		for _, sweep_parameter := range this.parameters {
+			case_indices = append(case_indices, random.Int63n(sweep_parameter.Size()))
+		}
+		indices = append(indices, case_indices)
+	}
+
+	return indices
+}
+
+// LatinHypercubeIndices splits the values of each parameter into num_samples strata of equal width
+// and draws a value from each stratum once, pairing the strata of the parameters at random, so that
+// the samples cover the range of every parameter evenly.
+func (this *SweepFile) LatinHypercubeIndices() [][]int64 {
+	random := rand.New(rand.NewSource(this.seed))
+
+	indices := make([][]int64, this.num_samples)
+	for i := range indices {
+		indices[i] = make([]int64, 0)
+	}
+
+	for _, sweep_parameter := range this.parameters {
+		strata := random.Perm(this.num_samples)
+
+		for i, stratum := range strata {
+			position := (float64(stratum) + random.Float64()) / float64(this.num_samples)
+			index := int64(position * float64(sweep_parameter.Size()))
+			if index >= sweep_parameter.Size() {
+				index = sweep_parameter.Size() - 1
+			}
+
+			indices[i] = append(indices[i], index)
+		}
+	}
+
+	return indices
+}
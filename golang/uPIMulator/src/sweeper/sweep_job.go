@@ -0,0 +1,15 @@
+package sweeper
+
+type SweepJob struct {
+	sweeper    *Sweeper
+	sweep_case *SweepCase
+}
+
+func (this *SweepJob) Init(sweeper *Sweeper, sweep_case *SweepCase) {
+	this.sweeper = sweeper
+	this.sweep_case = sweep_case
+}
+
+func (this *SweepJob) Execute() {
+	this.sweeper.Simulate(this.sweep_case)
+}
@@ -0,0 +1,77 @@
+package sweeper
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// A SweepParameter is a command line option or an architecture parameter that a sweep varies,
+// either over a list of values or over the integers from min to max in steps of step.
+type SweepParameter struct {
+	name string
+
+	values []string
+
+	is_range bool
+	min      int64
+	max      int64
+	step     int64
+}
+
+func (this *SweepParameter) Init(name string, values []string) {
+	if len(values) == 0 {
+		err_msg := fmt.Sprintf("sweep parameter (%s) has no values", name)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	this.name = name
+	this.values = values
+	this.is_range = false
+}
+
+func (this *SweepParameter) InitRange(name string, min int64, max int64, step int64) {
+	if step <= 0 {
+		err_msg := fmt.Sprintf("sweep parameter (%s) has step <= 0", name)
+		err := errors.New(err_msg)
+		panic(err)
+	} else if min > max {
+		err_msg := fmt.Sprintf("sweep parameter (%s) has min > max", name)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	this.name = name
+	this.values = nil
+	this.is_range = true
+	this.min = min
+	this.max = max
+	this.step = step
+}
+
+func (this *SweepParameter) Name() string {
+	return this.name
+}
+
+func (this *SweepParameter) Size() int64 {
+	if this.is_range {
+		return (this.max-this.min)/this.step + 1
+	} else {
+		return int64(len(this.values))
+	}
+}
+
+func (this *SweepParameter) Value(index int64) string {
+	if index < 0 || index >= this.Size() {
+		err_msg := fmt.Sprintf("index (%d) of sweep parameter (%s) is out of range", index, this.name)
+		err := errors.New(err_msg)
+		panic(err)
+	}
+
+	if this.is_range {
+		return strconv.FormatInt(this.min+index*this.step, 10)
+	} else {
+		return this.values[index]
+	}
+}
@@ -0,0 +1,567 @@
+package sweeper
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"uPIMulator/src/core"
+	"uPIMulator/src/misc"
+)
+
+// A Sweeper runs the cases of a sweep file, each as a run of its own in a bin directory of its own
+// under bin_dirpath, with the options given on the command line and the parameters of the case.
+// The cases that take the same options of the compiler, the linker, and the assembler, and the same
+// architecture, share a build: the build is compiled, linked, and assembled once, and its binary is
+// copied into the bin directories of the cases, which are then simulated in parallel. The builds
+// run one after another since the compiler builds the benchmark in root_dirpath.
+//
+// Each finished build and case leaves status.json in its bin directory, so that a sweep that is
+// interrupted resumes from the builds and the cases that have not finished, and the stats of the
+// cases are collected into results.csv at the end.
+type Sweeper struct {
+	command_line_parser *misc.CommandLineParser
+
+	bin_dirpath    string
+	executable     string
+	num_sweep_jobs int
+
+	sweep_file  *SweepFile
+	sweep_cases []*SweepCase
+	builds      []*SweepCase
+
+	num_finished_cases int
+	mutex              sync.Mutex
+}
+
+func (this *Sweeper) Init(command_line_parser *misc.CommandLineParser) {
+	this.command_line_parser = command_line_parser
+
+	this.bin_dirpath = command_line_parser.StringParameter("bin_dirpath")
+	this.num_sweep_jobs = int(command_line_parser.IntParameter("num_sweep_jobs"))
+
+	executable, executable_err := os.Executable()
+	if executable_err != nil {
+		panic(executable_err)
+	}
+	this.executable = executable
+
+	sweep_filepath := command_line_parser.StringParameter("sweep_filepath")
+	if sweep_filepath == "" {
+		err := errors.New("sweep_filepath is not given")
+		panic(err)
+	}
+
+	this.sweep_file = new(SweepFile)
+	this.sweep_file.Init(sweep_filepath)
+
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	for _, name := range this.sweep_file.Names() {
+		if slices.Contains(this.ReservedOptions(), name) {
+			err_msg := fmt.Sprintf("sweep parameter (%s) is set by the sweeper", name)
+			err := errors.New(err_msg)
+			panic(err)
+		} else if !config_loader.HasParameter(name) &&
+			!slices.Contains(command_line_parser.Options(), name) {
+			err_msg := fmt.Sprintf("sweep parameter (%s) is not an option or an architecture parameter", name)
+			err := errors.New(err_msg)
+			panic(err)
+		}
+	}
+
+	this.sweep_cases = this.sweep_file.Cases()
+	this.builds = make([]*SweepCase, 0)
+
+	this.AssignBuilds()
+
+	this.num_finished_cases = 0
+}
+
+// ReservedOptions are the options that the sweeper sets for each run.
+func (this *Sweeper) ReservedOptions() []string {
+	return []string{
+		"bin_dirpath",
+		"build_only",
+		"num_sweep_jobs",
+		"skip_build",
+		"stats_format",
+		"sweep_filepath",
+	}
+}
+
+// BuildOptions are the options that the compiler, the linker, and the assembler read, besides the
+// architecture, so that the cases that differ in them need builds of their own.
+func (this *Sweeper) BuildOptions() []string {
+	return []string{
+		"benchmark",
+		"config_filepath",
+		"config_overrides",
+		"data_prep_params",
+		"min_access_granularity",
+		"num_channels",
+		"num_dpus_per_rank",
+		"num_ranks_per_channel",
+		"num_tasklets",
+		"root_dirpath",
+	}
+}
+
+func (this *Sweeper) BuildNames() []string {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	build_names := make([]string, 0)
+	for _, name := range this.sweep_file.Names() {
+		if config_loader.HasParameter(name) || slices.Contains(this.BuildOptions(), name) {
+			build_names = append(build_names, name)
+		}
+	}
+	return build_names
+}
+
+func (this *Sweeper) AssignBuilds() {
+	build_ids := make(map[string]int, 0)
+
+	for _, sweep_case := range this.sweep_cases {
+		key := make([]string, 0)
+		for _, name := range this.BuildNames() {
+			key = append(key, name+"="+sweep_case.Parameter(name))
+		}
+
+		build_id, found := build_ids[strings.Join(key, "\n")]
+		if !found {
+			build_id = len(this.builds)
+			build_ids[strings.Join(key, "\n")] = build_id
+
+			this.builds = append(this.builds, sweep_case)
+		}
+
+		sweep_case.SetBuildId(build_id)
+	}
+}
+
+func (this *Sweeper) BuildDirpath(build_id int) string {
+	return filepath.Join(this.bin_dirpath, "builds", fmt.Sprintf("build_%d", build_id))
+}
+
+func (this *Sweeper) CaseDirpath(sweep_case *SweepCase) string {
+	return filepath.Join(this.bin_dirpath, "cases", sweep_case.Name())
+}
+
+func (this *Sweeper) Run() {
+	this.CheckCases()
+
+	pending_cases := make([]*SweepCase, 0)
+	for _, sweep_case := range this.sweep_cases {
+		if _, _, found := this.ReadStatus(this.CaseDirpath(sweep_case)); !found {
+			pending_cases = append(pending_cases, sweep_case)
+		}
+	}
+
+	this.num_finished_cases = len(this.sweep_cases) - len(pending_cases)
+
+	fmt.Printf(
+		"sweeping (%d) cases in (%d) builds, (%d) cases are pending...\n",
+		len(this.sweep_cases),
+		len(this.builds),
+		len(pending_cases),
+	)
+
+	worker_pool := new(core.WorkerPool)
+	worker_pool.Init(this.num_sweep_jobs)
+
+	num_jobs := 0
+	for build_id, build := range this.builds {
+		build_cases := make([]*SweepCase, 0)
+		for _, sweep_case := range pending_cases {
+			if sweep_case.BuildId() == build_id {
+				build_cases = append(build_cases, sweep_case)
+			}
+		}
+
+		if len(build_cases) == 0 {
+			continue
+		}
+
+		status, exit_code := this.Build(build)
+
+		for _, sweep_case := range build_cases {
+			if exit_code == 0 {
+				sweep_job := new(SweepJob)
+				sweep_job.Init(this, sweep_case)
+
+				worker_pool.Enque(sweep_job)
+				num_jobs++
+			} else if exit_code != -1 {
+				case_dirpath := this.CaseDirpath(sweep_case)
+				this.MakeDir(case_dirpath)
+				this.WriteStatus(case_dirpath, status, exit_code)
+			}
+		}
+	}
+
+	if num_jobs > 0 {
+		worker_pool.Start()
+		worker_pool.Fini()
+	}
+
+	results_path := filepath.Join(this.bin_dirpath, "results.csv")
+	this.WriteResults(results_path)
+
+	fmt.Printf("sweep is finished, results are written to (%s)...\n", results_path)
+}
+
+// CheckCases writes the cases to cases.csv, or checks that they are the cases of the sweep to
+// resume, since a sweep file that has changed expands to other cases under the same names.
+func (this *Sweeper) CheckCases() {
+	records := make([][]string, 0)
+	records = append(records, append([]string{"case", "build"}, this.sweep_file.Names()...))
+
+	for _, sweep_case := range this.sweep_cases {
+		record := []string{sweep_case.Name(), fmt.Sprintf("build_%d", sweep_case.BuildId())}
+		for _, name := range sweep_case.Names() {
+			record = append(record, sweep_case.Parameter(name))
+		}
+		records = append(records, record)
+	}
+
+	cases_path := filepath.Join(this.bin_dirpath, "cases.csv")
+	content := this.StringifyCsv(records)
+
+	if previous_content, read_err := os.ReadFile(cases_path); read_err == nil {
+		if string(previous_content) != content {
+			err_msg := fmt.Sprintf(
+				"cases of the sweep in bin_dirpath (%s) are different from those of the sweep file",
+				this.bin_dirpath,
+			)
+			err := new(misc.SimulationError)
+			err.Init(misc.CONFIG_ERROR, err_msg)
+			panic(err)
+		}
+	} else {
+		this.MakeDir(this.bin_dirpath)
+
+		if write_err := os.WriteFile(cases_path, []byte(content), 0644); write_err != nil {
+			panic(write_err)
+		}
+	}
+}
+
+// Build compiles, links, and assembles the binary of the build unless it has been built, and
+// returns the status and the exit code of the build.
+func (this *Sweeper) Build(build *SweepCase) (string, int) {
+	build_dirpath := this.BuildDirpath(build.BuildId())
+
+	if status, exit_code, found := this.ReadStatus(build_dirpath); found && exit_code == 0 {
+		return status, exit_code
+	}
+
+	this.ClearDir(build_dirpath)
+
+	fmt.Printf("building (build_%d)...\n", build.BuildId())
+
+	args := this.Args(build, this.BuildNames(), build_dirpath)
+	args = append(args, "--build_only", "true")
+
+	status, exit_code := this.Execute(args, build_dirpath)
+	if exit_code != -1 {
+		this.WriteStatus(build_dirpath, status, exit_code)
+	}
+
+	fmt.Printf("build (build_%d) is finished: %s...\n", build.BuildId(), status)
+
+	return status, exit_code
+}
+
+// Simulate copies the binary of the build into the bin directory of the case and simulates it.
+func (this *Sweeper) Simulate(sweep_case *SweepCase) {
+	case_dirpath := this.CaseDirpath(sweep_case)
+
+	this.ClearDir(case_dirpath)
+	this.CopyBuild(this.BuildDirpath(sweep_case.BuildId()), case_dirpath)
+
+	args := this.Args(sweep_case, sweep_case.Names(), case_dirpath)
+	args = append(args, "--skip_build", "true", "--stats_format", "csv")
+
+	status, exit_code := this.Execute(args, case_dirpath)
+
+	// a run that is killed, e.g., by an interrupt, is left pending to be resumed
+	if exit_code != -1 {
+		this.WriteStatus(case_dirpath, status, exit_code)
+	}
+
+	this.mutex.Lock()
+	this.num_finished_cases++
+	fmt.Printf(
+		"case (%s) is finished: %s (%d/%d)...\n",
+		sweep_case.Name(),
+		status,
+		this.num_finished_cases,
+		len(this.sweep_cases),
+	)
+	this.mutex.Unlock()
+}
+
+// Args returns the command line of a run: the options given to the sweeper, with the parameters of
+// the case that are given by names. The architecture parameters of the case are appended to
+// config_overrides, which the architecture parameters of the options are overridden by.
+func (this *Sweeper) Args(sweep_case *SweepCase, names []string, bin_dirpath string) []string {
+	config_loader := new(misc.ConfigLoader)
+	config_loader.Init()
+
+	parameters := this.command_line_parser.CustomParameters()
+	for _, reserved_option := range this.ReservedOptions() {
+		delete(parameters, reserved_option)
+	}
+
+	config_overrides := make([]string, 0)
+	if config_override, found := parameters["config_overrides"]; found {
+		config_overrides = append(config_overrides, config_override)
+	}
+
+	for _, name := range names {
+		if name == "config_overrides" {
+			config_overrides = append(config_overrides, sweep_case.Parameter(name))
+		} else if config_loader.HasParameter(name) {
+			config_overrides = append(config_overrides, name+"="+sweep_case.Parameter(name))
+		} else {
+			parameters[name] = sweep_case.Parameter(name)
+		}
+	}
+
+	delete(parameters, "config_overrides")
+	if len(config_overrides) > 0 {
+		parameters["config_overrides"] = strings.Join(config_overrides, ",")
+	}
+
+	parameters["bin_dirpath"] = bin_dirpath
+
+	options := make([]string, 0)
+	for option := range parameters {
+		options = append(options, option)
+	}
+	slices.Sort(options)
+
+	args := make([]string, 0)
+	for _, option := range options {
+		args = append(args, "--"+option, parameters[option])
+	}
+	return args
+}
+
+// Execute runs the command line in a process of its own, writing what it prints to console.txt in
+// the bin directory, and returns the status and the exit code of the run, which is -1 if the
+// process is killed. The host takes the files whose names start with input or output for its
+// chunks, hence the name of the file.
+func (this *Sweeper) Execute(args []string, dirpath string) (string, int) {
+	output, create_err := os.Create(filepath.Join(dirpath, "console.txt"))
+	if create_err != nil {
+		panic(create_err)
+	}
+	defer output.Close()
+
+	command := exec.Command(this.executable, args...)
+	command.Stdout = output
+	command.Stderr = output
+
+	exit_code := 0
+	if run_err := command.Run(); run_err != nil {
+		exit_err, ok := run_err.(*exec.ExitError)
+		if !ok {
+			panic(run_err)
+		}
+
+		exit_code = exit_err.ExitCode()
+	}
+
+	if exit_code == 0 {
+		return "ok", exit_code
+	} else if exit_code == -1 {
+		return "killed", exit_code
+	}
+
+	failure_content, read_err := os.ReadFile(filepath.Join(dirpath, "failure.json"))
+	if read_err != nil {
+		return "failed", exit_code
+	}
+
+	var failure struct {
+		Kind string `json:"kind"`
+	}
+	if unmarshal_err := json.Unmarshal(failure_content, &failure); unmarshal_err != nil {
+		return "failed", exit_code
+	}
+	return failure.Kind, exit_code
+}
+
+func (this *Sweeper) ReadStatus(dirpath string) (string, int, bool) {
+	content, read_err := os.ReadFile(filepath.Join(dirpath, "status.json"))
+	if read_err != nil {
+		return "", 0, false
+	}
+
+	var status struct {
+		Status   string `json:"status"`
+		ExitCode int    `json:"exit_code"`
+	}
+	if unmarshal_err := json.Unmarshal(content, &status); unmarshal_err != nil {
+		return "", 0, false
+	}
+
+	return status.Status, status.ExitCode, true
+}
+
+func (this *Sweeper) WriteStatus(dirpath string, status string, exit_code int) {
+	bytes, err := json.MarshalIndent(map[string]interface{}{
+		"status":    status,
+		"exit_code": exit_code,
+	}, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+
+	file_dumper := new(misc.FileDumper)
+	file_dumper.Init(filepath.Join(dirpath, "status.json"))
+	file_dumper.WriteLines([]string{string(bytes)})
+}
+
+// CopyBuild copies the binary of the build, leaving out what the build run has written about
+// itself.
+func (this *Sweeper) CopyBuild(build_dirpath string, case_dirpath string) {
+	run_files := []string{
+		"args.txt",
+		"config.json",
+		"console.txt",
+		"failure.json",
+		"log.txt",
+		"options.txt",
+		"status.json",
+	}
+
+	entries, read_err := os.ReadDir(build_dirpath)
+	if read_err != nil {
+		panic(read_err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || slices.Contains(run_files, entry.Name()) {
+			continue
+		}
+
+		content, file_read_err := os.ReadFile(filepath.Join(build_dirpath, entry.Name()))
+		if file_read_err != nil {
+			panic(file_read_err)
+		}
+
+		write_err := os.WriteFile(filepath.Join(case_dirpath, entry.Name()), content, 0644)
+		if write_err != nil {
+			panic(write_err)
+		}
+	}
+}
+
+func (this *Sweeper) MakeDir(dirpath string) {
+	if err := os.MkdirAll(dirpath, 0755); err != nil {
+		panic(err)
+	}
+}
+
+func (this *Sweeper) ClearDir(dirpath string) {
+	if err := os.RemoveAll(dirpath); err != nil {
+		panic(err)
+	}
+
+	this.MakeDir(dirpath)
+}
+
+// WriteResults collects the total stats of the cases into a table with a row per stat of each DPU,
+// and a row without stats for each case that has failed.
+func (this *Sweeper) WriteResults(path string) {
+	records := make([][]string, 0)
+
+	header := []string{"case"}
+	header = append(header, this.sweep_file.Names()...)
+	header = append(
+		header,
+		"status",
+		"exit_code",
+		"channel_id",
+		"rank_id",
+		"dpu_id",
+		"component",
+		"stat",
+		"value",
+	)
+	records = append(records, header)
+
+	for _, sweep_case := range this.sweep_cases {
+		case_dirpath := this.CaseDirpath(sweep_case)
+
+		status, exit_code, found := this.ReadStatus(case_dirpath)
+		if !found {
+			status = "pending"
+			exit_code = -1
+		}
+
+		case_record := []string{sweep_case.Name()}
+		for _, name := range sweep_case.Names() {
+			case_record = append(case_record, sweep_case.Parameter(name))
+		}
+		case_record = append(case_record, status, strconv.Itoa(exit_code))
+
+		if !found || exit_code != 0 {
+			records = append(records, append(slices.Clone(case_record), "", "", "", "", "", ""))
+			continue
+		}
+
+		for _, stat_record := range this.ReadStats(filepath.Join(case_dirpath, "stats.csv")) {
+			records = append(records, append(slices.Clone(case_record), stat_record...))
+		}
+	}
+
+	if write_err := os.WriteFile(path, []byte(this.StringifyCsv(records)), 0644); write_err != nil {
+		panic(write_err)
+	}
+}
+
+// ReadStats reads the total stats of a case, without the section, from its stats.csv.
+func (this *Sweeper) ReadStats(path string) [][]string {
+	content, read_err := os.ReadFile(path)
+	if read_err != nil {
+		panic(read_err)
+	}
+
+	records, parse_err := csv.NewReader(bytes.NewReader(content)).ReadAll()
+	if parse_err != nil {
+		panic(parse_err)
+	}
+
+	stat_records := make([][]string, 0)
+	for _, record := range records[1:] {
+		if record[0] == "total" {
+			stat_records = append(stat_records, record[1:])
+		}
+	}
+	return stat_records
+}
+
+func (this *Sweeper) StringifyCsv(records [][]string) string {
+	buffer := new(bytes.Buffer)
+
+	writer := csv.NewWriter(buffer)
+	if err := writer.WriteAll(records); err != nil {
+		panic(err)
+	}
+
+	return buffer.String()
+}